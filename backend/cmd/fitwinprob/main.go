@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sc2-analytics/internal/analyzer/winprob"
+	"sc2-analytics/internal/parser"
+	"sc2-analytics/internal/repository"
+)
+
+// Fittet das Siegwahrscheinlichkeits-Modell aus den Replays in der Datenbank.
+// Das Ergebnis wird vom Server per -winprob-model geladen.
+func main() {
+	dbPath := flag.String("db", "./data/sc2analytics.db", "Pfad zur SQLite Datenbank")
+	uploadDir := flag.String("uploads", "./data/uploads", "Upload-Verzeichnis mit den Replay-Dateien")
+	out := flag.String("out", "./data/winprob.json", "Ausgabedatei für das Modell")
+	iterations := flag.Int("iterations", 2000, "Anzahl Iterationen des Gradientenabstiegs")
+	learningRate := flag.Float64("learning-rate", 0.1, "Lernrate des Gradientenabstiegs")
+	flag.Parse()
+
+	repo, err := repository.New(*dbPath)
+	if err != nil {
+		log.Fatalf("Konnte Datenbank nicht öffnen: %v", err)
+	}
+	defer repo.Close()
+
+	p := parser.New()
+	var samples []winprob.LabeledSample
+	games := 0
+
+	const pageSize = 100
+	for offset := 0; ; offset += pageSize {
+		replays, err := repo.ListReplays(pageSize, offset)
+		if err != nil {
+			log.Fatalf("Konnte Replays nicht laden: %v", err)
+		}
+		if len(replays) == 0 {
+			break
+		}
+
+		for _, replay := range replays {
+			path := filepath.Join(*uploadDir, "replays", replay.Hash+".SC2Replay")
+			parsed, err := p.ParseFile(path)
+			if err != nil {
+				log.Printf("Überspringe %s: %v", replay.Filename, err)
+				continue
+			}
+
+			// Nur 1v1 mit eindeutigem Ergebnis
+			if len(parsed.Players) != 2 {
+				continue
+			}
+			first, second := parsed.Players[0], parsed.Players[1]
+			if first.Result == second.Result || (first.Result != "Win" && first.Result != "Loss") {
+				continue
+			}
+
+			// Beide Perspektiven, damit das Modell symmetrisch bleibt
+			for _, pair := range [][2]parser.ParsedPlayer{{first, second}, {second, first}} {
+				for _, s := range winprob.ExtractSamples(parsed.Events, pair[0].Slot, pair[1].Slot, float64(parsed.Duration)) {
+					samples = append(samples, winprob.LabeledSample{
+						Features: s.Features,
+						Won:      pair[0].Result == "Win",
+					})
+				}
+			}
+			games++
+		}
+	}
+
+	if games == 0 {
+		log.Fatal("Keine auswertbaren 1v1-Replays gefunden")
+	}
+
+	fmt.Printf("Fitte Modell mit %d Samples aus %d Spielen...\n", len(samples), games)
+	model := winprob.Fit(samples, *iterations, *learningRate)
+	model.Games = games
+	model.FittedAt = time.Now()
+
+	if err := os.MkdirAll(filepath.Dir(*out), 0755); err != nil {
+		log.Fatalf("Konnte Ausgabe-Verzeichnis nicht erstellen: %v", err)
+	}
+	if err := winprob.SaveModel(*out, model); err != nil {
+		log.Fatalf("Konnte Modell nicht speichern: %v", err)
+	}
+
+	fmt.Printf("Intercept:    %8.4f\n", model.Intercept)
+	fmt.Printf("Armeewert:    %8.4f\n", model.ArmyLead)
+	fmt.Printf("Verluste:     %8.4f\n", model.LostDiff)
+	fmt.Printf("Worker:       %8.4f\n", model.WorkerDiff)
+	fmt.Printf("Basen:        %8.4f\n", model.BaseDiff)
+	fmt.Printf("Supply:       %8.4f\n", model.SupplyDiff)
+	fmt.Printf("Trefferquote: %7.1f%%\n", model.Accuracy)
+	fmt.Printf("Modell gespeichert: %s\n", *out)
+}
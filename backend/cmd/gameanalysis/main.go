@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"sc2-analytics/internal/analyzer"
+	"sc2-analytics/internal/analyzer/story"
+	"sc2-analytics/internal/i18n"
 	"sc2-analytics/internal/models"
 	"sc2-analytics/internal/parser"
 )
@@ -125,7 +127,17 @@ func main() {
 			} else if block.Severity == "high" {
 				severity = "SCHWER ⚠️"
 			}
-			fmt.Printf("  %s - %.0f Sekunden (%s)\n", formatTime(block.StartTime), block.Duration, severity)
+			cause := ""
+			switch block.Cause {
+			case "no_supply_started":
+				cause = "kein Supply im Bau"
+			case "supply_started_late":
+				cause = "Supply zu spät gestartet"
+			case "supply_provider_killed":
+				cause = "Supply-Gebäude verloren"
+			}
+			fmt.Printf("  %s - %.0f Sekunden (%s) – %s, ~%d Supply verloren\n",
+				formatTime(block.StartTime), block.Duration, severity, cause, block.EstimatedSupplyLost)
 		}
 		fmt.Println()
 	}
@@ -134,6 +146,14 @@ func main() {
 	fmt.Printf("━━━ KRITISCHE MOMENTE / KÄMPFE ━━━\n\n")
 	findCriticalMoments(replay, loser.Slot, winner.Slot)
 
+	// Spielverlauf
+	fmt.Printf("━━━ SPIELVERLAUF ━━━\n\n")
+	gameStory := story.NewStoryAnalyzer(i18n.DefaultLanguage).Analyze(replay, map[int]*models.AnalysisData{
+		loser.Slot:  loserAnalysis,
+		winner.Slot: winnerAnalysis,
+	})
+	printStory(gameStory.Entries, story.ImportanceNotable)
+
 	// Strategische Empfehlungen
 	fmt.Printf("╔══════════════════════════════════════════════════════════════╗\n")
 	fmt.Printf("║               WAS DU BESSER MACHEN KANNST                    ║\n")
@@ -151,11 +171,42 @@ func printBuildOrder(items []models.BuildOrderItem, limit int) {
 			fmt.Printf("  ... (und weitere)\n")
 			break
 		}
-		fmt.Printf("  %s [%d] %s %s\n", formatTime(item.Time), item.Supply, item.Action, item.UnitOrBuilding)
+		status := ""
+		switch item.Status {
+		case "cancelled":
+			status = " (abgebrochen)"
+			if item.Pattern == "extractor_trick" {
+				status = " (Extractor-Trick)"
+			}
+		case "destroyed":
+			status = " (im Bau zerstört)"
+		}
+		if item.IsProxy {
+			status += " [Proxy]"
+		}
+		fmt.Printf("  %s [%d] %s %s%s\n", formatTime(item.Time), item.Supply, item.Action, item.UnitOrBuilding, status)
 		count++
 	}
 }
 
+// printStory gibt die Einträge des Spielverlaufs ab der angegebenen Wichtigkeit aus
+func printStory(entries []models.StoryEntry, minImportance int) {
+	for _, e := range entries {
+		if e.Importance < minImportance {
+			continue
+		}
+		marker := "•"
+		if e.Importance >= story.ImportanceKey {
+			marker = "★"
+		}
+		fmt.Printf("  %6s %s %s\n", e.Label, marker, e.Title)
+		if e.Detail != "" {
+			fmt.Printf("  %6s   %s\n", "", e.Detail)
+		}
+	}
+	fmt.Println()
+}
+
 func formatTime(seconds float64) string {
 	mins := int(seconds) / 60
 	secs := int(seconds) % 60
@@ -9,7 +9,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"sc2-analytics/internal/analyzer/benchmark"
+	"sc2-analytics/internal/analyzer/rules"
+	"sc2-analytics/internal/analyzer/strategic"
+	"sc2-analytics/internal/analyzer/winprob"
 	"sc2-analytics/internal/api"
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
 	"sc2-analytics/internal/repository"
 )
 
@@ -19,6 +25,14 @@ func main() {
 	dbPath := flag.String("db", "./data/sc2analytics.db", "Pfad zur SQLite Datenbank")
 	uploadDir := flag.String("uploads", "./data/uploads", "Upload-Verzeichnis")
 	staticDir := flag.String("static", "./static", "Verzeichnis für statische Dateien (Frontend)")
+	apmResolution := flag.Float64("apm-resolution", 30, "Auflösung der APM-Timeline in Sekunden")
+	benchmarkFile := flag.String("benchmarks", "", "JSON-Datei mit Benchmark-Zielwerten pro Liga und Matchup (optional)")
+	winProbModel := flag.String("winprob-model", "", "JSON-Datei mit gefittetem Siegwahrscheinlichkeits-Modell (siehe cmd/fitwinprob, optional)")
+	rulesFile := flag.String("rules", "", "JSON-Datei mit Vorschlagsregeln, ergänzt bzw. ersetzt die Standard-Regeln (optional)")
+	matchupsFile := flag.String("matchups", "", "JSON-Datei mit Matchup-Tipps und Gegner-Strategien, ergänzt bzw. ersetzt die eingebaute Wissensbasis (optional)")
+	localesDir := flag.String("locales", "", "Verzeichnis mit zusätzlichen Übersetzungen (<sprache>.json), ergänzt bzw. überschreibt die mitgelieferten (optional)")
+	admins := flag.String("admins", "", "Emails (kommasepariert) registrierter Benutzer, die Admin-Rechte erhalten, z.B. für Referenz-Replays (optional)")
+	coaches := flag.String("coaches", "", "Emails (kommasepariert) registrierter Benutzer, die Referenz-Replays verwalten dürfen (optional)")
 	flag.Parse()
 
 	// Stelle sicher, dass Verzeichnisse existieren
@@ -36,8 +50,86 @@ func main() {
 	}
 	defer repo.Close()
 
+	// Rollen aus den Flags vergeben; Benutzer müssen bereits registriert sein
+	for role, emails := range map[string]string{models.RoleAdmin: *admins, models.RoleCoach: *coaches} {
+		for _, email := range strings.Split(emails, ",") {
+			email = strings.TrimSpace(email)
+			if email == "" {
+				continue
+			}
+			found, err := repo.SetUserRole(email, role)
+			if err != nil {
+				log.Fatalf("Konnte Rolle %s nicht vergeben: %v", role, err)
+			}
+			if !found {
+				log.Printf("Rolle %s: kein Benutzer mit Email %s registriert", role, email)
+			}
+		}
+	}
+
+	// Übersetzungen vor dem Handler laden, da Vorschlagsregeln je Sprache kompiliert werden
+	if *localesDir != "" {
+		count, err := i18n.LoadDir(*localesDir)
+		if err != nil {
+			log.Fatalf("Konnte Übersetzungen nicht laden: %v", err)
+		}
+		log.Printf("Übersetzungen geladen: %s (%d Dateien, Sprachen: %s)", *localesDir, count, strings.Join(i18n.Languages(), ", "))
+	}
+
 	// Erstelle Handler und Router
 	handler := api.NewHandler(repo, *uploadDir)
+	handler.SetAPMResolution(*apmResolution)
+	if *benchmarkFile != "" {
+		targets, err := benchmark.LoadTargets(*benchmarkFile)
+		if err != nil {
+			log.Fatalf("Konnte Benchmark-Zielwerte nicht laden: %v", err)
+		}
+		handler.SetBenchmarkTargets(targets)
+	}
+	if *winProbModel != "" {
+		model, err := winprob.LoadModel(*winProbModel)
+		if err != nil {
+			log.Fatalf("Konnte Siegwahrscheinlichkeits-Modell nicht laden: %v", err)
+		}
+		handler.SetWinProbabilityModel(model)
+	}
+	if *rulesFile != "" {
+		set, err := rules.LoadRules(*rulesFile)
+		if err != nil {
+			log.Fatalf("Konnte Vorschlagsregeln nicht laden: %v", err)
+		}
+		if err := handler.SetSuggestionRules(set); err != nil {
+			log.Fatalf("Ungültige Vorschlagsregeln: %v", err)
+		}
+		log.Printf("Vorschlagsregeln geladen: %s (%d Regeln)", *rulesFile, len(set.Rules))
+	}
+	if *matchupsFile != "" {
+		kb, err := strategic.LoadKnowledgeBase(*matchupsFile)
+		if err != nil {
+			log.Fatalf("Konnte Matchup-Wissensbasis nicht laden: %v", err)
+		}
+		handler.SetMatchupKnowledge(strategic.MergeKnowledgeBase(kb))
+		log.Printf("Matchup-Wissensbasis geladen: %s (%d Matchups, %d Strategien)", *matchupsFile, len(kb.Matchups), len(kb.Strategies))
+	}
+	// Analysen älterer Versionen aus den Replay-Dateien neu berechnen
+	if count, err := handler.ReanalyzeOutdated(); err != nil {
+		log.Printf("Konnte veraltete Analysen nicht neu berechnen: %v", err)
+	} else if count > 0 {
+		log.Printf("Veraltete Analysen neu berechnet: %d Replays", count)
+	}
+	// Fehlende strategische Analysen zugeordneter Replays vor dem Start nachholen
+	if count, err := handler.BackfillStrategicAnalyses(); err != nil {
+		log.Printf("Konnte strategische Analysen nicht nachholen: %v", err)
+	} else if count > 0 {
+		log.Printf("Strategische Analysen nachgeholt: %d", count)
+	}
+	// Bereits analysierte Spiele in den Ähnlichkeitsindex aufnehmen
+	if count, err := handler.BackfillGameSignatures(); err != nil {
+		log.Printf("Konnte Ähnlichkeitsindex nicht ergänzen: %v", err)
+	} else if count > 0 {
+		log.Printf("Ähnlichkeitsindex ergänzt: %d Spieler", count)
+	}
+
 	router := api.NewRouter(handler, repo)
 
 	// Statische Dateien servieren (für Production)
@@ -3,36 +3,80 @@ package analyzer
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"sc2-analytics/internal/analyzer/benchmark"
 	"sc2-analytics/internal/analyzer/builds"
 	"sc2-analytics/internal/analyzer/macro"
 	"sc2-analytics/internal/analyzer/micro"
+	"sc2-analytics/internal/analyzer/rules"
+	"sc2-analytics/internal/analyzer/scouting"
+	"sc2-analytics/internal/analyzer/winprob"
+	"sc2-analytics/internal/i18n"
 	"sc2-analytics/internal/models"
 	"sc2-analytics/internal/parser"
 )
 
 // Analyzer koordiniert alle Analyse-Module
 type Analyzer struct {
-	supplyAnalyzer   *macro.SupplyAnalyzer
-	spendingAnalyzer *macro.SpendingAnalyzer
-	injectAnalyzer   *macro.InjectAnalyzer
-	apmAnalyzer      *micro.APMAnalyzer
-	armyAnalyzer     *micro.ArmyAnalyzer
-	buildAnalyzer    *builds.BuildOrderAnalyzer
+	supplyAnalyzer    *macro.SupplyAnalyzer
+	spendingAnalyzer  *macro.SpendingAnalyzer
+	injectAnalyzer    *macro.InjectAnalyzer
+	larvaAnalyzer     *macro.LarvaAnalyzer
+	creepAnalyzer     *macro.CreepAnalyzer
+	apmAnalyzer       *micro.APMAnalyzer
+	armyAnalyzer      *micro.ArmyAnalyzer
+	hotkeyAnalyzer    *micro.HotkeyAnalyzer
+	attentionAnalyzer *micro.AttentionAnalyzer
+	harassAnalyzer    *micro.HarassmentAnalyzer
+	buildAnalyzer     *builds.BuildOrderAnalyzer
+	scoutingAnalyzer  *scouting.ScoutingAnalyzer
+	benchmarkAnalyzer *benchmark.BenchmarkAnalyzer
+	winProbAnalyzer   *winprob.WinProbabilityAnalyzer
+	ruleEngine        *rules.RuleEngine
 }
 
 // New erstellt einen neuen Analyzer
 func New() *Analyzer {
 	return &Analyzer{
-		supplyAnalyzer:   macro.NewSupplyAnalyzer(),
-		spendingAnalyzer: macro.NewSpendingAnalyzer(),
-		injectAnalyzer:   macro.NewInjectAnalyzer(),
-		apmAnalyzer:      micro.NewAPMAnalyzer(),
-		armyAnalyzer:     micro.NewArmyAnalyzer(),
-		buildAnalyzer:    builds.NewBuildOrderAnalyzer(),
+		supplyAnalyzer:    macro.NewSupplyAnalyzer(),
+		spendingAnalyzer:  macro.NewSpendingAnalyzer(),
+		injectAnalyzer:    macro.NewInjectAnalyzer(),
+		larvaAnalyzer:     macro.NewLarvaAnalyzer(),
+		creepAnalyzer:     macro.NewCreepAnalyzer(),
+		apmAnalyzer:       micro.NewAPMAnalyzer(),
+		armyAnalyzer:      micro.NewArmyAnalyzer(),
+		hotkeyAnalyzer:    micro.NewHotkeyAnalyzer(),
+		attentionAnalyzer: micro.NewAttentionAnalyzer(),
+		harassAnalyzer:    micro.NewHarassmentAnalyzer(),
+		buildAnalyzer:     builds.NewBuildOrderAnalyzer(),
+		scoutingAnalyzer:  scouting.NewScoutingAnalyzer(),
+		benchmarkAnalyzer: benchmark.NewBenchmarkAnalyzer(),
+		winProbAnalyzer:   winprob.NewWinProbabilityAnalyzer(),
+		ruleEngine:        rules.NewRuleEngine(),
 	}
 }
 
+// SetAPMResolution setzt die Fenstergröße der APM-Timeline in Sekunden
+func (a *Analyzer) SetAPMResolution(seconds float64) {
+	a.apmAnalyzer.SetResolution(seconds)
+}
+
+// SetBenchmarkTargets setzt konfigurierte Benchmark-Zielwerte (pro Liga und Matchup)
+func (a *Analyzer) SetBenchmarkTargets(targets []models.BenchmarkTarget) {
+	a.benchmarkAnalyzer.SetTargets(targets)
+}
+
+// SetWinProbabilityModel setzt ein offline gefittetes Siegwahrscheinlichkeits-Modell
+func (a *Analyzer) SetWinProbabilityModel(m models.WinProbabilityModel) {
+	a.winProbAnalyzer.SetModel(m)
+}
+
+// SetSuggestionRules ergänzt bzw. ersetzt die Standard-Vorschlagsregeln
+func (a *Analyzer) SetSuggestionRules(set models.SuggestionRuleSet) error {
+	return a.ruleEngine.SetRules(set)
+}
+
 // AnalyzePlayer führt alle Analysen für einen Spieler durch
 func (a *Analyzer) AnalyzePlayer(parsedReplay *parser.ParsedReplay, playerSlot int, race string) (*models.AnalysisData, error) {
 	if parsedReplay == nil || parsedReplay.Events == nil {
@@ -43,53 +87,92 @@ func (a *Analyzer) AnalyzePlayer(parsedReplay *parser.ParsedReplay, playerSlot i
 	events := parsedReplay.Events
 
 	data := &models.AnalysisData{
+		Version:     models.AnalysisVersion,
 		Suggestions: []models.Suggestion{},
 	}
 
 	// Supply Analyse
 	data.SupplyAnalysis = a.supplyAnalyzer.Analyze(events, playerSlot, gameDuration)
-	if data.SupplyAnalysis != nil {
-		suggestions := a.supplyAnalyzer.GenerateSuggestions(data.SupplyAnalysis)
-		data.Suggestions = append(data.Suggestions, suggestions...)
-	}
 
 	// Spending Analyse
-	data.SpendingAnalysis = a.spendingAnalyzer.Analyze(events, playerSlot, gameDuration)
-	if data.SpendingAnalysis != nil {
-		suggestions := a.spendingAnalyzer.GenerateSuggestions(data.SpendingAnalysis)
-		data.Suggestions = append(data.Suggestions, suggestions...)
-	}
+	data.SpendingAnalysis = a.spendingAnalyzer.Analyze(events, playerSlot, gameDuration, playerLeftAt(parsedReplay.Players, playerSlot))
 
 	// APM Analyse
 	data.APMAnalysis = a.apmAnalyzer.Analyze(events, playerSlot, gameDuration)
-	if data.APMAnalysis != nil {
-		suggestions := a.apmAnalyzer.GenerateSuggestions(data.APMAnalysis)
-		data.Suggestions = append(data.Suggestions, suggestions...)
-	}
 
 	// Build Order
 	data.BuildOrder = a.buildAnalyzer.Analyze(events, playerSlot)
 
 	// Inject Analyse (nur für Zerg)
 	data.InjectAnalysis = a.injectAnalyzer.Analyze(events, playerSlot, race, gameDuration)
-	if data.InjectAnalysis != nil {
-		suggestions := a.injectAnalyzer.GenerateSuggestions(data.InjectAnalysis)
-		data.Suggestions = append(data.Suggestions, suggestions...)
-	}
+
+	// Larven- und Queen-Analyse (nur für Zerg)
+	data.LarvaAnalysis = a.larvaAnalyzer.Analyze(events, playerSlot, race, gameDuration)
+
+	// Creep-Spread Analyse (nur für Zerg)
+	data.CreepAnalysis = a.creepAnalyzer.Analyze(events, playerSlot, race, gameDuration, parsedReplay.MapSizeX, parsedReplay.MapSizeY)
 
 	// Army Analyse
 	data.ArmyAnalysis = a.armyAnalyzer.Analyze(events, playerSlot, gameDuration)
-	if data.ArmyAnalysis != nil {
-		suggestions := a.armyAnalyzer.GenerateSuggestions(data.ArmyAnalysis)
-		data.Suggestions = append(data.Suggestions, suggestions...)
+
+	// Hotkey Analyse
+	data.HotkeyAnalysis = a.hotkeyAnalyzer.Analyze(events, playerSlot, gameDuration)
+
+	// Aufmerksamkeits-Analyse (Kamera)
+	data.AttentionAnalysis = a.attentionAnalyzer.Analyze(events, playerSlot, gameDuration)
+
+	// Harass- und Reaktionszeit-Analyse
+	data.HarassmentAnalysis = a.harassAnalyzer.Analyze(events, playerSlot, gameDuration)
+
+	// Scouting Analyse
+	data.ScoutingAnalysis = a.scoutingAnalyzer.Analyze(events, playerSlot, gameDuration)
+
+	// Benchmarks zu festen Zeitpunkten
+	matchup, league := playerContext(parsedReplay.Players, playerSlot)
+	data.BenchmarkAnalysis = a.benchmarkAnalyzer.Analyze(events, playerSlot, matchup, league, gameDuration)
+
+	// Siegwahrscheinlichkeit im Spielverlauf
+	if opponent := opponentSlot(parsedReplay.Players, playerSlot); opponent > 0 {
+		data.WinProbability = a.winProbAnalyzer.Analyze(events, playerSlot, opponent, gameDuration)
 	}
 
-	// Sortiere Vorschläge nach Priorität
+	// Vorschläge aus den konfigurierten Regeln, sortiert nach Priorität
+	data.Suggestions = append(data.Suggestions, a.ruleEngine.GenerateSuggestions(data, i18n.DefaultLanguage)...)
 	sortSuggestions(data.Suggestions)
 
 	return data, nil
 }
 
+// Localize übersetzt die generierten Texte einer gespeicherten Analyse
+// Analysen werden in der Standardsprache gespeichert; für andere Sprachen werden
+// Vorschläge neu aus den Regeln erzeugt und Anzeigenamen aus dem Katalog gesetzt
+func (a *Analyzer) Localize(data *models.AnalysisData, lang string) {
+	if data == nil || lang == "" || lang == i18n.DefaultLanguage {
+		return
+	}
+
+	data.Suggestions = a.ruleEngine.GenerateSuggestions(data, lang)
+	sortSuggestions(data.Suggestions)
+
+	if data.ScoutingAnalysis != nil {
+		for i := range data.ScoutingAnalysis.Windows {
+			w := &data.ScoutingAnalysis.Windows[i]
+			if w.Key == "" {
+				continue // vor Einführung der Schlüssel gespeichert
+			}
+			w.Name = i18n.T(lang, "scouting.window."+w.Key+".name")
+			w.Description = i18n.T(lang, "scouting.window."+w.Key+".description")
+		}
+	}
+
+	if data.WinProbability != nil {
+		for i := range data.WinProbability.TurningPoints {
+			tp := &data.WinProbability.TurningPoints[i]
+			tp.Description = winprob.DescribeTurningPoint(*tp, lang)
+		}
+	}
+}
+
 // AnalyzeAndStore analysiert und speichert die Ergebnisse
 func (a *Analyzer) AnalyzeAndStore(parsedReplay *parser.ParsedReplay, replayID int64, players []models.GamePlayer) (map[int64]*models.Analysis, error) {
 	results := make(map[int64]*models.Analysis)
@@ -134,7 +217,7 @@ func (a *Analyzer) GetPlayerMetrics(parsedReplay *parser.ParsedReplay, playerSlo
 	}
 
 	// SQ
-	spendingAnalysis := a.spendingAnalyzer.Analyze(parsedReplay.Events, playerSlot, gameDuration)
+	spendingAnalysis := a.spendingAnalyzer.Analyze(parsedReplay.Events, playerSlot, gameDuration, playerLeftAt(parsedReplay.Players, playerSlot))
 	if spendingAnalysis != nil {
 		sq = spendingAnalysis.SpendingQuotient
 	}
@@ -142,6 +225,54 @@ func (a *Analyzer) GetPlayerMetrics(parsedReplay *parser.ParsedReplay, playerSlo
 	return apm, sq
 }
 
+// opponentSlot gibt den Slot des ersten Gegners zurück (0 wenn keiner existiert)
+func opponentSlot(players []parser.ParsedPlayer, playerSlot int) int {
+	for _, p := range players {
+		if p.Slot != playerSlot {
+			return p.Slot
+		}
+	}
+	return 0
+}
+
+// playerLeftAt gibt zurück, wann ein Spieler das Spiel verlassen hat (0 = kein Leave-Event)
+func playerLeftAt(players []parser.ParsedPlayer, playerSlot int) float64 {
+	for _, p := range players {
+		if p.Slot == playerSlot {
+			return float64(p.LeftAt)
+		}
+	}
+	return 0
+}
+
+// playerContext ermittelt Matchup (z.B. "ZvT") und Liga eines Spielers
+func playerContext(players []parser.ParsedPlayer, playerSlot int) (matchup, league string) {
+	var own, opponent string
+	for _, p := range players {
+		if p.Slot == playerSlot {
+			own = raceLetter(p.Race)
+			league = p.League
+		} else if opponent == "" {
+			opponent = raceLetter(p.Race)
+		}
+	}
+	if own == "" {
+		return "", league
+	}
+	if opponent == "" {
+		opponent = "?"
+	}
+	return own + "v" + opponent, league
+}
+
+// raceLetter gibt den Anfangsbuchstaben einer Rasse zurück
+func raceLetter(race string) string {
+	if race == "" {
+		return ""
+	}
+	return strings.ToUpper(race[:1])
+}
+
 // sortSuggestions sortiert Vorschläge nach Priorität (high > medium > low)
 func sortSuggestions(suggestions []models.Suggestion) {
 	priorityOrder := map[string]int{
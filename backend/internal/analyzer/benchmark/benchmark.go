@@ -0,0 +1,233 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// BenchmarkAnalyzer hält den Spielstand zu festen Zeitpunkten fest und vergleicht ihn mit Zielwerten
+type BenchmarkAnalyzer struct {
+	targets []models.BenchmarkTarget
+}
+
+// NewBenchmarkAnalyzer erstellt einen neuen BenchmarkAnalyzer mit den Standard-Zielwerten
+func NewBenchmarkAnalyzer() *BenchmarkAnalyzer {
+	return &BenchmarkAnalyzer{targets: DefaultTargets()}
+}
+
+// SetTargets setzt zusätzliche Zielwerte, z.B. aus einer Konfigurationsdatei
+// Bei gleicher Spezifität haben sie Vorrang vor den Standard-Zielwerten
+func (ba *BenchmarkAnalyzer) SetTargets(targets []models.BenchmarkTarget) {
+	ba.targets = append(append([]models.BenchmarkTarget{}, targets...), DefaultTargets()...)
+}
+
+// LoadTargets liest Zielwerte aus einer JSON-Datei (Array von BenchmarkTarget)
+func LoadTargets(path string) ([]models.BenchmarkTarget, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("konnte Benchmark-Datei nicht lesen: %w", err)
+	}
+	var targets []models.BenchmarkTarget
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("ungültige Benchmark-Datei: %w", err)
+	}
+	for i := range targets {
+		targets[i].League = strings.ToLower(targets[i].League)
+	}
+	return targets, nil
+}
+
+// DefaultTargets gibt die eingebauten Zielwerte je Rasse zurück (ligaunabhängig)
+func DefaultTargets() []models.BenchmarkTarget {
+	perRace := map[string][]models.BenchmarkValues{
+		"Z": {
+			{Workers: 24, Supply: 30, Bases: 3},
+			{Workers: 33, Supply: 42, Bases: 3},
+			{Workers: 44, Supply: 58, Bases: 3},
+			{Workers: 55, Supply: 80, Bases: 3, UpgradesStarted: 1},
+			{Workers: 66, Supply: 130, Bases: 4, UpgradesStarted: 2},
+			{Workers: 75, Supply: 170, Bases: 5, UpgradesStarted: 3},
+		},
+		"T": {
+			{Workers: 22, Supply: 28, Bases: 2},
+			{Workers: 30, Supply: 40, Bases: 2},
+			{Workers: 38, Supply: 56, Bases: 3},
+			{Workers: 46, Supply: 74, Bases: 3, UpgradesStarted: 1},
+			{Workers: 60, Supply: 120, Bases: 3, UpgradesStarted: 2},
+			{Workers: 70, Supply: 160, Bases: 4, UpgradesStarted: 3},
+		},
+		"P": {
+			{Workers: 23, Supply: 30, Bases: 2},
+			{Workers: 31, Supply: 44, Bases: 2},
+			{Workers: 39, Supply: 60, Bases: 3},
+			{Workers: 47, Supply: 78, Bases: 3, UpgradesStarted: 1},
+			{Workers: 60, Supply: 124, Bases: 3, UpgradesStarted: 2},
+			{Workers: 70, Supply: 165, Bases: 4, UpgradesStarted: 3},
+		},
+	}
+
+	var targets []models.BenchmarkTarget
+	for race, values := range perRace {
+		for i, t := range models.BenchmarkTimes {
+			targets = append(targets, models.BenchmarkTarget{
+				Matchup: race + "v*",
+				Time:    t,
+				Values:  values[i],
+			})
+		}
+	}
+	return targets
+}
+
+// townHall ist ein eigenes Hauptgebäude
+type townHall struct {
+	done bool
+}
+
+// Analyze erstellt die Benchmark-Snapshots eines Spielers
+// matchup hat die Form "ZvT", league ist die Liga des Spielers (leer = unbekannt)
+func (ba *BenchmarkAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, matchup, league string, gameDuration float64) *models.BenchmarkAnalysis {
+	if events == nil {
+		return nil
+	}
+
+	analysis := &models.BenchmarkAnalysis{
+		League:    league,
+		Matchup:   matchup,
+		Snapshots: []models.BenchmarkSnapshot{},
+	}
+
+	townHalls := make(map[int]*townHall) // Unit-Tag -> Hauptgebäude
+	var current models.BenchmarkValues
+	next := 0
+
+	takeSnapshot := func(t float64, reached bool) {
+		values := current
+		values.Bases = countBases(townHalls)
+		analysis.Snapshots = append(analysis.Snapshots, models.BenchmarkSnapshot{
+			Time:    t,
+			Label:   parser.FormatTime(t),
+			Reached: reached,
+			Values:  values,
+		})
+	}
+
+	for _, evt := range events.TrackerEvents {
+		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+		for next < len(models.BenchmarkTimes) && timeSeconds > models.BenchmarkTimes[next] {
+			takeSnapshot(models.BenchmarkTimes[next], models.BenchmarkTimes[next] <= gameDuration)
+			next++
+		}
+
+		switch evt.EventType {
+		case "PlayerStats":
+			if evt.PlayerID != playerID {
+				continue
+			}
+			stats, _ := evt.Data["stats"].(map[string]interface{})
+			current.Supply = parser.GetInt(stats, "scoreValueFoodUsed") / 4096
+			current.Workers = parser.GetInt(stats, "scoreValueWorkersActiveCount")
+			current.ArmyValue = parser.GetInt(stats, "scoreValueMineralsUsedActiveForces") + parser.GetInt(stats, "scoreValueVespeneUsedActiveForces")
+			current.Income = float64(parser.GetInt(stats, "scoreValueMineralsCollectionRate") + parser.GetInt(stats, "scoreValueVespeneCollectionRate"))
+
+		case "UnitBorn":
+			// Start-Hauptgebäude entstehen fertig
+			if parser.GetInt(evt.Data, "controlPlayerId") == playerID && parser.IsTownHall(parser.GetString(evt.Data, "unitTypeName")) {
+				townHalls[parser.GetUnitTag(evt.Data)] = &townHall{done: true}
+			}
+
+		case "UnitInit":
+			if parser.GetInt(evt.Data, "controlPlayerId") == playerID && parser.IsTownHall(parser.GetString(evt.Data, "unitTypeName")) {
+				townHalls[parser.GetUnitTag(evt.Data)] = &townHall{}
+			}
+
+		case "UnitDone":
+			if hall, ok := townHalls[parser.GetUnitTag(evt.Data)]; ok {
+				hall.done = true
+			}
+
+		case "UnitDied":
+			delete(townHalls, parser.GetUnitTag(evt.Data))
+		}
+	}
+	for ; next < len(models.BenchmarkTimes); next++ {
+		t := models.BenchmarkTimes[next]
+		takeSnapshot(t, t <= gameDuration)
+	}
+
+	// Upgrades zählen ab ihrem Forschungsbeginn, auch wenn sie erst nach dem Zeitpunkt
+	// oder gar nicht mehr fertig wurden
+	research := parser.CollectResearch(events, playerID)
+	for i := range analysis.Snapshots {
+		for _, r := range research {
+			if r.Start <= analysis.Snapshots[i].Time {
+				analysis.Snapshots[i].Values.UpgradesStarted++
+			}
+		}
+	}
+
+	// Vergleich mit den Zielwerten
+	for i := range analysis.Snapshots {
+		snapshot := &analysis.Snapshots[i]
+		if !snapshot.Reached {
+			continue
+		}
+		if target := ba.findTarget(league, matchup, snapshot.Time); target != nil {
+			snapshot.Target = &target.Values
+			snapshot.Comparisons = snapshot.Values.Compare(target.Values)
+		}
+	}
+
+	return analysis
+}
+
+// findTarget wählt den spezifischsten Zielwert für Liga, Matchup und Zeitpunkt
+// Reihenfolge: Liga+Matchup, Liga+Rasse, Matchup, Rasse, allgemein
+func (ba *BenchmarkAnalyzer) findTarget(league, matchup string, t float64) *models.BenchmarkTarget {
+	var best *models.BenchmarkTarget
+	bestScore := -1
+	for i := range ba.targets {
+		target := &ba.targets[i]
+		if target.Time != t {
+			continue
+		}
+		score := 0
+		switch {
+		case target.League == "":
+		case target.League == league:
+			score += 4
+		default:
+			continue
+		}
+		switch {
+		case target.Matchup == "":
+		case strings.EqualFold(target.Matchup, matchup):
+			score += 2
+		case strings.HasSuffix(target.Matchup, "v*") && len(matchup) > 0 && strings.EqualFold(target.Matchup[:1], matchup[:1]):
+			score++
+		default:
+			continue
+		}
+		if score > bestScore {
+			best = target
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// countBases zählt die fertigen Hauptgebäude
+func countBases(townHalls map[int]*townHall) int {
+	count := 0
+	for _, hall := range townHalls {
+		if hall.done {
+			count++
+		}
+	}
+	return count
+}
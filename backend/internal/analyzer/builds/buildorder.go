@@ -1,6 +1,8 @@
 package builds
 
 import (
+	"math"
+
 	"sc2-analytics/internal/models"
 	"sc2-analytics/internal/parser"
 	"sort"
@@ -21,16 +23,37 @@ type buildEvent struct {
 	Supply         int
 	Action         string
 	UnitOrBuilding string
+	UnitType       string
+	Status         string
+	CompletedTime  float64
+	LostTime       float64
+	KilledBy       int
+	Pattern        string
+	IsProxy        bool
+}
+
+// position ist eine Kartenposition (in Map-Zellen)
+type position struct {
+	X, Y float64
 }
 
+const (
+	// proxyDistance ist der Mindestabstand zur nächsten eigenen Basis, ab dem ein Gebäude als Proxy gilt
+	proxyDistance = 40.0
+	// extractorTrickWindow ist das Zeitfenster, in dem ein abgebrochener Extractor als Extractor-Trick zählt
+	extractorTrickWindow = 20.0
+)
+
 // Analyze extrahiert die Build Order eines Spielers
 func (ba *BuildOrderAnalyzer) Analyze(events *parser.ParsedEvents, playerID int) []models.BuildOrderItem {
 	if events == nil {
 		return nil
 	}
 
-	var buildEvents []buildEvent
-	currentSupply := make(map[int]int) // playerID -> supply
+	var buildEvents []*buildEvent
+	currentSupply := make(map[int]int)   // playerID -> supply
+	pending := make(map[int]*buildEvent) // unitTag -> Gebäude im Bau
+	var townHalls []position             // eigene Basen (fertig oder im Bau)
 
 	for _, evt := range events.TrackerEvents {
 		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
@@ -49,13 +72,53 @@ func (ba *BuildOrderAnalyzer) Analyze(events *parser.ParsedEvents, playerID int)
 			}
 
 			unitType := getUnitTypeName(evt.Data)
-			if isBuilding(unitType) {
-				buildEvents = append(buildEvents, buildEvent{
+			if parser.IsBuilding(unitType) {
+				pos := getPosition(evt.Data)
+				be := &buildEvent{
 					Time:           timeSeconds,
 					Supply:         currentSupply[playerID],
 					Action:         "Build",
 					UnitOrBuilding: formatUnitName(unitType),
-				})
+					UnitType:       unitType,
+					Status:         "in_progress",
+				}
+
+				if parser.IsTownHall(unitType) {
+					townHalls = append(townHalls, pos)
+				} else if !isGasBuilding(unitType) && len(townHalls) > 0 {
+					be.IsProxy = distanceToNearest(pos, townHalls) > proxyDistance
+				}
+
+				buildEvents = append(buildEvents, be)
+				pending[parser.GetUnitTag(evt.Data)] = be
+			}
+
+		case "UnitDone":
+			// Gebäude fertiggestellt
+			tag := parser.GetUnitTag(evt.Data)
+			if be, ok := pending[tag]; ok {
+				be.Status = "completed"
+				be.CompletedTime = timeSeconds
+				delete(pending, tag)
+			}
+
+		case "UnitDied":
+			// Gebäude vor Fertigstellung abgebrochen oder zerstört
+			tag := parser.GetUnitTag(evt.Data)
+			be, ok := pending[tag]
+			if !ok {
+				continue
+			}
+			delete(pending, tag)
+
+			be.LostTime = timeSeconds
+			killer := getKillerPlayerID(evt.Data)
+			if killer == 0 || killer == playerID {
+				be.Status = "cancelled"
+				be.Pattern = classifyCancellation(be)
+			} else {
+				be.Status = "destroyed"
+				be.KilledBy = killer
 			}
 
 		case "UnitBorn":
@@ -66,18 +129,27 @@ func (ba *BuildOrderAnalyzer) Analyze(events *parser.ParsedEvents, playerID int)
 
 			unitType := getUnitTypeName(evt.Data)
 
+			// Start-Basis merken (für Proxy-Erkennung)
+			if parser.IsTownHall(unitType) {
+				townHalls = append(townHalls, getPosition(evt.Data))
+				continue
+			}
+
 			// Nur relevante Einheiten für Build Order
 			if isBuildOrderUnit(unitType) {
 				action := "Train"
-				if isWorker(unitType) {
+				if parser.IsWorker(unitType) {
 					action = "Train Worker"
 				}
 
-				buildEvents = append(buildEvents, buildEvent{
+				buildEvents = append(buildEvents, &buildEvent{
 					Time:           timeSeconds,
 					Supply:         currentSupply[playerID],
 					Action:         action,
 					UnitOrBuilding: formatUnitName(unitType),
+					UnitType:       unitType,
+					Status:         "completed",
+					CompletedTime:  timeSeconds,
 				})
 			}
 
@@ -88,19 +160,22 @@ func (ba *BuildOrderAnalyzer) Analyze(events *parser.ParsedEvents, playerID int)
 			}
 
 			upgradeName := getUpgradeName(evt.Data)
-			if upgradeName != "" && !isCosmetic(upgradeName) {
-				buildEvents = append(buildEvents, buildEvent{
+			if upgradeName != "" && !parser.IsCosmeticUpgrade(upgradeName) {
+				buildEvents = append(buildEvents, &buildEvent{
 					Time:           timeSeconds,
 					Supply:         currentSupply[playerID],
 					Action:         "Upgrade",
 					UnitOrBuilding: formatUpgradeName(upgradeName),
+					UnitType:       upgradeName,
+					Status:         "completed",
+					CompletedTime:  timeSeconds,
 				})
 			}
 		}
 	}
 
 	// Sortiere nach Zeit
-	sort.Slice(buildEvents, func(i, j int) bool {
+	sort.SliceStable(buildEvents, func(i, j int) bool {
 		return buildEvents[i].Time < buildEvents[j].Time
 	})
 
@@ -116,12 +191,45 @@ func (ba *BuildOrderAnalyzer) Analyze(events *parser.ParsedEvents, playerID int)
 			Supply:         be.Supply,
 			Action:         be.Action,
 			UnitOrBuilding: be.UnitOrBuilding,
+			Status:         be.Status,
+			CompletedTime:  be.CompletedTime,
+			LostTime:       be.LostTime,
+			KilledBy:       be.KilledBy,
+			Pattern:        be.Pattern,
+			IsProxy:        be.IsProxy,
 		})
 	}
 
 	return result
 }
 
+// classifyCancellation erkennt bekannte Abbruch-Muster
+func classifyCancellation(be *buildEvent) string {
+	lowerType := strings.ToLower(be.UnitType)
+
+	switch {
+	case strings.Contains(lowerType, "extractor") && be.LostTime-be.Time <= extractorTrickWindow:
+		// Extractor kurz angesetzt und abgebrochen, um über das Supply-Limit zu kommen
+		return "extractor_trick"
+	case parser.IsTownHall(be.UnitType):
+		// Basis angesetzt und abgebrochen (Fake oder geblockt)
+		return "fake_expansion"
+	}
+	return ""
+}
+
+// distanceToNearest berechnet den Abstand zur nächsten Position
+func distanceToNearest(pos position, others []position) float64 {
+	nearest := math.MaxFloat64
+	for _, o := range others {
+		d := math.Hypot(pos.X-o.X, pos.Y-o.Y)
+		if d < nearest {
+			nearest = d
+		}
+	}
+	return nearest
+}
+
 // getPlayerID extrahiert die Spieler-ID aus Stats-Events (ohne m_ Präfix)
 func getPlayerID(data map[string]interface{}) int {
 	if pid, ok := data["playerId"]; ok {
@@ -154,6 +262,19 @@ func getPlayerIDFromUnit(data map[string]interface{}) int {
 	return 0
 }
 
+// getKillerPlayerID extrahiert den Spieler, der die Einheit getötet hat (0 = keiner)
+func getKillerPlayerID(data map[string]interface{}) int {
+	return parser.GetInt(data, "killerPlayerId")
+}
+
+// getPosition extrahiert die Kartenposition aus Unit-Events
+func getPosition(data map[string]interface{}) position {
+	return position{
+		X: float64(parser.GetInt(data, "x")),
+		Y: float64(parser.GetInt(data, "y")),
+	}
+}
+
 // getSupplyUsed extrahiert das aktuelle Supply (ohne m_ Präfix)
 func getSupplyUsed(data map[string]interface{}) int {
 	statsRaw, ok := data["stats"]
@@ -199,55 +320,12 @@ func getUpgradeName(data map[string]interface{}) string {
 	return ""
 }
 
-// isCosmetic prüft ob es ein kosmetisches Upgrade ist
-func isCosmetic(upgradeName string) bool {
-	lowerName := strings.ToLower(upgradeName)
-	cosmetics := []string{"reward", "dance", "skin", "spray", "voice", "emote"}
-	for _, c := range cosmetics {
-		if strings.Contains(lowerName, c) {
-			return true
-		}
-	}
-	return false
-}
-
-// isBuilding prüft ob es ein Gebäude ist
-func isBuilding(unitType string) bool {
-	lowerType := strings.ToLower(unitType)
-
-	buildings := []string{
-		// Terran
-		"commandcenter", "orbitalcommand", "planetaryfortress",
-		"supplydepot", "barracks", "factory", "starport",
-		"engineeringbay", "armory", "ghostacademy", "fusioncore",
-		"bunker", "missileturret", "sensortower", "refinery",
-		"techlab", "reactor",
-		// Protoss
-		"nexus", "pylon", "gateway", "forge", "cyberneticscore",
-		"roboticsfacility", "roboticsbay", "stargate", "fleetbeacon",
-		"twilightcouncil", "templararchive", "darkshrine",
-		"photoncannon", "shieldbattery", "assimilator",
-		// Zerg
-		"hatchery", "lair", "hive", "spawningpool", "evolutionchamber",
-		"roachwarren", "banelingnest", "hydraliskden", "lurkerden",
-		"spire", "greaterspire", "infestationpit", "ultraliscavern",
-		"nydusnetwork", "spinecrawler", "sporecrawler", "extractor",
-	}
-
-	for _, b := range buildings {
-		if strings.Contains(lowerType, b) {
-			return true
-		}
-	}
-	return false
-}
-
-// isWorker prüft ob es ein Worker ist
-func isWorker(unitType string) bool {
+// isGasBuilding prüft ob es ein Gas-Gebäude ist
+func isGasBuilding(unitType string) bool {
 	lowerType := strings.ToLower(unitType)
-	return strings.Contains(lowerType, "scv") ||
-		strings.Contains(lowerType, "probe") ||
-		strings.Contains(lowerType, "drone")
+	return strings.Contains(lowerType, "refinery") ||
+		strings.Contains(lowerType, "assimilator") ||
+		strings.Contains(lowerType, "extractor")
 }
 
 // isBuildOrderUnit prüft ob die Einheit für Build Order relevant ist
@@ -0,0 +1,266 @@
+package compare
+
+import (
+	"math"
+	"sort"
+
+	"sc2-analytics/internal/models"
+)
+
+const (
+	// timelineInterval ist der Abstand (Sekunden) der gemeinsamen Zeitpunkte überlagerter Verläufe
+	timelineInterval = 10.0
+)
+
+// timelineMetrics sind die überlagerten Verläufe in Ausgabereihenfolge
+var timelineMetrics = []string{"supply", "army_value", "minerals", "gas", "income"}
+
+// ComparisonAnalyzer stellt zwei analysierte Spiele gegenüber
+type ComparisonAnalyzer struct{}
+
+// NewComparisonAnalyzer erstellt einen neuen ComparisonAnalyzer
+func NewComparisonAnalyzer() *ComparisonAnalyzer {
+	return &ComparisonAnalyzer{}
+}
+
+// Analyze vergleicht Spiel B mit Spiel A: Build Order, Benchmarks und Verläufe
+func (ca *ComparisonAnalyzer) Analyze(sideA, sideB models.ComparisonSide, a, b *models.AnalysisData) *models.ReplayComparison {
+	if a.BenchmarkAnalysis != nil && sideA.Matchup == "" {
+		sideA.Matchup = a.BenchmarkAnalysis.Matchup
+	}
+	if b.BenchmarkAnalysis != nil && sideB.Matchup == "" {
+		sideB.Matchup = b.BenchmarkAnalysis.Matchup
+	}
+
+	comparison := &models.ReplayComparison{
+		A:          sideA,
+		B:          sideB,
+		BuildOrder: alignBuildOrders(a.BuildOrder, b.BuildOrder),
+		Benchmarks: compareBenchmarks(a.BenchmarkAnalysis, b.BenchmarkAnalysis),
+	}
+	comparison.BuildSummary = summarizeBuildOrder(comparison.BuildOrder)
+
+	seriesA := timelineSeries(a)
+	seriesB := timelineSeries(b)
+	end := math.Max(float64(sideA.Duration), float64(sideB.Duration))
+	for _, metric := range timelineMetrics {
+		if len(seriesA[metric]) == 0 && len(seriesB[metric]) == 0 {
+			continue
+		}
+		timeline := models.ComparisonTimeline{Metric: metric, Points: []models.ComparisonPoint{}}
+		for t := timelineInterval; t <= end; t += timelineInterval {
+			point := models.ComparisonPoint{Time: t}
+			if t <= float64(sideA.Duration) {
+				point.A = valueAt(seriesA[metric], t)
+			}
+			if t <= float64(sideB.Duration) {
+				point.B = valueAt(seriesB[metric], t)
+			}
+			timeline.Points = append(timeline.Points, point)
+		}
+		comparison.Timelines = append(comparison.Timelines, timeline)
+	}
+
+	return comparison
+}
+
+// alignBuildOrders richtet beide Build Orders an der längsten gemeinsamen Schrittfolge aus
+// Abgebrochene und zerstörte Schritte zählen nicht, da sie keinen Build-Schritt darstellen
+func alignBuildOrders(a, b []models.BuildOrderItem) []models.BuildStepComparison {
+	a = effectiveSteps(a)
+	b = effectiveSteps(b)
+
+	// lcs[i][j] = Länge der gemeinsamen Folge von a[i:] und b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if stepKey(a[i]) == stepKey(b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	steps := []models.BuildStepComparison{}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		if i < len(a) && j < len(b) && stepKey(a[i]) == stepKey(b[j]) {
+			delta := b[j].Time - a[i].Time
+			steps = append(steps, models.BuildStepComparison{
+				Action:         a[i].Action,
+				UnitOrBuilding: a[i].UnitOrBuilding,
+				Status:         "matched",
+				TimeA:          floatPtr(a[i].Time),
+				TimeB:          floatPtr(b[j].Time),
+				SupplyA:        a[i].Supply,
+				SupplyB:        b[j].Supply,
+				Delta:          &delta,
+			})
+			i++
+			j++
+			continue
+		}
+
+		// Abweichenden Schritt überspringen, der die gemeinsame Folge erhält; bei Gleichstand den früheren
+		takeA := j >= len(b)
+		if i < len(a) && j < len(b) {
+			if lcs[i+1][j] != lcs[i][j+1] {
+				takeA = lcs[i+1][j] > lcs[i][j+1]
+			} else {
+				takeA = a[i].Time <= b[j].Time
+			}
+		}
+		if takeA {
+			steps = append(steps, models.BuildStepComparison{
+				Action:         a[i].Action,
+				UnitOrBuilding: a[i].UnitOrBuilding,
+				Status:         "only_a",
+				TimeA:          floatPtr(a[i].Time),
+				SupplyA:        a[i].Supply,
+			})
+			i++
+		} else {
+			steps = append(steps, models.BuildStepComparison{
+				Action:         b[j].Action,
+				UnitOrBuilding: b[j].UnitOrBuilding,
+				Status:         "only_b",
+				TimeB:          floatPtr(b[j].Time),
+				SupplyB:        b[j].Supply,
+			})
+			j++
+		}
+	}
+	return steps
+}
+
+// effectiveSteps filtert abgebrochene und zerstörte Schritte heraus
+func effectiveSteps(items []models.BuildOrderItem) []models.BuildOrderItem {
+	var steps []models.BuildOrderItem
+	for _, item := range items {
+		if item.Status == "cancelled" || item.Status == "destroyed" {
+			continue
+		}
+		steps = append(steps, item)
+	}
+	return steps
+}
+
+// stepKey identifiziert gleiche Schritte beider Build Orders
+func stepKey(item models.BuildOrderItem) string {
+	return item.Action + ":" + item.UnitOrBuilding
+}
+
+// summarizeBuildOrder zählt gemeinsame und abweichende Schritte
+func summarizeBuildOrder(steps []models.BuildStepComparison) models.BuildOrderDiffSummary {
+	var summary models.BuildOrderDiffSummary
+	var deltaSum float64
+	for _, step := range steps {
+		switch step.Status {
+		case "matched":
+			summary.Matched++
+			deltaSum += *step.Delta
+			continue
+		case "only_a":
+			summary.OnlyA++
+		case "only_b":
+			summary.OnlyB++
+		}
+		if summary.FirstDivergence == nil {
+			t := step.TimeA
+			if t == nil {
+				t = step.TimeB
+			}
+			summary.FirstDivergence = floatPtr(*t)
+		}
+	}
+	if summary.Matched > 0 {
+		summary.AverageDelta = math.Round(deltaSum/float64(summary.Matched)*10) / 10
+	}
+	return summary
+}
+
+// compareBenchmarks stellt die Benchmark-Snapshots beider Spiele nach Zeitpunkt gegenüber
+func compareBenchmarks(a, b *models.BenchmarkAnalysis) []models.BenchmarkDelta {
+	deltas := []models.BenchmarkDelta{}
+	if a == nil || b == nil {
+		return deltas
+	}
+
+	snapshotsB := make(map[float64]models.BenchmarkSnapshot)
+	for _, s := range b.Snapshots {
+		snapshotsB[s.Time] = s
+	}
+
+	for _, sa := range a.Snapshots {
+		sb, ok := snapshotsB[sa.Time]
+		if !ok {
+			continue
+		}
+		delta := models.BenchmarkDelta{
+			Time:     sa.Time,
+			Label:    sa.Label,
+			ReachedA: sa.Reached,
+			ReachedB: sb.Reached,
+		}
+		if sa.Reached && sb.Reached {
+			valuesA := sa.Values.ByMetric()
+			valuesB := sb.Values.ByMetric()
+			for _, metric := range models.BenchmarkMetrics {
+				delta.Metrics = append(delta.Metrics, models.MetricDelta{
+					Metric: metric,
+					A:      valuesA[metric],
+					B:      valuesB[metric],
+					Delta:  math.Round((valuesB[metric]-valuesA[metric])*10) / 10,
+				})
+			}
+		}
+		deltas = append(deltas, delta)
+	}
+	return deltas
+}
+
+// timedValue ist ein Messpunkt eines Verlaufs
+type timedValue struct {
+	time  float64
+	value float64
+}
+
+// timelineSeries liest die Verläufe eines Spiels aus seiner Analyse
+func timelineSeries(data *models.AnalysisData) map[string][]timedValue {
+	series := make(map[string][]timedValue)
+	if data.SupplyAnalysis != nil {
+		for _, p := range data.SupplyAnalysis.SupplyTimeline {
+			series["supply"] = append(series["supply"], timedValue{p.Time, float64(p.SupplyUsed)})
+		}
+	}
+	if data.ArmyAnalysis != nil {
+		for _, p := range data.ArmyAnalysis.ArmyTimeline {
+			series["army_value"] = append(series["army_value"], timedValue{p.Time, float64(p.Value)})
+		}
+	}
+	if data.SpendingAnalysis != nil {
+		for _, p := range data.SpendingAnalysis.ResourceTimeline {
+			series["minerals"] = append(series["minerals"], timedValue{p.Time, float64(p.Minerals)})
+			series["gas"] = append(series["gas"], timedValue{p.Time, float64(p.Gas)})
+			series["income"] = append(series["income"], timedValue{p.Time, math.Round(p.Income.Minerals + p.Income.Gas)})
+		}
+	}
+	return series
+}
+
+// valueAt gibt den letzten bekannten Wert bis zum Zeitpunkt t zurück (nil vor dem ersten Messpunkt)
+func valueAt(series []timedValue, t float64) *float64 {
+	n := sort.Search(len(series), func(i int) bool { return series[i].time > t })
+	if n == 0 {
+		return nil
+	}
+	return floatPtr(series[n-1].value)
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
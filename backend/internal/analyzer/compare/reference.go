@@ -0,0 +1,66 @@
+package compare
+
+import (
+	"math"
+
+	"sc2-analytics/internal/models"
+)
+
+const (
+	// gapThreshold ist der Rückstand (Anteil des Referenzwerts), ab dem ein Zeitpunkt als Rückstand zählt
+	gapThreshold = 0.15
+	// gapMinDuration ist die Mindestdauer (Sekunden) eines gemeldeten Rückstands
+	gapMinDuration = 30.0
+)
+
+// gapMetrics sind die Kurven, deren Rückstand gegenüber der Referenz gemeldet wird,
+// mit dem Referenzwert, ab dem ein Rückstand aussagekräftig ist
+var gapMetrics = []struct {
+	metric   string
+	minValue float64
+}{
+	{"income", 300},
+	{"supply", 12},
+	{"army_value", 400},
+}
+
+// ReferenceGaps findet Zeiträume, in denen Wirtschaft, Supply oder Armee von Spiel A
+// deutlich hinter der Referenz B liegen
+func (ca *ComparisonAnalyzer) ReferenceGaps(comparison *models.ReplayComparison) []models.ReferenceGap {
+	timelines := make(map[string]models.ComparisonTimeline)
+	for _, t := range comparison.Timelines {
+		timelines[t.Metric] = t
+	}
+
+	gaps := []models.ReferenceGap{}
+	for _, gm := range gapMetrics {
+		var current *models.ReferenceGap
+		flush := func() {
+			if current != nil && current.End-current.Start >= gapMinDuration {
+				gaps = append(gaps, *current)
+			}
+			current = nil
+		}
+
+		for _, p := range timelines[gm.metric].Points {
+			if p.A == nil || p.B == nil || *p.B < gm.minValue || *p.A >= *p.B*(1-gapThreshold) {
+				flush()
+				continue
+			}
+			deficit := *p.B - *p.A
+			if current == nil {
+				current = &models.ReferenceGap{Metric: gm.metric, Start: p.Time}
+			}
+			current.End = p.Time
+			if deficit > current.Deficit {
+				current.PeakTime = p.Time
+				current.Value = *p.A
+				current.ReferenceValue = *p.B
+				current.Deficit = deficit
+				current.Percent = math.Round(deficit / *p.B * 100)
+			}
+		}
+		flush()
+	}
+	return gaps
+}
@@ -0,0 +1,241 @@
+package gamestate
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// unit ist eine lebende Einheit bzw. ein Gebäude während der Rekonstruktion
+type unit struct {
+	owner    int
+	unitType string
+	done     bool
+}
+
+// Reconstruct rekonstruiert den Spielstand beider Spieler zum angegebenen Loop
+// Einheiten und Gebäude kommen aus den Unit-Events, Supply, Bank, Einkommen und
+// Armeewert aus dem letzten PlayerStats-Event vor dem Zeitpunkt
+func Reconstruct(replay *parser.ParsedReplay, loop int) *models.GameState {
+	t := parser.LoopsToRealSeconds(loop)
+	state := &models.GameState{
+		Time:    t,
+		Label:   parser.FormatTime(t),
+		Loop:    loop,
+		Players: []models.PlayerState{},
+	}
+
+	players := make(map[int]*models.PlayerState)
+	for _, p := range replay.Players {
+		state.Players = append(state.Players, models.PlayerState{
+			PlayerSlot:         p.Slot,
+			Name:               p.Name,
+			Race:               p.Race,
+			Units:              []models.UnitTypeCount{},
+			Structures:         []models.UnitTypeCount{},
+			UpgradesDone:       []string{},
+			UpgradesInProgress: []models.UpgradeProgress{},
+		})
+	}
+	for i := range state.Players {
+		players[state.Players[i].PlayerSlot] = &state.Players[i]
+	}
+
+	if replay.Events == nil {
+		return state
+	}
+
+	units := make(map[int]*unit) // Unit-Tag -> Einheit
+
+	for _, evt := range replay.Events.TrackerEvents {
+		if evt.Loop > loop {
+			break
+		}
+
+		switch evt.EventType {
+		case "PlayerStats":
+			ps, ok := players[evt.PlayerID]
+			if !ok {
+				continue
+			}
+			stats, _ := evt.Data["stats"].(map[string]interface{})
+			ps.Supply = parser.GetInt(stats, "scoreValueFoodUsed") / 4096
+			ps.SupplyCap = parser.GetInt(stats, "scoreValueFoodMade") / 4096
+			ps.Workers = parser.GetInt(stats, "scoreValueWorkersActiveCount")
+			ps.Minerals = parser.GetInt(stats, "scoreValueMineralsCurrent")
+			ps.Gas = parser.GetInt(stats, "scoreValueVespeneCurrent")
+			ps.MineralIncome = parser.GetInt(stats, "scoreValueMineralsCollectionRate")
+			ps.GasIncome = parser.GetInt(stats, "scoreValueVespeneCollectionRate")
+			ps.ArmyValue = parser.GetInt(stats, "scoreValueMineralsUsedActiveForces") + parser.GetInt(stats, "scoreValueVespeneUsedActiveForces")
+
+		case "UnitBorn":
+			owner := parser.GetInt(evt.Data, "controlPlayerId")
+			if _, ok := players[owner]; !ok {
+				continue
+			}
+			units[parser.GetUnitTag(evt.Data)] = &unit{
+				owner:    owner,
+				unitType: parser.GetString(evt.Data, "unitTypeName"),
+				done:     true,
+			}
+
+		case "UnitInit":
+			// Gebäude im Bau und Warp-Ins
+			owner := parser.GetInt(evt.Data, "controlPlayerId")
+			if _, ok := players[owner]; !ok {
+				continue
+			}
+			units[parser.GetUnitTag(evt.Data)] = &unit{
+				owner:    owner,
+				unitType: parser.GetString(evt.Data, "unitTypeName"),
+			}
+
+		case "UnitDone":
+			if u, ok := units[parser.GetUnitTag(evt.Data)]; ok {
+				u.done = true
+			}
+
+		case "UnitTypeChange":
+			if u, ok := units[parser.GetUnitTag(evt.Data)]; ok {
+				u.unitType = parser.GetString(evt.Data, "unitTypeName")
+			}
+
+		case "UnitDied":
+			delete(units, parser.GetUnitTag(evt.Data))
+		}
+	}
+
+	// Einheiten und Gebäude pro Typ zählen
+	unitCounts := make(map[int]map[string]*models.UnitTypeCount)
+	structureCounts := make(map[int]map[string]*models.UnitTypeCount)
+	for slot := range players {
+		unitCounts[slot] = make(map[string]*models.UnitTypeCount)
+		structureCounts[slot] = make(map[string]*models.UnitTypeCount)
+	}
+	for _, u := range units {
+		counts := unitCounts[u.owner]
+		if parser.IsBuilding(u.unitType) || strings.Contains(strings.ToLower(u.unitType), "creeptumor") {
+			counts = structureCounts[u.owner]
+		}
+		c, ok := counts[u.unitType]
+		if !ok {
+			c = &models.UnitTypeCount{UnitType: u.unitType}
+			counts[u.unitType] = c
+		}
+		if u.done {
+			c.Count++
+		} else {
+			c.InProgress++
+		}
+	}
+	for slot, ps := range players {
+		ps.Units = sortedCounts(unitCounts[slot])
+		ps.Structures = sortedCounts(structureCounts[slot])
+	}
+
+	// Upgrades werden separat ausgewertet, da laufende Upgrades erst nach dem Zeitpunkt fertig werden
+	// Fertige Upgrades bis zum Zeitpunkt
+	for _, evt := range replay.Events.TrackerEvents {
+		if evt.EventType != "Upgrade" || evt.Loop > loop {
+			continue
+		}
+		ps, ok := players[evt.PlayerID]
+		if !ok {
+			continue
+		}
+		if name := parser.GetString(evt.Data, "upgradeTypeName"); name != "" && !parser.IsCosmeticUpgrade(name) {
+			ps.UpgradesDone = append(ps.UpgradesDone, name)
+		}
+	}
+
+	// Laufende Forschungen ab ihrem Forschungsbefehl, auch wenn sie bei Spielende nicht fertig waren
+	for slot, ps := range players {
+		for _, r := range parser.CollectResearch(replay.Events, slot) {
+			if r.Start > t || (r.Done > 0 && r.Done <= t) {
+				continue
+			}
+			progress := models.UpgradeProgress{
+				Name:      r.Upgrade,
+				Structure: r.Structure,
+				Started:   r.Start,
+				Completes: r.Done,
+			}
+			if r.Done > r.Start {
+				progress.Progress = (t - r.Start) / (r.Done - r.Start) * 100
+			}
+			ps.UpgradesInProgress = append(ps.UpgradesInProgress, progress)
+		}
+	}
+
+	return state
+}
+
+// TimeError ist ein ungültiger Zeitpunkt; Key ist der Katalog-Schlüssel der Meldung,
+// Value der eingegebene Wert (Platzhalter {value})
+type TimeError struct {
+	Key   string
+	Value string
+}
+
+// Error liefert die Meldung in der Standardsprache
+func (e *TimeError) Error() string {
+	return i18n.T(i18n.DefaultLanguage, e.Key, e.Params())
+}
+
+// Params liefert die Platzhalter-Werte der Meldung
+func (e *TimeError) Params() i18n.Params {
+	return i18n.Params{"value": e.Value}
+}
+
+// ParseTime liest einen Zeitpunkt als m:ss bzw. mm:ss oder als Sekunden
+// Fehler sind vom Typ *TimeError, damit sie in der Sprache der Anfrage gemeldet werden können
+func ParseTime(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, &TimeError{Key: "error.time_missing"}
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 2 {
+		return 0, &TimeError{Key: "error.time_format", Value: value}
+	}
+	if len(parts) == 1 {
+		seconds, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil || seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			return 0, &TimeError{Key: "error.time_format", Value: value}
+		}
+		return seconds, nil
+	}
+
+	minutes, err := strconv.Atoi(parts[0])
+	if err != nil || minutes < 0 {
+		return 0, &TimeError{Key: "error.time_minutes", Value: value}
+	}
+	seconds, err := strconv.Atoi(parts[1])
+	if err != nil || seconds < 0 || seconds > 59 || len(parts[1]) != 2 {
+		return 0, &TimeError{Key: "error.time_seconds", Value: value}
+	}
+	return float64(minutes*60 + seconds), nil
+}
+
+// sortedCounts sortiert Zählungen absteigend nach Anzahl, dann nach Name
+func sortedCounts(counts map[string]*models.UnitTypeCount) []models.UnitTypeCount {
+	result := make([]models.UnitTypeCount, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ti := result[i].Count + result[i].InProgress
+		tj := result[j].Count + result[j].InProgress
+		if ti != tj {
+			return ti > tj
+		}
+		return result[i].UnitType < result[j].UnitType
+	})
+	return result
+}
@@ -0,0 +1,64 @@
+package gamestate
+
+import (
+	"errors"
+	"testing"
+
+	"sc2-analytics/internal/i18n"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr string // Katalog-Schlüssel des Fehlers, leer = kein Fehler
+	}{
+		{"5:30", 330, ""},
+		{"05:30", 330, ""},
+		{"0:00", 0, ""},
+		{"12:07", 727, ""},
+		{"90:00", 5400, ""},
+		{" 3:15 ", 195, ""},
+		{"330", 330, ""},
+		{"12.5", 12.5, ""},
+		{"", 0, "error.time_missing"},
+		{"   ", 0, "error.time_missing"},
+		{"5:60", 0, "error.time_seconds"},
+		{"5:7", 0, "error.time_seconds"},
+		{"5:007", 0, "error.time_seconds"},
+		{"-1:30", 0, "error.time_minutes"},
+		{"5:-1", 0, "error.time_seconds"},
+		{"1:02:03", 0, "error.time_format"},
+		{"abc", 0, "error.time_format"},
+		{"a:30", 0, "error.time_minutes"},
+		{"-5", 0, "error.time_format"},
+		{"NaN", 0, "error.time_format"},
+		{"Inf", 0, "error.time_format"},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value)
+		if tt.wantErr != "" {
+			var timeErr *TimeError
+			if !errors.As(err, &timeErr) || timeErr.Key != tt.wantErr {
+				t.Errorf("ParseTime(%q) error = %v, want %s", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTime(%q) error = %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestTimeErrorTranslations(t *testing.T) {
+	for _, key := range []string{"error.time_missing", "error.time_format", "error.time_minutes", "error.time_seconds"} {
+		de, en := i18n.T("de", key), i18n.T("en", key)
+		if de == key || en == de {
+			t.Errorf("%s: missing translation (de %q, en %q)", key, de, en)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package macro
+
+import (
+	"math"
+	"strings"
+
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// CreepAnalyzer analysiert den Creep-Spread von Zerg-Spielern
+type CreepAnalyzer struct{}
+
+// NewCreepAnalyzer erstellt einen neuen CreepAnalyzer
+func NewCreepAnalyzer() *CreepAnalyzer {
+	return &CreepAnalyzer{}
+}
+
+const (
+	// tumorCreepRadius ist der Creep-Radius eines Tumors in Map-Zellen
+	tumorCreepRadius = 10
+	// hatcheryCreepRadius ist der Creep-Radius einer Hatchery in Map-Zellen
+	hatcheryCreepRadius = 12
+	// tumorSpreadRange ist die maximale Reichweite, in der ein Tumor einen neuen setzen kann
+	tumorSpreadRange = 10.0
+	// creepSampleInterval ist der Abstand (Sekunden) der Creep-Timeline
+	creepSampleInterval = 30.0
+	// creepGapThreshold: Pausen ohne neuen Tumor ab dieser Dauer werden gemeldet
+	creepGapThreshold = 45.0
+)
+
+// creepHatchery ist eine Hatchery, die Creep erzeugt, sobald sie fertig ist
+type creepHatchery struct {
+	x, y    float64
+	painted bool
+}
+
+// creepTumor ist ein platzierter Creep-Tumor
+type creepTumor struct {
+	x, y  float64
+	depth int
+	alive bool
+}
+
+// creepGrid zählt pro Map-Zelle die Creep-Quellen
+type creepGrid struct {
+	width, height int
+	counts        []int
+	covered       int
+}
+
+// newCreepGrid erstellt ein Creep-Raster in Map-Größe
+func newCreepGrid(width, height int) *creepGrid {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	return &creepGrid{width: width, height: height, counts: make([]int, width*height)}
+}
+
+// paint fügt eine kreisförmige Creep-Quelle hinzu (delta = 1) oder entfernt sie (delta = -1)
+func (g *creepGrid) paint(cx, cy float64, radius int, delta int) {
+	if g == nil {
+		return
+	}
+	r2 := float64(radius * radius)
+	for y := int(cy) - radius; y <= int(cy)+radius; y++ {
+		if y < 0 || y >= g.height {
+			continue
+		}
+		for x := int(cx) - radius; x <= int(cx)+radius; x++ {
+			if x < 0 || x >= g.width {
+				continue
+			}
+			dx, dy := float64(x)-cx, float64(y)-cy
+			if dx*dx+dy*dy > r2 {
+				continue
+			}
+			i := y*g.width + x
+			before := g.counts[i]
+			g.counts[i] += delta
+			if before == 0 && g.counts[i] > 0 {
+				g.covered++
+			} else if before > 0 && g.counts[i] == 0 {
+				g.covered--
+			}
+		}
+	}
+}
+
+// coverage gibt den Anteil der Map mit Creep in Prozent zurück
+func (g *creepGrid) coverage() float64 {
+	if g == nil {
+		return 0
+	}
+	return float64(g.covered) / float64(g.width*g.height) * 100
+}
+
+// Analyze analysiert den Creep-Spread für Zerg-Spieler
+func (ca *CreepAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, race string, gameDuration float64, mapSizeX, mapSizeY int) *models.CreepAnalysis {
+	// Nur für Zerg relevant
+	if strings.ToLower(race) != "zerg" {
+		return nil
+	}
+
+	if events == nil {
+		return nil
+	}
+
+	analysis := &models.CreepAnalysis{
+		SpreadGaps: []models.CreepGap{},
+		Timeline:   []models.CreepPoint{},
+		Phases:     []models.CreepPhase{},
+	}
+
+	grid := newCreepGrid(mapSizeX, mapSizeY)
+	tumors := make(map[int]*creepTumor) // unitTagIndex -> Tumor
+	var tumorOrder []*creepTumor
+	var placements []float64
+	hatcheries := make(map[int]*creepHatchery) // unitTagIndex -> Hatchery
+	activeTumors := 0
+	nextSample := 0.0
+
+	sample := func(t float64) {
+		coverage := grid.coverage()
+		if coverage > analysis.PeakCoverage {
+			analysis.PeakCoverage = coverage
+		}
+		analysis.Timeline = append(analysis.Timeline, models.CreepPoint{
+			Time:         t,
+			ActiveTumors: activeTumors,
+			TotalTumors:  analysis.TotalTumors,
+			Coverage:     coverage,
+		})
+	}
+
+	for _, evt := range events.TrackerEvents {
+		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+		for nextSample <= timeSeconds {
+			sample(nextSample)
+			nextSample += creepSampleInterval
+		}
+
+		index := getUnitTagInject(evt.Data)
+
+		switch evt.EventType {
+		case "UnitInit", "UnitBorn":
+			if getUnitPlayerIDInject(evt.Data) != playerID {
+				continue
+			}
+			unitType := getUnitTypeNameInject(evt.Data)
+			x, y := float64(parser.GetInt(evt.Data, "x")), float64(parser.GetInt(evt.Data, "y"))
+
+			switch {
+			case isHatcheryType(unitType):
+				if _, exists := hatcheries[index]; exists {
+					continue
+				}
+				h := &creepHatchery{x: x, y: y}
+				hatcheries[index] = h
+				// Start-Hatchery hat sofort Creep, gebaute erst mit UnitDone
+				if evt.EventType == "UnitBorn" {
+					h.painted = true
+					grid.paint(x, y, hatcheryCreepRadius, 1)
+				}
+
+			case strings.HasPrefix(strings.ToLower(unitType), "creeptumor"):
+				// Ein Tumor durchläuft mehrere Typen (CreepTumor -> CreepTumorBurrowed), nur einmal zählen
+				if _, exists := tumors[index]; exists {
+					continue
+				}
+				tumor := &creepTumor{x: x, y: y, alive: true}
+				if strings.EqualFold(unitType, "CreepTumorQueen") {
+					analysis.QueenTumors++
+				} else {
+					analysis.SpreadTumors++
+					// Kette: nächster lebender Tumor in Reichweite ist der Ursprung
+					if parent := nearestTumor(tumorOrder, x, y); parent != nil {
+						tumor.depth = parent.depth + 1
+					}
+				}
+				if tumor.depth > analysis.MaxChainDepth {
+					analysis.MaxChainDepth = tumor.depth
+				}
+
+				tumors[index] = tumor
+				tumorOrder = append(tumorOrder, tumor)
+				placements = append(placements, timeSeconds)
+				analysis.TotalTumors++
+				activeTumors++
+				grid.paint(x, y, tumorCreepRadius, 1)
+			}
+
+		case "UnitDone":
+			if h, ok := hatcheries[index]; ok && !h.painted {
+				h.painted = true
+				grid.paint(h.x, h.y, hatcheryCreepRadius, 1)
+			}
+
+		case "UnitDied":
+			if tumor, ok := tumors[index]; ok && tumor.alive {
+				tumor.alive = false
+				activeTumors--
+				grid.paint(tumor.x, tumor.y, tumorCreepRadius, -1)
+			}
+			if h, ok := hatcheries[index]; ok {
+				if h.painted {
+					grid.paint(h.x, h.y, hatcheryCreepRadius, -1)
+				}
+				delete(hatcheries, index)
+			}
+		}
+	}
+	for nextSample <= gameDuration {
+		sample(nextSample)
+		nextSample += creepSampleInterval
+	}
+	if n := len(analysis.Timeline); n == 0 || analysis.Timeline[n-1].Time < gameDuration {
+		sample(gameDuration)
+	}
+	analysis.FinalCoverage = grid.coverage()
+
+	// Spread-Rhythmus: Pausen zwischen Tumor-Platzierungen
+	if len(placements) > 0 {
+		analysis.FirstTumorTime = placements[0]
+	}
+	if len(placements) > 1 {
+		var totalGap float64
+		for i := 1; i < len(placements); i++ {
+			gap := placements[i] - placements[i-1]
+			totalGap += gap
+			if gap > analysis.LongestSpreadGap {
+				analysis.LongestSpreadGap = gap
+			}
+			if gap >= creepGapThreshold {
+				analysis.SpreadGaps = append(analysis.SpreadGaps, models.CreepGap{
+					Start:    placements[i-1],
+					End:      placements[i],
+					Duration: gap,
+				})
+			}
+		}
+		analysis.AverageSpreadGap = totalGap / float64(len(placements)-1)
+	}
+
+	// Phasen
+	for _, phase := range models.GamePhases(gameDuration) {
+		p := models.CreepPhase{Phase: phase.Name, Start: phase.Start, End: phase.End}
+		for _, t := range placements {
+			if phase.Contains(t) {
+				p.TumorsPlaced++
+			}
+		}
+		var sum float64
+		count := 0
+		for _, point := range analysis.Timeline {
+			if point.Time >= p.Start && point.Time <= p.End {
+				sum += point.Coverage
+				count++
+				p.EndCoverage = point.Coverage
+			}
+		}
+		if count > 0 {
+			p.AverageCoverage = sum / float64(count)
+		}
+		analysis.Phases = append(analysis.Phases, p)
+	}
+
+	return analysis
+}
+
+// nearestTumor findet den nächsten lebenden Tumor in Spread-Reichweite
+func nearestTumor(tumors []*creepTumor, x, y float64) *creepTumor {
+	var nearest *creepTumor
+	bestDist := tumorSpreadRange
+	for _, t := range tumors {
+		if !t.alive {
+			continue
+		}
+		if d := math.Hypot(t.x-x, t.y-y); d <= bestDist {
+			bestDist = d
+			nearest = t
+		}
+	}
+	return nearest
+}
@@ -205,42 +205,3 @@ func isInjectAbilityID(id int) bool {
 	}
 	return false
 }
-
-// GenerateSuggestions erstellt Verbesserungsvorschläge für Injects
-func (ia *InjectAnalyzer) GenerateSuggestions(analysis *models.InjectAnalysis) []models.Suggestion {
-	var suggestions []models.Suggestion
-
-	if analysis == nil {
-		return suggestions
-	}
-
-	if analysis.Efficiency < 50 {
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "high",
-			Category:    "macro",
-			Title:       "Inject-Effizienz verbessern",
-			Description: "Deine Inject-Effizienz liegt bei nur %.0f%%. Nutze Hotkeys und regelmäßige Inject-Zyklen.",
-			TargetValue: "> 80% Effizienz",
-		})
-	} else if analysis.Efficiency < 70 {
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "medium",
-			Category:    "macro",
-			Title:       "Injects optimieren",
-			Description: "Deine Inject-Effizienz von %.0f%% kann verbessert werden. Trainiere den Inject-Rhythmus.",
-			TargetValue: "> 85% Effizienz",
-		})
-	}
-
-	if analysis.MissedInjects > 10 {
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "high",
-			Category:    "macro",
-			Title:       "Zu viele verpasste Injects",
-			Description: "Du hast ca. %d Injects verpasst. Setze einen Timer oder nutze das Inject-Hotkey-System.",
-			TargetValue: "< 5 verpasste Injects",
-		})
-	}
-
-	return suggestions
-}
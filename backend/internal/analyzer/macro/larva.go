@@ -0,0 +1,301 @@
+package macro
+
+import (
+	"math"
+	"strings"
+
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// LarvaAnalyzer analysiert Larven- und Queen-Nutzung von Zerg-Spielern
+type LarvaAnalyzer struct{}
+
+// NewLarvaAnalyzer erstellt einen neuen LarvaAnalyzer
+func NewLarvaAnalyzer() *LarvaAnalyzer {
+	return &LarvaAnalyzer{}
+}
+
+const (
+	// larvaCap ist die Larvenanzahl, ab der eine Hatchery keine natürlichen Larven mehr erzeugt
+	larvaCap = 3
+	// larvaSampleInterval ist der Abstand (Sekunden) der Larven-Timeline
+	larvaSampleInterval = 10.0
+	// injectLarvaCount ist die Anzahl gleichzeitig schlüpfender Larven eines Injects
+	injectLarvaCount = 3
+)
+
+// larvaHatchery ist der Larven-Zustand einer Hatchery
+type larvaHatchery struct {
+	stats        *models.HatcheryLarva
+	alive        bool
+	x, y         float64
+	larva        int
+	aliveTime    float64
+	larvaTime    float64
+	doneLoop     int
+	birthLoop    int
+	birthsInLoop int
+}
+
+// larvaQueen ist der Zustand einer Queen
+type larvaQueen struct {
+	usage *models.QueenUsage
+	alive bool
+	x, y  float64
+}
+
+// Analyze analysiert Larven- und Queen-Nutzung für Zerg-Spieler
+func (la *LarvaAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, race string, gameDuration float64) *models.LarvaAnalysis {
+	// Nur für Zerg relevant
+	if strings.ToLower(race) != "zerg" {
+		return nil
+	}
+
+	if events == nil {
+		return nil
+	}
+
+	analysis := &models.LarvaAnalysis{
+		Hatcheries: []models.HatcheryLarva{},
+		Queens:     []models.QueenUsage{},
+	}
+
+	hatcheries := make(map[int]*larvaHatchery) // unitTagIndex -> Hatchery
+	var hatchOrder []*larvaHatchery
+	larvaOwner := make(map[int]*larvaHatchery) // Larva unitTagIndex -> Hatchery
+	queens := make(map[int]*larvaQueen)
+	var queenOrder []*larvaQueen
+	aliveQueens := 0
+
+	lastTime := 0.0
+	nextSample := 0.0
+
+	// advance integriert Larvenzeit und Timeline bis zum Zeitpunkt t
+	advance := func(t float64) {
+		for nextSample <= t {
+			for _, h := range hatchOrder {
+				if h.alive {
+					h.stats.Timeline = append(h.stats.Timeline, models.LarvaSample{Time: nextSample, Count: h.larva})
+				}
+			}
+			nextSample += larvaSampleInterval
+		}
+
+		dt := t - lastTime
+		if dt <= 0 {
+			return
+		}
+		for _, h := range hatchOrder {
+			if !h.alive {
+				continue
+			}
+			h.aliveTime += dt
+			h.larvaTime += float64(h.larva) * dt
+			analysis.UnusedLarvaTime += float64(h.larva) * dt
+			if h.larva >= larvaCap {
+				h.stats.BankedTime += dt
+				analysis.BankedTime += dt
+			}
+		}
+		lastTime = t
+	}
+
+	// removeLarva entfernt eine Larve aus ihrer Hatchery
+	removeLarva := func(index int) bool {
+		h, ok := larvaOwner[index]
+		if !ok {
+			return false
+		}
+		if h != nil && h.larva > 0 {
+			h.larva--
+		}
+		delete(larvaOwner, index)
+		return true
+	}
+
+	for _, evt := range events.TrackerEvents {
+		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+		advance(timeSeconds)
+
+		index := getUnitTagInject(evt.Data)
+
+		switch evt.EventType {
+		case "UnitInit", "UnitBorn":
+			if getUnitPlayerIDInject(evt.Data) != playerID {
+				continue
+			}
+			unitType := getUnitTypeNameInject(evt.Data)
+			x, y := float64(parser.GetInt(evt.Data, "x")), float64(parser.GetInt(evt.Data, "y"))
+
+			switch {
+			case isHatcheryType(unitType):
+				h := &larvaHatchery{
+					stats: &models.HatcheryLarva{HatcheryID: index, X: int(x), Y: int(y), Timeline: []models.LarvaSample{}},
+					x:     x,
+					y:     y,
+				}
+				hatcheries[index] = h
+				// Start-Hatchery ist sofort fertig, gebaute erst mit UnitDone
+				if evt.EventType == "UnitBorn" {
+					h.alive = true
+					h.doneLoop = evt.Loop
+					h.stats.DoneTime = timeSeconds
+					hatchOrder = append(hatchOrder, h)
+				}
+
+			case strings.EqualFold(unitType, "Larva"):
+				analysis.LarvaSpawned++
+				h := nearestLarvaHatchery(hatchOrder, x, y)
+				larvaOwner[index] = h
+				if h == nil {
+					continue
+				}
+				h.larva++
+				h.stats.LarvaSpawned++
+
+				// Startlarven einer fertigen Hatchery erscheinen gemeinsam, sind aber kein Inject
+				if evt.Loop == 0 || evt.Loop == h.doneLoop {
+					continue
+				}
+
+				// Inject: mehrere Larven schlüpfen im selben Loop
+				if h.birthLoop == evt.Loop {
+					h.birthsInLoop++
+				} else {
+					h.birthLoop = evt.Loop
+					h.birthsInLoop = 1
+				}
+				if h.birthsInLoop == injectLarvaCount {
+					h.stats.InjectPops++
+					analysis.InjectPops++
+					if q := nearestQueen(queenOrder, h.x, h.y); q != nil {
+						q.usage.Injects++
+					}
+				}
+
+			case strings.EqualFold(unitType, "Queen"):
+				q := &larvaQueen{
+					usage: &models.QueenUsage{ID: index, BornTime: timeSeconds},
+					alive: true,
+					x:     x,
+					y:     y,
+				}
+				queens[index] = q
+				queenOrder = append(queenOrder, q)
+				analysis.QueensBuilt++
+				aliveQueens++
+				if aliveQueens > analysis.PeakQueens {
+					analysis.PeakQueens = aliveQueens
+				}
+
+			case strings.EqualFold(unitType, "CreepTumorQueen"):
+				if q := nearestQueen(queenOrder, x, y); q != nil {
+					q.usage.CreepTumors++
+				}
+			}
+
+		case "UnitDone":
+			if h, ok := hatcheries[index]; ok && !h.alive {
+				h.alive = true
+				h.doneLoop = evt.Loop
+				h.stats.DoneTime = timeSeconds
+				hatchOrder = append(hatchOrder, h)
+			}
+
+		case "UnitTypeChange":
+			// Larve -> Ei: Larve wurde genutzt
+			if removeLarva(index) {
+				analysis.LarvaUsed++
+			}
+
+		case "UnitDied":
+			removeLarva(index)
+			if h, ok := hatcheries[index]; ok {
+				h.alive = false
+				delete(hatcheries, index)
+			}
+			if q, ok := queens[index]; ok && q.alive {
+				q.alive = false
+				q.usage.DiedTime = timeSeconds
+				aliveQueens--
+			}
+
+		case "UnitPositions":
+			// Queen-Positionen aktualisieren
+			for _, p := range evt.UnitPositions() {
+				if q, ok := queens[p.UnitIndex]; ok {
+					q.x = p.X
+					q.y = p.Y
+				}
+			}
+		}
+	}
+	advance(gameDuration)
+
+	// Hatchery-Statistiken
+	var totalHatchTime float64
+	for _, h := range hatchOrder {
+		if h.aliveTime > 0 {
+			h.stats.AverageLarva = h.larvaTime / h.aliveTime
+		}
+		totalHatchTime += h.aliveTime
+		analysis.Hatcheries = append(analysis.Hatcheries, *h.stats)
+	}
+	if totalHatchTime > 0 {
+		analysis.BankedShare = analysis.BankedTime / totalHatchTime * 100
+	}
+	if analysis.LarvaSpawned > 0 {
+		analysis.ConversionRate = float64(analysis.LarvaUsed) / float64(analysis.LarvaSpawned) * 100
+	}
+
+	// Queen-Rollen
+	for _, q := range queenOrder {
+		switch {
+		case q.usage.Injects > 0 && q.usage.Injects >= q.usage.CreepTumors:
+			q.usage.Role = "inject"
+			analysis.InjectQueens++
+		case q.usage.CreepTumors > 0:
+			q.usage.Role = "creep"
+			analysis.CreepQueens++
+		default:
+			q.usage.Role = "defense"
+			analysis.DefenseQueens++
+		}
+		analysis.Queens = append(analysis.Queens, *q.usage)
+	}
+
+	return analysis
+}
+
+// nearestLarvaHatchery findet die nächste lebende Hatchery
+func nearestLarvaHatchery(hatcheries []*larvaHatchery, x, y float64) *larvaHatchery {
+	var nearest *larvaHatchery
+	bestDist := math.MaxFloat64
+	for _, h := range hatcheries {
+		if !h.alive {
+			continue
+		}
+		if d := math.Hypot(h.x-x, h.y-y); d < bestDist {
+			bestDist = d
+			nearest = h
+		}
+	}
+	return nearest
+}
+
+// nearestQueen findet die nächste lebende Queen
+func nearestQueen(queens []*larvaQueen, x, y float64) *larvaQueen {
+	var nearest *larvaQueen
+	bestDist := math.MaxFloat64
+	for _, q := range queens {
+		if !q.alive {
+			continue
+		}
+		if d := math.Hypot(q.x-x, q.y-y); d < bestDist {
+			bestDist = d
+			nearest = q
+		}
+	}
+	return nearest
+}
@@ -15,22 +15,78 @@ func NewSpendingAnalyzer() *SpendingAnalyzer {
 	return &SpendingAnalyzer{}
 }
 
+const (
+	// sqWarmup: Ticks vor diesem Zeitpunkt (Sekunden) fließen nicht in den SQ ein,
+	// im Opening sagen Einkommen und Bank noch nichts über das Spending aus
+	sqWarmup = 90.0
+	// sqRollingWindow ist das Fenster (Sekunden) der gleitenden SQ-Timeline
+	sqRollingWindow = 60.0
+	// floatThreshold: ab so vielen ungenutzten Ressourcen (Mineralien + Gas) wird gefloatet
+	floatThreshold = 1000
+	// floatMinDuration: kürzere Float-Phasen (Sekunden) werden nicht gemeldet
+	floatMinDuration = 20.0
+)
+
+// resourceSample ist ein PlayerStats-Tick eines Spielers
+type resourceSample struct {
+	time         float64
+	minerals     float64
+	gas          float64
+	mineralsRate float64 // Mineralien pro Minute
+	gasRate      float64 // Gas pro Minute
+}
+
+// resourceAverages sind die Durchschnittswerte einer Menge von Ticks
+type resourceAverages struct {
+	unspent models.ResourceValue
+	income  models.ResourceValue
+}
+
+// averageResources bildet die Durchschnitte über die übergebenen Ticks
+func averageResources(samples []resourceSample) resourceAverages {
+	var avg resourceAverages
+	if len(samples) == 0 {
+		return avg
+	}
+	for _, s := range samples {
+		avg.unspent.Minerals += s.minerals
+		avg.unspent.Gas += s.gas
+		avg.income.Minerals += s.mineralsRate
+		avg.income.Gas += s.gasRate
+	}
+	n := float64(len(samples))
+	avg.unspent.Minerals /= n
+	avg.unspent.Gas /= n
+	avg.income.Minerals /= n
+	avg.income.Gas /= n
+	return avg
+}
+
+// sq berechnet den Spending Quotient aus den Durchschnittswerten
+func (avg resourceAverages) sq() float64 {
+	return calculateSQ(avg.income.Minerals+avg.income.Gas, avg.unspent.Minerals+avg.unspent.Gas)
+}
+
 // Analyze analysiert das Ressourcen-Spending eines Spielers
-func (sa *SpendingAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameDuration float64) *models.SpendingAnalysis {
+// leftAt ist der Zeitpunkt (Sekunden), zu dem der Spieler das Spiel verlassen hat (0 = unbekannt)
+func (sa *SpendingAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameDuration, leftAt float64) *models.SpendingAnalysis {
 	if events == nil {
 		return nil
 	}
 
 	analysis := &models.SpendingAnalysis{
 		ResourceTimeline: []models.ResourcePoint{},
+		SQTimeline:       []models.SQPoint{},
+		FloatPeriods:     []models.FloatPeriod{},
 	}
 
-	var totalMinerals, totalGas float64
-	var totalIncome float64
-	var dataPoints int
-
-	var lastMineralsRate, lastGasRate float64
+	// Nach dem Verlassen laufen die PlayerStats weiter, die Bank wächst aber nur noch
+	endTime := gameDuration
+	if leftAt > 0 && leftAt < endTime {
+		endTime = leftAt
+	}
 
+	var samples []resourceSample
 	for _, evt := range events.TrackerEvents {
 		// Event-Typ prüfen (vereinfachter Name)
 		if evt.EventType != "PlayerStats" {
@@ -41,65 +97,140 @@ func (sa *SpendingAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, g
 		}
 
 		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+		if leftAt > 0 && timeSeconds > leftAt {
+			break
+		}
 
-		// Aktuelle Ressourcen (Werte sind bereits in normalen Einheiten)
-		minerals := getScoreValueFloat(evt.Data, "scoreValueMineralsCurrent")
-		gas := getScoreValueFloat(evt.Data, "scoreValueVespeneCurrent")
-
-		// Einkommensrate (durch 4096 teilen)
-		mineralsRate := getScoreValueFloat(evt.Data, "scoreValueMineralsCollectionRate") / 4096
-		gasRate := getScoreValueFloat(evt.Data, "scoreValueVespeneCollectionRate") / 4096
-
-		// Sammle Daten für Durchschnittsberechnung
-		totalMinerals += minerals
-		totalGas += gas
-		totalIncome += mineralsRate + gasRate
-		dataPoints++
+		// Bank und Sammelrate (Ressourcen pro Minute) sind keine Festkomma-Werte
+		sample := resourceSample{
+			time:         timeSeconds,
+			minerals:     getScoreValueFloat(evt.Data, "scoreValueMineralsCurrent"),
+			gas:          getScoreValueFloat(evt.Data, "scoreValueVespeneCurrent"),
+			mineralsRate: getScoreValueFloat(evt.Data, "scoreValueMineralsCollectionRate"),
+			gasRate:      getScoreValueFloat(evt.Data, "scoreValueVespeneCollectionRate"),
+		}
+		samples = append(samples, sample)
 
 		// Timeline-Punkt
 		analysis.ResourceTimeline = append(analysis.ResourceTimeline, models.ResourcePoint{
 			Time:     timeSeconds,
-			Minerals: int(minerals),
-			Gas:      int(gas),
+			Minerals: int(sample.minerals),
+			Gas:      int(sample.gas),
 			Income: models.ResourceValue{
-				Minerals: mineralsRate,
-				Gas:      gasRate,
+				Minerals: sample.mineralsRate,
+				Gas:      sample.gasRate,
 			},
 		})
+	}
 
-		lastMineralsRate = mineralsRate
-		lastGasRate = gasRate
+	if len(samples) == 0 {
+		return analysis
 	}
 
-	// Dummy-Check für Compiler-Warnung
-	_ = lastMineralsRate
-	_ = lastGasRate
+	// Für den SQ zählen nur Ticks nach dem Opening; sehr kurze Spiele nutzen alle Ticks
+	sqSamples := samples
+	for i, s := range samples {
+		if s.time >= sqWarmup {
+			sqSamples = samples[i:]
+			break
+		}
+	}
+	if sqSamples[len(sqSamples)-1].time < sqWarmup {
+		sqSamples = samples
+	}
 
-	// Berechne Durchschnittswerte
-	if dataPoints > 0 {
-		avgUnspentMinerals := totalMinerals / float64(dataPoints)
-		avgUnspentGas := totalGas / float64(dataPoints)
-		avgIncome := totalIncome / float64(dataPoints)
+	// SQ = 35 * (0.00137 * Einkommen - ln(ungenutzte Ressourcen)) + 240
+	avg := averageResources(sqSamples)
+	analysis.AverageUnspent = avg.unspent
+	analysis.AverageIncome = avg.income
+	analysis.SpendingQuotient = avg.sq()
+	analysis.Rating = rateSQ(analysis.SpendingQuotient)
+
+	// Gleitender SQ über die letzten sqRollingWindow Sekunden
+	windowStart := 0
+	for i, s := range sqSamples {
+		for sqSamples[windowStart].time <= s.time-sqRollingWindow {
+			windowStart++
+		}
+		analysis.SQTimeline = append(analysis.SQTimeline, models.SQPoint{
+			Time: s.time,
+			SQ:   averageResources(sqSamples[windowStart : i+1]).sq(),
+		})
+	}
 
-		analysis.AverageUnspent = models.ResourceValue{
-			Minerals: avgUnspentMinerals,
-			Gas:      avgUnspentGas,
+	// SQ pro Spielphase
+	for _, phase := range models.GamePhases(endTime) {
+		var phaseSamples []resourceSample
+		for _, s := range sqSamples {
+			if phase.Contains(s.time) {
+				phaseSamples = append(phaseSamples, s)
+			}
 		}
-		analysis.AverageIncome = models.ResourceValue{
-			Minerals: avgIncome * 0.7, // Ungefähre Aufteilung
-			Gas:      avgIncome * 0.3,
+		if len(phaseSamples) == 0 {
+			continue
 		}
+		phaseAvg := averageResources(phaseSamples)
+		sq := phaseAvg.sq()
+		analysis.Phases = append(analysis.Phases, models.SpendingPhase{
+			Phase:            phase.Name,
+			Start:            phase.Start,
+			End:              phase.End,
+			SpendingQuotient: sq,
+			Rating:           rateSQ(sq),
+			AverageUnspent:   phaseAvg.unspent,
+			AverageIncome:    phaseAvg.income.Minerals + phaseAvg.income.Gas,
+		})
+	}
 
-		// Berechne Spending Quotient
-		// SQ = 35 * (0.00137 * avgIncome - ln(avgUnspent + 1)) + 240
-		avgUnspent := avgUnspentMinerals + avgUnspentGas
-		analysis.SpendingQuotient = calculateSQ(avgIncome, avgUnspent)
-		analysis.Rating = rateSQ(analysis.SpendingQuotient)
+	analysis.FloatPeriods = findFloatPeriods(samples, endTime)
+	for _, period := range analysis.FloatPeriods {
+		analysis.FloatTime += period.Duration
 	}
 
 	return analysis
 }
 
+// findFloatPeriods findet zusammenhängende Zeiträume mit zu vielen ungenutzten Ressourcen
+func findFloatPeriods(samples []resourceSample, gameDuration float64) []models.FloatPeriod {
+	periods := []models.FloatPeriod{}
+	var current *models.FloatPeriod
+
+	closePeriod := func(end float64) {
+		current.End = end
+		current.Duration = end - current.Start
+		if current.Duration >= floatMinDuration {
+			periods = append(periods, *current)
+		}
+		current = nil
+	}
+
+	for _, s := range samples {
+		unspent := s.minerals + s.gas
+		if unspent < floatThreshold {
+			if current != nil {
+				closePeriod(s.time)
+			}
+			continue
+		}
+		if current == nil {
+			current = &models.FloatPeriod{Start: s.time}
+		}
+		if unspent > float64(current.PeakMinerals+current.PeakGas) {
+			current.PeakMinerals = int(s.minerals)
+			current.PeakGas = int(s.gas)
+		}
+	}
+	if current != nil {
+		end := samples[len(samples)-1].time
+		if gameDuration > end {
+			end = gameDuration
+		}
+		closePeriod(end)
+	}
+
+	return periods
+}
+
 // getScoreValueFloat extrahiert einen Float-Wert aus den Event-Daten
 func getScoreValueFloat(data map[string]interface{}, key string) float64 {
 	// Stats sind in "stats" verschachtelt (ohne m_ Präfix)
@@ -132,6 +263,7 @@ func getScoreValueFloat(data map[string]interface{}, key string) float64 {
 
 // calculateSQ berechnet den Spending Quotient
 // Formel: SQ = 35 * (0.00137 * avgIncome - ln(avgUnspent + 1)) + 240
+// avgIncome ist die Sammelrate (Mineralien + Gas) pro Minute
 func calculateSQ(avgIncome, avgUnspent float64) float64 {
 	if avgIncome <= 0 {
 		return 0
@@ -151,69 +283,21 @@ func calculateSQ(avgIncome, avgUnspent float64) float64 {
 }
 
 // rateSQ bewertet den Spending Quotient
+// Seit die Sammelrate nicht mehr durch 4096 geteilt wird, liegt der SQ auf der üblichen
+// Skala (Ladder-Durchschnitt um 60-70, Profis 80-100); die Grenzen entsprechen den
+// Schwellen im Wochenbericht (50/60/70). Ältere gespeicherte Werte werden über
+// models.AnalysisVersion beim Start neu berechnet.
 func rateSQ(sq float64) string {
 	switch {
-	case sq < 70:
+	case sq < 50:
 		return "poor"
-	case sq < 90:
+	case sq < 60:
 		return "below_average"
-	case sq < 110:
+	case sq < 70:
 		return "average"
-	case sq < 130:
+	case sq < 85:
 		return "good"
 	default:
 		return "excellent"
 	}
 }
-
-// GenerateSuggestions erstellt Verbesserungsvorschläge
-func (sa *SpendingAnalyzer) GenerateSuggestions(analysis *models.SpendingAnalysis) []models.Suggestion {
-	var suggestions []models.Suggestion
-
-	if analysis == nil {
-		return suggestions
-	}
-
-	// Vorschlag basierend auf SQ
-	switch analysis.Rating {
-	case "poor":
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "high",
-			Category:    "macro",
-			Title:       "Ressourcen besser ausgeben",
-			Description: "Dein Spending Quotient von %.0f ist niedrig. Baue mehr Produktionsgebäude oder Einheiten.",
-			TargetValue: "> 90 SQ",
-		})
-	case "below_average":
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "medium",
-			Category:    "macro",
-			Title:       "Spending verbessern",
-			Description: "Du hast oft zu viele ungenutzte Ressourcen. Versuche, kontinuierlich zu produzieren.",
-			TargetValue: "> 100 SQ",
-		})
-	}
-
-	// Spezifische Ressourcen-Vorschläge
-	if analysis.AverageUnspent.Minerals > 1000 {
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "high",
-			Category:    "macro",
-			Title:       "Zu viele ungenutzte Mineralien",
-			Description: "Du hattest durchschnittlich %.0f ungenutzte Mineralien. Baue mehr Produktionsgebäude.",
-			TargetValue: "< 500 Mineralien",
-		})
-	}
-
-	if analysis.AverageUnspent.Gas > 500 {
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "medium",
-			Category:    "macro",
-			Title:       "Zu viel ungenutztes Gas",
-			Description: "Du hattest durchschnittlich %.0f ungenutztes Gas. Baue mehr gas-intensive Einheiten.",
-			TargetValue: "< 300 Gas",
-		})
-	}
-
-	return suggestions
-}
@@ -0,0 +1,92 @@
+package macro
+
+import (
+	"math"
+	"testing"
+
+	"sc2-analytics/internal/parser"
+)
+
+func TestCalculateSQ(t *testing.T) {
+	tests := []struct {
+		name            string
+		income, unspent float64
+		want            float64
+	}{
+		{"no income", 0, 300, 0},
+		{"typical ladder game", 1500, 500, 94.344},
+		{"high income low bank", 2000, 300, 136.151},
+		{"floating", 800, 1500, 22.374},
+		{"clamped at zero", 500, 5000, 0},
+		{"clamped at 200", 5000, 0, 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateSQ(tt.income, tt.unspent); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("calculateSQ(%v, %v) = %.3f, want %.3f", tt.income, tt.unspent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateSQ(t *testing.T) {
+	tests := []struct {
+		sq   float64
+		want string
+	}{
+		{0, "poor"},
+		{49.9, "poor"},
+		{50, "below_average"},
+		{60, "average"},
+		{70, "good"},
+		{84.9, "good"},
+		{85, "excellent"},
+		{200, "excellent"},
+	}
+	for _, tt := range tests {
+		if got := rateSQ(tt.sq); got != tt.want {
+			t.Errorf("rateSQ(%v) = %q, want %q", tt.sq, got, tt.want)
+		}
+	}
+}
+
+// playerStats baut einen PlayerStats-Tick mit Bank und Sammelrate pro Minute
+func playerStats(seconds float64, bank, rate int) parser.TrackerEvent {
+	return parser.TrackerEvent{
+		Loop:      parser.RealSecondsToLoops(seconds),
+		EventType: "PlayerStats",
+		PlayerID:  1,
+		Data: map[string]interface{}{"stats": map[string]interface{}{
+			"scoreValueMineralsCurrent":        int64(bank),
+			"scoreValueMineralsCollectionRate": int64(rate),
+		}},
+	}
+}
+
+func TestSpendingStopsAtLeftAt(t *testing.T) {
+	events := &parser.ParsedEvents{}
+	for s := 100.0; s <= 300; s += 10 {
+		bank := 200
+		if s > 200 {
+			bank = 5000 // nach dem Verlassen wächst nur noch die Bank
+		}
+		events.TrackerEvents = append(events.TrackerEvents, playerStats(s, bank, 1500))
+	}
+
+	sa := NewSpendingAnalyzer()
+	left := sa.Analyze(events, 1, 300, 200)
+	if got := left.ResourceTimeline[len(left.ResourceTimeline)-1].Time; got > 200 {
+		t.Errorf("last tick at %.0fs, want <= 200s", got)
+	}
+	if left.FloatTime != 0 {
+		t.Errorf("FloatTime = %.0f, want 0 (float only after leaving)", left.FloatTime)
+	}
+	if want := calculateSQ(1500, 200); math.Abs(left.SpendingQuotient-want) > 0.001 {
+		t.Errorf("SpendingQuotient = %.3f, want %.3f", left.SpendingQuotient, want)
+	}
+
+	stayed := sa.Analyze(events, 1, 300, 0)
+	if stayed.SpendingQuotient >= left.SpendingQuotient {
+		t.Errorf("SQ without leave = %.1f, want below %.1f", stayed.SpendingQuotient, left.SpendingQuotient)
+	}
+}
@@ -1,6 +1,9 @@
 package macro
 
 import (
+	"math"
+	"strings"
+
 	"sc2-analytics/internal/models"
 	"sc2-analytics/internal/parser"
 )
@@ -13,6 +16,33 @@ func NewSupplyAnalyzer() *SupplyAnalyzer {
 	return &SupplyAnalyzer{}
 }
 
+// supplyProviderWindow ist ein Zeitraum, in dem ein Supply-Gebäude im Bau war
+type supplyProviderWindow struct {
+	Start float64
+	Done  float64
+}
+
+// supplySample ist ein PlayerStats-Snapshot für die Ursachenanalyse
+type supplySample struct {
+	Time       float64
+	SupplyUsed int
+	SupplyMax  int
+	Bank       int
+}
+
+const (
+	// maxSupply ist das Supply-Limit des Spiels
+	maxSupply = 200
+	// overlordBuildTime ist die Bauzeit eines Overlords (Echtzeit-Sekunden)
+	overlordBuildTime = 18.0
+	// killedLookback ist das Zeitfenster, in dem ein Supply-Verlust als Ursache gilt
+	killedLookback = 30.0
+	// productionLookback ist das Zeitfenster für die Produktionsrate vor einem Block
+	productionLookback = 60.0
+	// minProductionBank ist der Mindest-Bank, ab dem Produktion möglich gewesen wäre
+	minProductionBank = 50
+)
+
 // Analyze analysiert Supply Blocks für einen Spieler
 func (sa *SupplyAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameDuration float64) *models.SupplyAnalysis {
 	if events == nil {
@@ -24,8 +54,14 @@ func (sa *SupplyAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gam
 		SupplyTimeline: []models.SupplyPoint{},
 	}
 
+	// Supply-Gebäude im Bau (für die Ursachenanalyse)
+	providers := collectSupplyProviders(events, playerID)
+
+	var samples []supplySample
 	var currentBlock *models.SupplyBlock
-	var lastSupplyUsed, lastSupplyMax int
+	var blockStartBank int
+	var blockSupplyRate float64
+	var maxedSince float64 = -1
 
 	for _, evt := range events.TrackerEvents {
 		// Event-Typ prüfen (vereinfachter Name in neuer s2prot Version)
@@ -44,51 +80,76 @@ func (sa *SupplyAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gam
 		supplyUsed := foodUsed / 4096
 		supplyMax := foodMade / 4096
 
+		bank := getScoreValue(evt.Data, "scoreValueMineralsCurrent") +
+			getScoreValue(evt.Data, "scoreValueVespeneCurrent")
+
 		// Zeitpunkt in Sekunden
 		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
 
-		// Supply Point für Timeline
-		isBlocked := supplyUsed >= supplyMax && supplyMax > 0
+		// 200/200 ist kein Supply Block
+		isMaxed := supplyMax >= maxSupply && supplyUsed >= supplyMax
+		isBlocked := supplyUsed >= supplyMax && supplyMax > 0 && !isMaxed
+
 		analysis.SupplyTimeline = append(analysis.SupplyTimeline, models.SupplyPoint{
 			Time:       timeSeconds,
 			SupplyUsed: supplyUsed,
 			SupplyMax:  supplyMax,
 			IsBlocked:  isBlocked,
+			IsMaxed:    isMaxed,
 		})
 
+		// Maxed-Zeit separat erfassen
+		if isMaxed {
+			if maxedSince < 0 {
+				maxedSince = timeSeconds
+			}
+		} else if maxedSince >= 0 {
+			analysis.MaxedOutTime += timeSeconds - maxedSince
+			maxedSince = -1
+		}
+
 		// Supply Block Erkennung
 		if isBlocked {
 			if currentBlock == nil {
 				// Neuer Block beginnt
 				currentBlock = &models.SupplyBlock{
-					StartTime:  timeSeconds,
-					SupplyUsed: supplyUsed,
-					SupplyMax:  supplyMax,
+					StartTime:         timeSeconds,
+					SupplyUsed:        supplyUsed,
+					SupplyMax:         supplyMax,
+					ProductionWaiting: bank >= minProductionBank,
 				}
+				currentBlock.Cause, currentBlock.ProviderInProgress = classifyBlockCause(timeSeconds, supplyMax, samples, providers)
+				blockSupplyRate = estimateSupplyRate(timeSeconds, supplyUsed, samples)
+				blockStartBank = bank
+			} else if bank >= minProductionBank {
+				currentBlock.ProductionWaiting = true
 			}
 		} else {
 			if currentBlock != nil {
 				// Block endet
-				currentBlock.EndTime = timeSeconds
-				currentBlock.Duration = currentBlock.EndTime - currentBlock.StartTime
-				currentBlock.Severity = classifyBlockSeverity(currentBlock.Duration)
-				analysis.Blocks = append(analysis.Blocks, *currentBlock)
-				analysis.TotalBlockTime += currentBlock.Duration
+				sa.closeBlock(analysis, currentBlock, timeSeconds, bank-blockStartBank, blockSupplyRate)
 				currentBlock = nil
 			}
 		}
 
-		lastSupplyUsed = supplyUsed
-		lastSupplyMax = supplyMax
+		samples = append(samples, supplySample{
+			Time:       timeSeconds,
+			SupplyUsed: supplyUsed,
+			SupplyMax:  supplyMax,
+			Bank:       bank,
+		})
 	}
 
 	// Falls ein Block am Ende des Spiels noch offen ist
 	if currentBlock != nil {
-		currentBlock.EndTime = gameDuration
-		currentBlock.Duration = currentBlock.EndTime - currentBlock.StartTime
-		currentBlock.Severity = classifyBlockSeverity(currentBlock.Duration)
-		analysis.Blocks = append(analysis.Blocks, *currentBlock)
-		analysis.TotalBlockTime += currentBlock.Duration
+		lastBank := blockStartBank
+		if len(samples) > 0 {
+			lastBank = samples[len(samples)-1].Bank
+		}
+		sa.closeBlock(analysis, currentBlock, gameDuration, lastBank-blockStartBank, blockSupplyRate)
+	}
+	if maxedSince >= 0 {
+		analysis.MaxedOutTime += gameDuration - maxedSince
 	}
 
 	// Berechne Prozentsatz der blockierten Zeit
@@ -96,13 +157,141 @@ func (sa *SupplyAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gam
 		analysis.BlockPercentage = (analysis.TotalBlockTime / gameDuration) * 100
 	}
 
-	// Dummy-Check für Compiler-Warnung
-	_ = lastSupplyUsed
-	_ = lastSupplyMax
+	analysis.Phases = supplyPhases(analysis.Blocks, gameDuration)
 
 	return analysis
 }
 
+// supplyPhases verteilt die Blockzeit auf die Spielphasen
+func supplyPhases(blocks []models.SupplyBlock, gameDuration float64) []models.SupplyPhase {
+	var phases []models.SupplyPhase
+	for _, phase := range models.GamePhases(gameDuration) {
+		p := models.SupplyPhase{Phase: phase.Name, Start: phase.Start, End: phase.End}
+		for _, block := range blocks {
+			// Blöcke über eine Phasengrenze werden anteilig gezählt
+			overlap := math.Min(block.EndTime, phase.End) - math.Max(block.StartTime, phase.Start)
+			if overlap <= 0 {
+				continue
+			}
+			p.BlockTime += overlap
+			if phase.Contains(block.StartTime) {
+				p.Blocks++
+			}
+		}
+		if phase.Duration() > 0 {
+			p.BlockPercentage = p.BlockTime / phase.Duration() * 100
+		}
+		phases = append(phases, p)
+	}
+	return phases
+}
+
+// closeBlock schließt einen Block ab und fügt ihn der Analyse hinzu
+func (sa *SupplyAnalyzer) closeBlock(analysis *models.SupplyAnalysis, block *models.SupplyBlock, endTime float64, floated int, supplyRate float64) {
+	block.EndTime = endTime
+	block.Duration = block.EndTime - block.StartTime
+	block.Severity = classifyBlockSeverity(block.Duration)
+	if floated > 0 {
+		block.ResourcesFloated = floated
+	}
+	// Produktionsrate vor dem Block auf die Blockdauer hochrechnen
+	block.EstimatedSupplyLost = int(math.Round(supplyRate * block.Duration))
+
+	analysis.Blocks = append(analysis.Blocks, *block)
+	analysis.TotalBlockTime += block.Duration
+}
+
+// collectSupplyProviders sammelt die Bauzeiträume aller Supply-Gebäude eines Spielers
+func collectSupplyProviders(events *parser.ParsedEvents, playerID int) []supplyProviderWindow {
+	var windows []supplyProviderWindow
+	inProgress := make(map[int]float64) // unitTag -> Baubeginn
+
+	for _, evt := range events.TrackerEvents {
+		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+
+		switch evt.EventType {
+		case "UnitInit":
+			if getUnitPlayerIDInject(evt.Data) != playerID {
+				continue
+			}
+			if isSupplyProvider(getUnitTypeNameInject(evt.Data)) {
+				inProgress[getUnitTagInject(evt.Data)] = timeSeconds
+			}
+
+		case "UnitDone":
+			tag := getUnitTagInject(evt.Data)
+			if start, ok := inProgress[tag]; ok {
+				windows = append(windows, supplyProviderWindow{Start: start, Done: timeSeconds})
+				delete(inProgress, tag)
+			}
+
+		case "UnitBorn":
+			// Overlords haben kein UnitInit - Baubeginn aus der Bauzeit zurückrechnen
+			if evt.Loop == 0 || getUnitPlayerIDInject(evt.Data) != playerID {
+				continue
+			}
+			if strings.EqualFold(getUnitTypeNameInject(evt.Data), "Overlord") {
+				windows = append(windows, supplyProviderWindow{
+					Start: timeSeconds - overlordBuildTime,
+					Done:  timeSeconds,
+				})
+			}
+		}
+	}
+
+	return windows
+}
+
+// isSupplyProvider prüft ob ein Gebäude Supply liefert
+func isSupplyProvider(unitType string) bool {
+	lowerType := strings.ToLower(unitType)
+	providers := []string{"supplydepot", "pylon", "commandcenter", "nexus", "hatchery"}
+	for _, p := range providers {
+		if strings.Contains(lowerType, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyBlockCause bestimmt die Ursache eines Supply Blocks
+func classifyBlockCause(start float64, supplyMax int, samples []supplySample, providers []supplyProviderWindow) (string, bool) {
+	inProgress := false
+	for _, p := range providers {
+		if p.Start <= start && p.Done > start {
+			inProgress = true
+			break
+		}
+	}
+
+	// Supply-Limit ist kurz vorher gesunken -> Supply-Gebäude/Overlord verloren
+	for i := len(samples) - 1; i >= 0 && start-samples[i].Time <= killedLookback; i-- {
+		if samples[i].SupplyMax > supplyMax {
+			return "supply_provider_killed", inProgress
+		}
+	}
+
+	if inProgress {
+		return "supply_started_late", true
+	}
+	return "no_supply_started", false
+}
+
+// estimateSupplyRate schätzt die Produktionsrate (Supply pro Sekunde) vor dem Block
+func estimateSupplyRate(start float64, supplyUsed int, samples []supplySample) float64 {
+	for _, s := range samples {
+		if start-s.Time > productionLookback {
+			continue
+		}
+		elapsed := start - s.Time
+		if elapsed <= 0 || supplyUsed <= s.SupplyUsed {
+			return 0
+		}
+		return float64(supplyUsed-s.SupplyUsed) / elapsed
+	}
+	return 0
+}
+
 // getScoreValue extrahiert einen Score-Wert aus den Event-Daten
 func getScoreValue(data map[string]interface{}, key string) int {
 	// Die Stats sind in "stats" verschachtelt (ohne m_ Präfix)
@@ -144,47 +333,3 @@ func classifyBlockSeverity(duration float64) string {
 		return "high"
 	}
 }
-
-// GenerateSuggestions erstellt Verbesserungsvorschläge basierend auf der Analyse
-func (sa *SupplyAnalyzer) GenerateSuggestions(analysis *models.SupplyAnalysis) []models.Suggestion {
-	var suggestions []models.Suggestion
-
-	if analysis == nil {
-		return suggestions
-	}
-
-	// Vorschlag basierend auf Gesamtblockzeit
-	if analysis.BlockPercentage > 10 {
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "high",
-			Category:    "macro",
-			Title:       "Zu viele Supply Blocks",
-			Description: "Du warst %.1f%% der Spielzeit Supply-blockiert. Baue präventiv Supply-Gebäude.",
-			TargetValue: "< 5% Blockzeit",
-		})
-	} else if analysis.BlockPercentage > 5 {
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "medium",
-			Category:    "macro",
-			Title:       "Supply Blocks reduzieren",
-			Description: "Einige Supply Blocks könnten vermieden werden. Achte auf dein Supply-Limit.",
-			TargetValue: "< 5% Blockzeit",
-		})
-	}
-
-	// Vorschläge für schwere Blocks
-	for _, block := range analysis.Blocks {
-		if block.Severity == "high" {
-			suggestions = append(suggestions, models.Suggestion{
-				Priority:    "high",
-				Category:    "macro",
-				Title:       "Schwerer Supply Block",
-				Description: "Supply Block von %.0f Sekunden bei %.0f:%02.0f",
-				Timestamp:   block.StartTime,
-				TargetValue: "< 5s",
-			})
-		}
-	}
-
-	return suggestions
-}
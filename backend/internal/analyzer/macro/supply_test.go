@@ -0,0 +1,76 @@
+package macro
+
+import (
+	"math"
+	"testing"
+
+	"sc2-analytics/internal/parser"
+)
+
+func TestClassifyBlockCause(t *testing.T) {
+	depot := []supplyProviderWindow{{Start: 90, Done: 111}}
+	steady := []supplySample{
+		{Time: 80, SupplyUsed: 38, SupplyMax: 46},
+		{Time: 90, SupplyUsed: 42, SupplyMax: 46},
+	}
+	lost := []supplySample{
+		{Time: 80, SupplyUsed: 44, SupplyMax: 54},
+		{Time: 90, SupplyUsed: 46, SupplyMax: 46},
+	}
+	lostLongAgo := []supplySample{
+		{Time: 50, SupplyUsed: 44, SupplyMax: 54},
+		{Time: 90, SupplyUsed: 46, SupplyMax: 46},
+	}
+
+	tests := []struct {
+		name           string
+		samples        []supplySample
+		providers      []supplyProviderWindow
+		wantCause      string
+		wantInProgress bool
+	}{
+		{"nothing in production", steady, nil, "no_supply_started", false},
+		{"depot under construction", steady, depot, "supply_started_late", true},
+		{"depot finished before the block", steady, []supplyProviderWindow{{Start: 60, Done: 81}}, "no_supply_started", false},
+		{"depot started after the block", steady, []supplyProviderWindow{{Start: 101, Done: 122}}, "no_supply_started", false},
+		{"supply max dropped", lost, nil, "supply_provider_killed", false},
+		{"killed wins over late depot", lost, depot, "supply_provider_killed", true},
+		{"loss outside the lookback", lostLongAgo, nil, "no_supply_started", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cause, inProgress := classifyBlockCause(100, 46, tt.samples, tt.providers)
+			if cause != tt.wantCause || inProgress != tt.wantInProgress {
+				t.Errorf("classifyBlockCause() = (%q, %v), want (%q, %v)", cause, inProgress, tt.wantCause, tt.wantInProgress)
+			}
+		})
+	}
+}
+
+func TestCollectSupplyProviders(t *testing.T) {
+	unit := func(seconds float64, eventType string, player, tag int, unitType string) parser.TrackerEvent {
+		return parser.TrackerEvent{Loop: parser.RealSecondsToLoops(seconds), EventType: eventType, Data: map[string]interface{}{
+			"controlPlayerId": player, "unitTagIndex": tag, "unitTypeName": unitType,
+		}}
+	}
+	events := &parser.ParsedEvents{TrackerEvents: []parser.TrackerEvent{
+		unit(0, "UnitBorn", 1, 1, "Overlord"), // Start-Overlord
+		unit(60, "UnitInit", 1, 2, "SupplyDepot"),
+		unit(70, "UnitInit", 2, 3, "Pylon"), // Gegner
+		unit(70, "UnitInit", 1, 4, "Barracks"),
+		unit(81, "UnitDone", 1, 2, "SupplyDepot"),
+		unit(120, "UnitBorn", 1, 5, "Overlord"),
+		unit(130, "UnitInit", 1, 6, "SupplyDepot"), // bei Spielende nicht fertig
+	}}
+
+	got := collectSupplyProviders(events, 1)
+	want := []supplyProviderWindow{{Start: 60, Done: 81}, {Start: 120 - overlordBuildTime, Done: 120}}
+	if len(got) != len(want) {
+		t.Fatalf("collectSupplyProviders() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i].Start-want[i].Start) > 0.1 || math.Abs(got[i].Done-want[i].Done) > 0.1 {
+			t.Errorf("window %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
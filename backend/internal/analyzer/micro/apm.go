@@ -1,16 +1,57 @@
 package micro
 
 import (
+	"fmt"
+	"math"
+
 	"sc2-analytics/internal/models"
 	"sc2-analytics/internal/parser"
 )
 
 // APMAnalyzer berechnet APM-bezogene Metriken
-type APMAnalyzer struct{}
+type APMAnalyzer struct {
+	resolution float64 // Fenstergröße der Timeline in Sekunden
+}
+
+// Standard-Fenstergröße der APM-Timeline in Sekunden
+const defaultAPMResolution = 30.0
 
 // NewAPMAnalyzer erstellt einen neuen APMAnalyzer
 func NewAPMAnalyzer() *APMAnalyzer {
-	return &APMAnalyzer{}
+	return &APMAnalyzer{resolution: defaultAPMResolution}
+}
+
+// SetResolution setzt die Fenstergröße der APM-Timeline in Sekunden
+func (aa *APMAnalyzer) SetResolution(seconds float64) {
+	if seconds > 0 {
+		aa.resolution = seconds
+	}
+}
+
+// Aktionskategorien
+const (
+	actionCommand = iota
+	actionSelection
+	actionControlGroupRecall
+	actionControlGroupSet
+	actionCamera
+	actionNone
+)
+
+const (
+	// minLoopsBetweenActions: identische Aktionen innerhalb dieses Abstands gelten als Spam
+	minLoopsBetweenActions = 8
+	// fixationDistance ist die Kamerabewegung (Map-Zellen), ab der eine neue Fixation beginnt
+	fixationDistance = 6.0
+)
+
+// fixation ist eine Kamera-Fixation für die PAC-Analyse
+type fixation struct {
+	Start       float64
+	End         float64
+	X, Y        float64
+	Actions     int
+	FirstAction float64
 }
 
 // Analyze analysiert APM für einen Spieler
@@ -19,42 +60,89 @@ func (aa *APMAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameDu
 		return nil
 	}
 
+	windowSize := aa.resolution
+	if windowSize <= 0 {
+		windowSize = defaultAPMResolution
+	}
+
 	analysis := &models.APMAnalysis{
+		Resolution:  windowSize,
 		APMTimeline: []models.APMPoint{},
 	}
 
-	// Sammle Aktionen pro Zeitfenster (30 Sekunden)
-	const windowSize = 30.0
-	actionWindows := make(map[int]int) // window index -> action count
+	// Aktionen pro Zeitfenster und Kategorie
+	numWindows := int(gameDuration/windowSize) + 1
+	windows := make([][actionNone]int, numWindows)
+	var totals [actionNone]int
 	var totalActions int
 	var effectiveActions int
+	phaseActions := make(map[string]int)
+	phaseEffective := make(map[string]int)
 
 	// Für EAPM: Tracke letzte Aktion um Spam zu filtern
-	var lastActionLoop int
-	const minLoopsBetweenActions = 8 // Mindestens 0.5 Sekunden zwischen "echten" Aktionen
+	lastActionLoop := -minLoopsBetweenActions
+	lastActionKey := ""
+
+	// Für PAC: Kamera-Fixationen
+	var fixations []*fixation
+	var current *fixation
 
 	for _, evt := range events.GameEvents {
 		if evt.PlayerID != playerID {
 			continue
 		}
 
-		// Zähle nur relevante Aktionen (vereinfachte Namen)
-		if !isCountableAction(evt.EventType) {
+		category := classifyAction(evt)
+		if category == actionNone {
+			continue
+		}
+
+		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+		windowIndex := int(timeSeconds / windowSize)
+		if windowIndex >= numWindows {
+			windowIndex = numWindows - 1
+		}
+		windows[windowIndex][category]++
+		totals[category]++
+
+		// Kamerabewegungen zählen nicht zur APM, definieren aber die Fixationen
+		if category == actionCamera {
+			x, y, ok := getCameraTarget(evt.Data)
+			if !ok {
+				continue
+			}
+			if current == nil || math.Hypot(x-current.X, y-current.Y) > fixationDistance {
+				if current != nil {
+					current.End = timeSeconds
+				}
+				current = &fixation{Start: timeSeconds, X: x, Y: y}
+				fixations = append(fixations, current)
+			}
 			continue
 		}
 
 		totalActions++
+		phase := models.PhaseAt(timeSeconds)
+		phaseActions[phase]++
 
-		// EAPM: Filtere Spam-Aktionen
-		if evt.Loop-lastActionLoop >= minLoopsBetweenActions {
+		// EAPM: Filtere identische Aktionen kurz hintereinander (Spam)
+		key := actionKey(category, evt)
+		if evt.Loop-lastActionLoop >= minLoopsBetweenActions || key != lastActionKey {
 			effectiveActions++
+			phaseEffective[phase]++
 		}
 		lastActionLoop = evt.Loop
+		lastActionKey = key
 
-		// Ordne Aktion einem Zeitfenster zu
-		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
-		windowIndex := int(timeSeconds / windowSize)
-		actionWindows[windowIndex]++
+		if current != nil {
+			if current.Actions == 0 {
+				current.FirstAction = timeSeconds
+			}
+			current.Actions++
+		}
+	}
+	if current != nil {
+		current.End = gameDuration
 	}
 
 	// Berechne Durchschnitts-APM
@@ -62,101 +150,135 @@ func (aa *APMAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameDu
 	if gameDurationMinutes > 0 {
 		analysis.AverageAPM = float64(totalActions) / gameDurationMinutes
 		analysis.EAPM = float64(effectiveActions) / gameDurationMinutes
+		analysis.Breakdown = toBreakdown(totals, gameDurationMinutes)
 	}
 
-	// Erstelle Timeline und finde Peak-APM
+	// APM pro Spielphase
+	for _, phase := range models.GamePhases(gameDuration) {
+		phaseMinutes := phase.Duration() / 60.0
+		if phaseMinutes <= 0 {
+			continue
+		}
+		analysis.Phases = append(analysis.Phases, models.APMPhase{
+			Phase: phase.Name,
+			Start: phase.Start,
+			End:   phase.End,
+			APM:   float64(phaseActions[phase.Name]) / phaseMinutes,
+			EAPM:  float64(phaseEffective[phase.Name]) / phaseMinutes,
+		})
+	}
+
+	// Erstelle Timeline in zeitlicher Reihenfolge und finde Peak-APM
 	var peakAPM float64
-	for windowIndex, actions := range actionWindows {
-		// Konvertiere zu APM für dieses Fenster
-		apm := float64(actions) * (60.0 / windowSize)
-		if apm > peakAPM {
+	for i, counts := range windows {
+		// Letztes Fenster ist ggf. kürzer
+		windowMinutes := windowSize / 60.0
+		if rest := gameDuration - float64(i)*windowSize; rest < windowSize {
+			windowMinutes = rest / 60.0
+		}
+		if windowMinutes <= 0 {
+			continue
+		}
+
+		breakdown := toBreakdown(counts, windowMinutes)
+		apm := breakdown.Commands + breakdown.Selections + breakdown.ControlGroupRecalls + breakdown.ControlGroupSets
+		// Sehr kurze Restfenster verzerren den Peak
+		if apm > peakAPM && windowMinutes >= windowSize/120.0 {
 			peakAPM = apm
 		}
 
 		analysis.APMTimeline = append(analysis.APMTimeline, models.APMPoint{
-			Time: float64(windowIndex) * windowSize,
-			APM:  apm,
+			Time:      float64(i) * windowSize,
+			APM:       apm,
+			Breakdown: breakdown,
 		})
 	}
 	analysis.PeakAPM = peakAPM
 
-	// Sortiere Timeline nach Zeit
-	sortAPMTimeline(analysis.APMTimeline)
+	analysis.PAC = calculatePAC(fixations, gameDurationMinutes)
 
 	return analysis
 }
 
-// isCountableAction prüft ob ein Event als APM-Aktion zählt (vereinfachte Namen)
-func isCountableAction(eventType string) bool {
-	switch eventType {
-	case "Cmd", "CmdUpdateTargetPoint", "CmdUpdateTargetUnit",
-		"SelectionDelta", "ControlGroupUpdate":
-		return true
+// classifyAction ordnet ein Game-Event einer Aktionskategorie zu
+func classifyAction(evt parser.GameEvent) int {
+	switch evt.EventType {
+	case "Cmd", "CmdUpdateTargetPoint", "CmdUpdateTargetUnit":
+		return actionCommand
+	case "SelectionDelta":
+		return actionSelection
+	case "ControlGroupUpdate":
+		if parser.GetInt(evt.Data, "controlGroupUpdate") == parser.ControlGroupRecall {
+			return actionControlGroupRecall
+		}
+		// Set, Append, Steal und Clear verändern die Gruppe
+		return actionControlGroupSet
+	case "CameraUpdate":
+		return actionCamera
 	}
-	return false
+	return actionNone
 }
 
-// sortAPMTimeline sortiert die Timeline nach Zeit
-func sortAPMTimeline(timeline []models.APMPoint) {
-	// Simple Bubble Sort (Liste ist klein)
-	for i := 0; i < len(timeline)-1; i++ {
-		for j := 0; j < len(timeline)-i-1; j++ {
-			if timeline[j].Time > timeline[j+1].Time {
-				timeline[j], timeline[j+1] = timeline[j+1], timeline[j]
-			}
-		}
+// actionKey erzeugt einen Schlüssel, um identische Aktionen zu erkennen
+func actionKey(category int, evt parser.GameEvent) string {
+	switch category {
+	case actionCommand:
+		return fmt.Sprintf("cmd:%d", evt.AbilityLink())
+	case actionControlGroupRecall, actionControlGroupSet:
+		return fmt.Sprintf("cg:%d:%d", parser.GetInt(evt.Data, "controlGroupUpdate"), parser.GetInt(evt.Data, "controlGroupIndex"))
 	}
+	return evt.EventType
 }
 
-// GenerateSuggestions erstellt Verbesserungsvorschläge
-func (aa *APMAnalyzer) GenerateSuggestions(analysis *models.APMAnalysis) []models.Suggestion {
-	var suggestions []models.Suggestion
-
-	if analysis == nil {
-		return suggestions
+// toBreakdown rechnet Aktionszahlen in Aktionen pro Minute um
+func toBreakdown(counts [actionNone]int, minutes float64) models.APMBreakdown {
+	return models.APMBreakdown{
+		Commands:            float64(counts[actionCommand]) / minutes,
+		Selections:          float64(counts[actionSelection]) / minutes,
+		ControlGroupRecalls: float64(counts[actionControlGroupRecall]) / minutes,
+		ControlGroupSets:    float64(counts[actionControlGroupSet]) / minutes,
+		CameraMoves:         float64(counts[actionCamera]) / minutes,
 	}
+}
 
-	// APM-basierte Vorschläge
-	if analysis.AverageAPM < 50 {
-		suggestions = append(suggestions, models.Suggestion{
-			Priority:    "medium",
-			Category:    "micro",
-			Title:       "APM steigern",
-			Description: "Deine APM von %.0f ist niedrig. Übe schnellere Eingaben und Hotkey-Nutzung.",
-			TargetValue: "> 80 APM",
-		})
-	}
+// calculatePAC berechnet die Perception-Action-Cycle Metriken
+func calculatePAC(fixations []*fixation, gameDurationMinutes float64) *models.PACAnalysis {
+	pac := &models.PACAnalysis{}
 
-	// EAPM vs APM Verhältnis
-	if analysis.AverageAPM > 0 {
-		eapmRatio := analysis.EAPM / analysis.AverageAPM
-		if eapmRatio < 0.6 {
-			suggestions = append(suggestions, models.Suggestion{
-				Priority:    "low",
-				Category:    "micro",
-				Title:       "Weniger Spam-Aktionen",
-				Description: "Dein EAPM/APM-Verhältnis zeigt viele ineffektive Aktionen. Fokussiere auf sinnvolle Befehle.",
-				TargetValue: "> 70% EAPM/APM",
-			})
+	var totalActions int
+	var totalDuration, totalLatency float64
+	for _, f := range fixations {
+		// Nur Fixationen mit Aktion zählen als PAC
+		if f.Actions == 0 {
+			continue
 		}
+		pac.Fixations++
+		totalActions += f.Actions
+		totalDuration += f.End - f.Start
+		totalLatency += f.FirstAction - f.Start
 	}
 
-	// APM-Einbrüche erkennen
-	if len(analysis.APMTimeline) > 2 {
-		avgAPM := analysis.AverageAPM
-		for _, point := range analysis.APMTimeline {
-			if point.APM < avgAPM*0.3 && point.Time > 120 { // Ignoriere erste 2 Minuten
-				suggestions = append(suggestions, models.Suggestion{
-					Priority:    "low",
-					Category:    "micro",
-					Title:       "APM-Einbruch erkannt",
-					Description: "Bei %.0f:%02.0f fiel deine APM deutlich ab. Versuche, gleichmäßig aktiv zu bleiben.",
-					Timestamp:   point.Time,
-				})
-				break // Nur einen Vorschlag pro Typ
-			}
-		}
+	if pac.Fixations == 0 {
+		return pac
 	}
 
-	return suggestions
+	if gameDurationMinutes > 0 {
+		pac.PACPerMinute = float64(pac.Fixations) / gameDurationMinutes
+	}
+	pac.ActionsPerPAC = float64(totalActions) / float64(pac.Fixations)
+	pac.AverageFixationDuration = totalDuration / float64(pac.Fixations)
+	pac.AverageReactionLatency = totalLatency / float64(pac.Fixations)
+
+	return pac
+}
+
+// getCameraTarget extrahiert die Kameraposition in Map-Zellen (ohne m_ Präfix)
+// Zoom- und Pitch-Updates haben kein Ziel; dann ist ok false
+func getCameraTarget(data map[string]interface{}) (x, y float64, ok bool) {
+	target, ok := data["target"].(map[string]interface{})
+	if !ok {
+		return 0, 0, false
+	}
+	// Kamerakoordinaten sind in 1/256 Map-Zellen
+	return float64(parser.GetInt(target, "x")) / 256, float64(parser.GetInt(target, "y")) / 256, true
 }
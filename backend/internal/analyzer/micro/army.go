@@ -41,6 +41,7 @@ func (aa *ArmyAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameD
 	const sampleInterval = 30.0
 	var lastSampleTime float64
 	var peakArmyValue int
+	unitsLost := make(map[string]int) // Phase -> verlorene Armee-Einheiten
 
 	for _, evt := range events.TrackerEvents {
 		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
@@ -56,7 +57,7 @@ func (aa *ArmyAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameD
 			unitType := getUnitTypeFromEvent(evt.Data)
 			unitTag := getUnitTagFromEvent(evt.Data)
 
-			if unitType != "" && isArmyUnit(unitType) {
+			if unitType != "" && parser.IsArmyUnit(unitType) {
 				info := &unitInfo{
 					UnitType:    unitType,
 					MineralCost: getUnitMineralCost(unitType),
@@ -72,6 +73,7 @@ func (aa *ArmyAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameD
 			if info, exists := livingUnits[unitTag]; exists {
 				unitCounts[info.UnitType]--
 				delete(livingUnits, unitTag)
+				unitsLost[models.PhaseAt(timeSeconds)]++
 			}
 		}
 
@@ -96,6 +98,26 @@ func (aa *ArmyAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameD
 
 	analysis.PeakArmyValue = peakArmyValue
 
+	// Armeewerte pro Spielphase
+	for _, phase := range models.GamePhases(gameDuration) {
+		p := models.ArmyPhase{Phase: phase.Name, Start: phase.Start, End: phase.End, UnitsLost: unitsLost[phase.Name]}
+		var sum, count int
+		for _, point := range analysis.ArmyTimeline {
+			if !phase.Contains(point.Time) {
+				continue
+			}
+			sum += point.Value
+			count++
+			if point.Value > p.PeakArmyValue {
+				p.PeakArmyValue = point.Value
+			}
+		}
+		if count > 0 {
+			p.AverageArmyValue = float64(sum) / float64(count)
+		}
+		analysis.Phases = append(analysis.Phases, p)
+	}
+
 	// Erstelle finale Einheitenkomposition
 	for unitType, count := range unitCounts {
 		if count > 0 {
@@ -153,53 +175,6 @@ func getUnitTagFromEvent(data map[string]interface{}) int {
 	return 0
 }
 
-// isArmyUnit prüft ob eine Einheit zur Armee zählt
-func isArmyUnit(unitType string) bool {
-	// Gebäude und Worker ausschließen
-	lowerType := strings.ToLower(unitType)
-
-	// Worker
-	workers := []string{"scv", "probe", "drone"}
-	for _, w := range workers {
-		if strings.Contains(lowerType, w) {
-			return false
-		}
-	}
-
-	// Gebäude haben typischerweise bestimmte Suffixe oder Namen
-	buildings := []string{
-		"commandcenter", "orbitalcommand", "planetaryfortress",
-		"supplydepot", "barracks", "factory", "starport",
-		"engineeringbay", "armory", "ghostacademy", "fusioncore",
-		"bunker", "missileturret", "sensortower", "refinery",
-		"nexus", "pylon", "gateway", "forge", "cyberneticscore",
-		"roboticsfacility", "roboticsbay", "stargate", "fleetbeacon",
-		"twilightcouncil", "templararchive", "darkshrine",
-		"photoncannon", "shieldbattery", "assimilator", "warpgate",
-		"hatchery", "lair", "hive", "spawningpool", "evolutionchamber",
-		"roachwarren", "banelingnest", "hydraliskden", "lurkerden",
-		"spire", "greaterspire", "infestationpit", "ultraliscavern",
-		"nydusnetwork", "nyduscanal", "spinecrawler", "sporecrawler",
-		"extractor", "creeptumor",
-	}
-
-	for _, b := range buildings {
-		if strings.Contains(lowerType, b) {
-			return false
-		}
-	}
-
-	// Larva und Eggs ausschließen
-	excluded := []string{"larva", "egg", "cocoon", "locust", "broodling", "interceptor"}
-	for _, e := range excluded {
-		if strings.Contains(lowerType, e) {
-			return false
-		}
-	}
-
-	return true
-}
-
 // calculateArmyValue berechnet den Gesamtwert der Armee
 func calculateArmyValue(units map[int]*unitInfo) int {
 	total := 0
@@ -268,37 +243,3 @@ func getUnitGasCost(unitType string) int {
 	}
 	return 25 // Default
 }
-
-// GenerateSuggestions erstellt Verbesserungsvorschläge
-func (aa *ArmyAnalyzer) GenerateSuggestions(analysis *models.ArmyAnalysis) []models.Suggestion {
-	var suggestions []models.Suggestion
-
-	if analysis == nil {
-		return suggestions
-	}
-
-	// Prüfe auf Armee-Verluste ohne Gegenwert
-	if len(analysis.ArmyTimeline) > 2 {
-		for i := 1; i < len(analysis.ArmyTimeline); i++ {
-			current := analysis.ArmyTimeline[i]
-			previous := analysis.ArmyTimeline[i-1]
-
-			// Signifikanter Wertverlust (> 50% in einem Intervall)
-			if previous.Value > 0 {
-				lossRatio := float64(previous.Value-current.Value) / float64(previous.Value)
-				if lossRatio > 0.5 && previous.Value > 500 {
-					suggestions = append(suggestions, models.Suggestion{
-						Priority:    "high",
-						Category:    "micro",
-						Title:       "Große Armeeverluste",
-						Description: "Bei %.0f:%02.0f hast du über 50%% deiner Armee verloren. Achte auf besseres Engagement.",
-						Timestamp:   current.Time,
-					})
-					break // Nur einen Vorschlag
-				}
-			}
-		}
-	}
-
-	return suggestions
-}
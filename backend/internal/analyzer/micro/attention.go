@@ -0,0 +1,393 @@
+package micro
+
+import (
+	"math"
+	"sort"
+
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// AttentionAnalyzer rekonstruiert die Kameraposition und misst, wohin der Spieler schaut
+type AttentionAnalyzer struct{}
+
+// NewAttentionAnalyzer erstellt einen neuen AttentionAnalyzer
+func NewAttentionAnalyzer() *AttentionAnalyzer {
+	return &AttentionAnalyzer{}
+}
+
+const (
+	// baseRadius ist der Abstand (Map-Zellen), in dem die Kamera als "an der Basis" gilt
+	baseRadius = 15.0
+	// armyRadius ist der Abstand zum Armee-Schwerpunkt, in dem die Kamera als "bei der Armee" gilt
+	armyRadius = 15.0
+	// armySampleInterval ist der Abstand (Sekunden) zwischen Armee-Positionssamples
+	armySampleInterval = 5.0
+	// workerGapThreshold: Pausen zwischen zwei Workern ab dieser Dauer gelten als Produktionslücke
+	workerGapThreshold = 20.0
+	// workerSaturation: ab dieser Worker-Anzahl sind Lücken gewollt
+	workerSaturation = 66
+	// awayShareThreshold: Anteil der Lücke außerhalb der Basen, ab dem sie gemeldet wird
+	awayShareThreshold = 0.7
+	// heatmapCellSize ist die Kachelgröße der Heatmap in Map-Zellen
+	heatmapCellSize = 8
+)
+
+// Bereiche, auf die die Kamera gerichtet sein kann
+const (
+	locationMain      = "main"
+	locationNatural   = "natural"
+	locationArmy      = "army"
+	locationElsewhere = "elsewhere"
+)
+
+// mapPoint ist eine Position in Map-Zellen
+type mapPoint struct {
+	X, Y float64
+}
+
+// timedPoint ist eine Position ab einem Zeitpunkt
+type timedPoint struct {
+	Time  float64
+	Point mapPoint
+	Valid bool
+}
+
+// cameraSpan ist ein Zeitraum mit unveränderter Kameraposition
+type cameraSpan struct {
+	Start, End float64
+	Point      mapPoint
+	Location   string
+}
+
+// Analyze analysiert die Kamera-Aufmerksamkeit eines Spielers
+func (aa *AttentionAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameDuration float64) *models.AttentionAnalysis {
+	if events == nil || gameDuration <= 0 {
+		return nil
+	}
+
+	analysis := &models.AttentionAnalysis{
+		AwayGaps:        []models.AttentionGap{},
+		HeatmapCellSize: heatmapCellSize,
+		Heatmap:         []models.HeatmapCell{},
+	}
+
+	townHalls, armySamples, workerBirths := collectAttentionContext(events, playerID, collectArmyOrders(events, playerID))
+	if len(townHalls) == 0 {
+		return analysis
+	}
+
+	natural := naturalIndex(townHalls)
+
+	// Kamera-Spannen (Spielstart: Kamera auf der Main)
+	spans := []*cameraSpan{{Start: 0, Point: townHalls[0].Point}}
+	for _, evt := range events.GameEvents {
+		if evt.PlayerID != playerID || evt.EventType != "CameraUpdate" {
+			continue
+		}
+		x, y, ok := getCameraTarget(evt.Data)
+		if !ok {
+			continue
+		}
+		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+		last := spans[len(spans)-1]
+		last.End = timeSeconds
+		spans = append(spans, &cameraSpan{Start: timeSeconds, Point: mapPoint{X: x, Y: y}})
+	}
+	spans[len(spans)-1].End = gameDuration
+
+	// Bereiche und Heatmap
+	heatmap := make(map[[2]int]float64)
+	for _, span := range spans {
+		duration := span.End - span.Start
+		if duration <= 0 {
+			continue
+		}
+		span.Location = classifyCameraLocation(span.Point, span.Start, townHalls, natural, armySamples)
+
+		switch span.Location {
+		case locationMain:
+			analysis.TimeAtMain += duration
+		case locationNatural:
+			analysis.TimeAtNatural += duration
+		case locationArmy:
+			analysis.TimeAtArmy += duration
+		default:
+			analysis.TimeElsewhere += duration
+		}
+
+		cell := [2]int{int(span.Point.X) / heatmapCellSize, int(span.Point.Y) / heatmapCellSize}
+		heatmap[cell] += duration
+	}
+
+	total := analysis.TimeAtMain + analysis.TimeAtNatural + analysis.TimeAtArmy + analysis.TimeElsewhere
+	if total > 0 {
+		analysis.MainShare = analysis.TimeAtMain / total * 100
+		analysis.NaturalShare = analysis.TimeAtNatural / total * 100
+		analysis.ArmyShare = analysis.TimeAtArmy / total * 100
+		analysis.ElsewhereShare = analysis.TimeElsewhere / total * 100
+	}
+
+	for cell, seconds := range heatmap {
+		analysis.Heatmap = append(analysis.Heatmap, models.HeatmapCell{
+			X:       cell[0] * heatmapCellSize,
+			Y:       cell[1] * heatmapCellSize,
+			Seconds: seconds,
+		})
+	}
+	sort.Slice(analysis.Heatmap, func(i, j int) bool {
+		if analysis.Heatmap[i].Y != analysis.Heatmap[j].Y {
+			return analysis.Heatmap[i].Y < analysis.Heatmap[j].Y
+		}
+		return analysis.Heatmap[i].X < analysis.Heatmap[j].X
+	})
+
+	// Worker-Produktionslücken, in denen die Kamera woanders war
+	for i := 1; i < len(workerBirths); i++ {
+		start, end := workerBirths[i-1].Time, workerBirths[i].Time
+		if end-start < workerGapThreshold || workerBirths[i-1].Count >= workerSaturation {
+			continue
+		}
+		if gap, ok := evaluateAwayGap(start, end, spans); ok {
+			analysis.AwayGaps = append(analysis.AwayGaps, gap)
+			analysis.AwayGapTime += gap.Duration
+		}
+	}
+
+	return analysis
+}
+
+// workerBirth ist ein fertiger Worker mit der danach lebenden Worker-Anzahl
+type workerBirth struct {
+	Time  float64
+	Count int
+}
+
+// armyOrder ist ein zielgerichteter Befehl an eigene Armee-Einheiten
+type armyOrder struct {
+	Time   float64
+	Units  []int // unitTagIndex der befehligten Armee-Einheiten
+	Target mapPoint
+}
+
+// collectArmyOrders sammelt Move-, Angriffs- und andere Zielbefehle, deren Auswahl Armee-Einheiten enthält
+// Befehle an Worker und Gebäude (Abbauen, Rally, Bauplätze) und Befehle auf neutrale Ziele zählen nicht
+func collectArmyOrders(events *parser.ParsedEvents, playerID int) []armyOrder {
+	unitTypes := gameTagTypes(events)
+	selections := make(parser.Selections)
+	var orders []armyOrder
+
+	for _, evt := range events.GameEvents {
+		if evt.PlayerID != playerID {
+			continue
+		}
+		switch evt.EventType {
+		case "SelectionDelta", "ControlGroupUpdate":
+			selections.Apply(evt)
+
+		case "Cmd":
+			x, y, ok := evt.CmdTarget()
+			if !ok || evt.CmdTargetsNeutral() {
+				continue
+			}
+			var units []int
+			for _, tag := range selections.Active() {
+				if parser.IsArmyUnit(unitTypes[tag]) {
+					units = append(units, tag>>18)
+				}
+			}
+			if len(units) == 0 {
+				continue
+			}
+			orders = append(orders, armyOrder{
+				Time:   parser.LoopsToRealSeconds(evt.Loop),
+				Units:  units,
+				Target: mapPoint{X: x, Y: y},
+			})
+		}
+	}
+	return orders
+}
+
+// collectAttentionContext sammelt Basen, Armee-Schwerpunkte und Worker-Geburten aus Tracker-Events
+// Armee-Positionen stammen aus UnitPositions (nur kämpfende Einheiten) und den Zielen eigener Befehle
+func collectAttentionContext(events *parser.ParsedEvents, playerID int, orders []armyOrder) ([]timedPoint, []timedPoint, []workerBirth) {
+	var townHalls []timedPoint
+	var armySamples []timedPoint
+	var workerBirths []workerBirth
+
+	owners := make(map[int]int)          // unitTagIndex -> Spieler
+	armyUnits := make(map[int]*mapPoint) // unitTagIndex -> letzte bekannte Position
+	workers := make(map[int]bool)        // unitTagIndex -> lebender Worker
+	nextSample := 0.0
+	nextOrder := 0
+
+	addWorker := func(index int, timeSeconds float64) {
+		if workers[index] {
+			return
+		}
+		workers[index] = true
+		// Start-Worker zählen nicht als Produktion
+		if timeSeconds > 0 {
+			workerBirths = append(workerBirths, workerBirth{Time: timeSeconds, Count: len(workers)})
+		}
+	}
+
+	for _, evt := range events.TrackerEvents {
+		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+		index := parser.GetInt(evt.Data, "unitTagIndex")
+
+		// Befehlsziele bis zu diesem Zeitpunkt übernehmen: die Einheiten sind dorthin unterwegs
+		for nextOrder < len(orders) && orders[nextOrder].Time <= timeSeconds {
+			for _, unitIndex := range orders[nextOrder].Units {
+				if pos, ok := armyUnits[unitIndex]; ok {
+					*pos = orders[nextOrder].Target
+				}
+			}
+			nextOrder++
+		}
+
+		// Armee-Schwerpunkt vor dem Event samplen
+		if timeSeconds >= nextSample {
+			armySamples = append(armySamples, armyCentroid(armyUnits, timeSeconds))
+			nextSample = timeSeconds + armySampleInterval
+		}
+
+		switch evt.EventType {
+		case "UnitBorn", "UnitInit":
+			owner := getUnitPlayerID(evt.Data)
+			owners[index] = owner
+			if owner != playerID {
+				continue
+			}
+			unitType := getUnitTypeFromEvent(evt.Data)
+			pos := mapPoint{X: float64(parser.GetInt(evt.Data, "x")), Y: float64(parser.GetInt(evt.Data, "y"))}
+			switch {
+			case parser.IsTownHall(unitType):
+				townHalls = append(townHalls, timedPoint{Time: timeSeconds, Point: pos, Valid: true})
+			case parser.IsWorker(unitType):
+				if evt.EventType == "UnitBorn" {
+					addWorker(index, timeSeconds)
+				}
+			case parser.IsArmyUnit(unitType):
+				// UnitInit: Warp-Ins erscheinen bereits an ihrer Position
+				armyUnits[index] = &pos
+			}
+
+		case "UnitTypeChange":
+			// Zerg-Einheiten schlüpfen per Typwechsel aus dem Ei
+			if owners[index] != playerID {
+				continue
+			}
+			unitType := getUnitTypeFromEvent(evt.Data)
+			if parser.IsWorker(unitType) {
+				addWorker(index, timeSeconds)
+			} else if parser.IsArmyUnit(unitType) {
+				if _, ok := armyUnits[index]; !ok {
+					armyUnits[index] = &mapPoint{}
+				}
+			}
+
+		case "UnitDied":
+			delete(armyUnits, index)
+			delete(workers, index)
+
+		case "UnitPositions":
+			for _, p := range evt.UnitPositions() {
+				if pos, ok := armyUnits[p.UnitIndex]; ok {
+					pos.X = p.X
+					pos.Y = p.Y
+				}
+			}
+		}
+
+	}
+
+	return townHalls, armySamples, workerBirths
+}
+
+// armyCentroid berechnet den Schwerpunkt aller Armee-Einheiten mit bekannter Position
+func armyCentroid(units map[int]*mapPoint, timeSeconds float64) timedPoint {
+	var sumX, sumY float64
+	count := 0
+	for _, pos := range units {
+		if pos.X == 0 && pos.Y == 0 {
+			continue
+		}
+		sumX += pos.X
+		sumY += pos.Y
+		count++
+	}
+	if count == 0 {
+		return timedPoint{Time: timeSeconds}
+	}
+	return timedPoint{
+		Time:  timeSeconds,
+		Point: mapPoint{X: sumX / float64(count), Y: sumY / float64(count)},
+		Valid: true,
+	}
+}
+
+// classifyCameraLocation ordnet eine Kameraposition einem Bereich zu
+// natural ist der Index der Natural in townHalls (-1 = keine, siehe naturalIndex)
+func classifyCameraLocation(p mapPoint, timeSeconds float64, townHalls []timedPoint, natural int, armySamples []timedPoint) string {
+	if pointDistance(p, townHalls[0].Point) <= baseRadius {
+		return locationMain
+	}
+	if natural >= 0 && townHalls[natural].Time <= timeSeconds && pointDistance(p, townHalls[natural].Point) <= baseRadius {
+		return locationNatural
+	}
+
+	// Letztes Armee-Sample vor diesem Zeitpunkt
+	i := sort.Search(len(armySamples), func(i int) bool { return armySamples[i].Time > timeSeconds })
+	if i > 0 && armySamples[i-1].Valid && pointDistance(p, armySamples[i-1].Point) <= armyRadius {
+		return locationArmy
+	}
+
+	return locationElsewhere
+}
+
+// evaluateAwayGap prüft, ob die Kamera während einer Produktionslücke überwiegend nicht an den Basen war
+func evaluateAwayGap(start, end float64, spans []*cameraSpan) (models.AttentionGap, bool) {
+	gap := models.AttentionGap{Start: start, End: end, Duration: end - start}
+
+	var away, atArmy float64
+	for _, span := range spans {
+		overlap := math.Min(span.End, end) - math.Max(span.Start, start)
+		if overlap <= 0 {
+			continue
+		}
+		switch span.Location {
+		case locationMain, locationNatural:
+		case locationArmy:
+			away += overlap
+			atArmy += overlap
+		default:
+			away += overlap
+		}
+	}
+
+	share := away / gap.Duration
+	if share < awayShareThreshold {
+		return gap, false
+	}
+
+	gap.AwayShare = share * 100
+	gap.Location = locationElsewhere
+	if atArmy > away-atArmy {
+		gap.Location = locationArmy
+	}
+	return gap, true
+}
+
+// pointDistance berechnet den Abstand zweier Positionen
+func pointDistance(a, b mapPoint) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+// naturalIndex bestimmt die Natural unter den eigenen Basen (Index 0 = Main, -1 = keine)
+func naturalIndex(bases []timedPoint) int {
+	return parser.NaturalIndex(len(bases), func(i int) (float64, float64) {
+		return bases[i].Point.X, bases[i].Point.Y
+	})
+}
@@ -0,0 +1,267 @@
+package micro
+
+import (
+	"math"
+	"sort"
+
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// HarassmentAnalyzer erkennt gegnerischen Harass und misst die Reaktionszeit des Verteidigers
+type HarassmentAnalyzer struct{}
+
+// NewHarassmentAnalyzer erstellt einen neuen HarassmentAnalyzer
+func NewHarassmentAnalyzer() *HarassmentAnalyzer {
+	return &HarassmentAnalyzer{}
+}
+
+const (
+	// harassBaseRadius ist der Abstand (Map-Zellen) zu einer eigenen Basis, in dem Worker-Verluste als Harass zählen
+	harassBaseRadius = 20.0
+	// harassIncidentGap: Worker-Verluste mit kürzerem Abstand gehören zum selben Harass
+	harassIncidentGap = 20.0
+	// harassLeadTime ist die Zeit vor dem ersten Kill, in der gegnerische Einheiten den Harass beginnen können
+	harassLeadTime = 30.0
+	// reactionRadius ist der Abstand zum Harass, in dem Befehle oder Einheiten als Reaktion zählen
+	reactionRadius = 15.0
+	// reactionTimeout: ohne Reaktion bis zu dieser Zeit nach dem Harass gilt er als unbeantwortet
+	reactionTimeout = 30.0
+	// workerCost ist der Mineralien-Preis eines Workers
+	workerCost = 50
+	// workerIncomePerSecond ist das Mineralien-Einkommen eines Workers pro Sekunde
+	workerIncomePerSecond = 0.92
+)
+
+// harassIncident ist ein Harass während der Analyse
+type harassIncident struct {
+	incident  models.HarassmentIncident
+	baseIndex int
+	pos       mapPoint
+	attackers map[string]bool
+	deaths    []float64
+}
+
+// Analyze analysiert Harass an den Basen eines Spielers
+func (ha *HarassmentAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameDuration float64) *models.HarassmentAnalysis {
+	if events == nil {
+		return nil
+	}
+
+	analysis := &models.HarassmentAnalysis{
+		Incidents: []models.HarassmentIncident{},
+	}
+
+	var bases []timedPoint
+	var incidents []*harassIncident
+	var workerBirths []float64
+	var enemyPresence, ownArmyPresence []timedPoint
+	owners := make(map[int]int)       // unitTagIndex -> Spieler
+	unitTypes := make(map[int]string) // unitTagIndex -> Einheitentyp
+	workers := make(map[int]bool)     // unitTagIndex -> eigener Worker
+
+	for _, evt := range events.TrackerEvents {
+		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+		index := parser.GetInt(evt.Data, "unitTagIndex")
+
+		switch evt.EventType {
+		case "UnitBorn", "UnitInit":
+			owner := getUnitPlayerID(evt.Data)
+			unitType := getUnitTypeFromEvent(evt.Data)
+			owners[index] = owner
+			unitTypes[index] = unitType
+			if owner != playerID {
+				continue
+			}
+			if parser.IsTownHall(unitType) {
+				pos := mapPoint{X: float64(parser.GetInt(evt.Data, "x")), Y: float64(parser.GetInt(evt.Data, "y"))}
+				bases = append(bases, timedPoint{Time: timeSeconds, Point: pos, Valid: true})
+			}
+			if parser.IsWorker(unitType) && evt.EventType == "UnitBorn" {
+				workers[index] = true
+				if timeSeconds > 0 {
+					workerBirths = append(workerBirths, timeSeconds)
+				}
+			}
+
+		case "UnitTypeChange":
+			unitType := getUnitTypeFromEvent(evt.Data)
+			unitTypes[index] = unitType
+			// Zerg-Worker schlüpfen per Typwechsel aus dem Ei
+			if owners[index] == playerID && parser.IsWorker(unitType) && !workers[index] {
+				workers[index] = true
+				workerBirths = append(workerBirths, timeSeconds)
+			}
+
+		case "UnitDied":
+			if !workers[index] {
+				continue
+			}
+			delete(workers, index)
+
+			killer := parser.GetInt(evt.Data, "killerPlayerId")
+			if killer == 0 || killer == playerID {
+				continue
+			}
+			pos := mapPoint{X: float64(parser.GetInt(evt.Data, "x")), Y: float64(parser.GetInt(evt.Data, "y"))}
+			baseIndex := nearestBaseIndex(pos, bases)
+			if baseIndex < 0 {
+				continue
+			}
+
+			// Laufenden Harass an derselben Basis fortsetzen oder neuen beginnen
+			var current *harassIncident
+			for _, inc := range incidents {
+				if inc.baseIndex == baseIndex && timeSeconds-inc.incident.End <= harassIncidentGap {
+					current = inc
+				}
+			}
+			if current == nil {
+				current = &harassIncident{
+					incident:  models.HarassmentIncident{Start: timeSeconds, X: int(pos.X), Y: int(pos.Y)},
+					baseIndex: baseIndex,
+					pos:       pos,
+					attackers: make(map[string]bool),
+				}
+				incidents = append(incidents, current)
+			}
+			current.incident.End = timeSeconds
+			current.incident.WorkersLost++
+			current.deaths = append(current.deaths, timeSeconds)
+			if attacker := unitTypes[parser.GetInt(evt.Data, "killerUnitTagIndex")]; attacker != "" && owners[parser.GetInt(evt.Data, "killerUnitTagIndex")] == killer {
+				current.attackers[attacker] = true
+			}
+
+		case "UnitPositions":
+			for _, p := range evt.UnitPositions() {
+				pos := mapPoint{X: p.X, Y: p.Y}
+				owner := owners[p.UnitIndex]
+				switch {
+				case owner == playerID && parser.IsArmyUnit(unitTypes[p.UnitIndex]):
+					ownArmyPresence = append(ownArmyPresence, timedPoint{Time: timeSeconds, Point: pos, Valid: true})
+				case owner > 0 && owner != playerID && !parser.IsWorker(unitTypes[p.UnitIndex]):
+					enemyPresence = append(enemyPresence, timedPoint{Time: timeSeconds, Point: pos, Valid: true})
+				}
+			}
+		}
+	}
+
+	if len(incidents) == 0 {
+		return analysis
+	}
+
+	// Befehle des Verteidigers an Armee-Einheiten; Abbau-Befehle, Worker-Rallys
+	// und Bauplätze zählen nicht als Reaktion
+	var commands []timedPoint
+	for _, order := range collectArmyOrders(events, playerID) {
+		commands = append(commands, timedPoint{Time: order.Time, Point: order.Target, Valid: true})
+	}
+
+	// Verlorene Worker werden in Reihenfolge durch neue ersetzt
+	type workerDeath struct {
+		time     float64
+		incident *harassIncident
+	}
+	var allDeaths []workerDeath
+	for _, inc := range incidents {
+		for _, death := range inc.deaths {
+			allDeaths = append(allDeaths, workerDeath{time: death, incident: inc})
+		}
+	}
+	sort.Slice(allDeaths, func(i, j int) bool { return allDeaths[i].time < allDeaths[j].time })
+	birthIndex := 0
+	for _, death := range allDeaths {
+		for birthIndex < len(workerBirths) && workerBirths[birthIndex] <= death.time {
+			birthIndex++
+		}
+		replacedAt := gameDuration
+		if birthIndex < len(workerBirths) {
+			replacedAt = workerBirths[birthIndex]
+			birthIndex++
+		}
+		death.incident.incident.MiningTimeLost += replacedAt - death.time
+	}
+
+	natural := naturalIndex(bases)
+	var totalReaction float64
+	reactedCount := 0
+	for _, inc := range incidents {
+		result := inc.incident
+
+		// Harass beginnt mit der ersten gegnerischen Einheit an der Basis
+		basePos := bases[inc.baseIndex].Point
+		for _, p := range enemyPresence {
+			if p.Time < result.Start-harassLeadTime || p.Time >= result.Start {
+				continue
+			}
+			if pointDistance(p.Point, basePos) <= harassBaseRadius {
+				result.Start = p.Time
+				break
+			}
+		}
+
+		switch inc.baseIndex {
+		case 0:
+			result.Base = "main"
+		case natural:
+			result.Base = "natural"
+		default:
+			result.Base = "base"
+		}
+
+		result.AttackerTypes = []string{}
+		for attacker := range inc.attackers {
+			result.AttackerTypes = append(result.AttackerTypes, attacker)
+		}
+		sort.Strings(result.AttackerTypes)
+
+		// Reaktion: erster Armee-Befehl in den Bereich oder eigene Armee im Kampf dort
+		// (UnitPositions enthält nur Einheiten, die Schaden genommen oder ausgeteilt haben)
+		deadline := result.End + reactionTimeout
+		reaction := math.MaxFloat64
+		for _, list := range [][]timedPoint{commands, ownArmyPresence} {
+			for _, p := range list {
+				if p.Time < result.Start || p.Time > deadline || p.Time >= reaction {
+					continue
+				}
+				if pointDistance(p.Point, inc.pos) <= reactionRadius || pointDistance(p.Point, basePos) <= reactionRadius {
+					reaction = p.Time
+					break
+				}
+			}
+		}
+		if reaction != math.MaxFloat64 {
+			result.Reacted = true
+			result.ReactionTime = reaction - result.Start
+			totalReaction += result.ReactionTime
+			reactedCount++
+		} else {
+			analysis.UnansweredIncidents++
+		}
+
+		result.EstimatedMineralsLost = result.WorkersLost*workerCost + int(result.MiningTimeLost*workerIncomePerSecond)
+
+		analysis.TotalWorkersLost += result.WorkersLost
+		analysis.TotalMiningTimeLost += result.MiningTimeLost
+		analysis.Incidents = append(analysis.Incidents, result)
+	}
+
+	if reactedCount > 0 {
+		analysis.AverageReactionTime = totalReaction / float64(reactedCount)
+	}
+
+	return analysis
+}
+
+// nearestBaseIndex findet die nächste eigene Basis im Harass-Radius (-1 = keine)
+func nearestBaseIndex(p mapPoint, bases []timedPoint) int {
+	best := -1
+	bestDist := harassBaseRadius
+	for i, b := range bases {
+		if d := pointDistance(p, b.Point); d <= bestDist {
+			bestDist = d
+			best = i
+		}
+	}
+	return best
+}
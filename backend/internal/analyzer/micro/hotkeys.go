@@ -0,0 +1,249 @@
+package micro
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// HotkeyAnalyzer analysiert die Nutzung von Control Groups und Kamera-Hotkeys
+type HotkeyAnalyzer struct{}
+
+// NewHotkeyAnalyzer erstellt einen neuen HotkeyAnalyzer
+func NewHotkeyAnalyzer() *HotkeyAnalyzer {
+	return &HotkeyAnalyzer{}
+}
+
+const (
+	// hotkeyFollowupLoops: Auswahländerungen kurz nach einem Recall (z.B. Larven wählen) zählen noch als Hotkey
+	hotkeyFollowupLoops = 32
+	// cameraHotkeyDistance ist der maximale Abstand (Map-Zellen) zu einer gespeicherten Kameraposition
+	cameraHotkeyDistance = 1.0
+	// roleMajority ist der Anteil, ab dem eine Rolle eine Gruppe dominiert
+	roleMajority = 0.75
+)
+
+// controlGroupState sammelt die Nutzung einer Control Group
+type controlGroupState struct {
+	sets       int
+	recalls    int
+	roleCounts map[string]int
+	unitTypes  map[string]bool
+}
+
+// Analyze analysiert die Hotkey-Nutzung eines Spielers
+func (ha *HotkeyAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameDuration float64) *models.HotkeyAnalysis {
+	if events == nil || gameDuration <= 0 {
+		return nil
+	}
+
+	analysis := &models.HotkeyAnalysis{
+		ControlGroups: []models.ControlGroupUsage{},
+	}
+
+	unitTypes := gameTagTypes(events)
+
+	// Auswahl und Control Groups
+	selections := make(parser.Selections)
+	groups := make(map[int]*controlGroupState)
+	selectionViaHotkey := false
+	lastRecallLoop := -hotkeyFollowupLoops - 1
+
+	// Kamera-Hotkeys
+	savedCameras := make(map[int][2]float64)
+	var lastCamX, lastCamY float64
+	hasCamera := false
+
+	for _, evt := range events.GameEvents {
+		if evt.PlayerID != playerID {
+			continue
+		}
+
+		switch evt.EventType {
+		case "SelectionDelta":
+			selections.Apply(evt)
+			if parser.GetInt(evt.Data, "controlGroupId") == parser.ActiveSelection {
+				selectionViaHotkey = evt.Loop-lastRecallLoop <= hotkeyFollowupLoops
+			}
+
+		case "ControlGroupUpdate":
+			index := parser.GetInt(evt.Data, "controlGroupIndex")
+			if index < 0 || index > 9 {
+				continue
+			}
+			state := groups[index]
+			if state == nil {
+				state = &controlGroupState{roleCounts: make(map[string]int), unitTypes: make(map[string]bool)}
+				groups[index] = state
+			}
+
+			selections.Apply(evt)
+			switch parser.GetInt(evt.Data, "controlGroupUpdate") {
+			case parser.ControlGroupRecall:
+				state.recalls++
+				analysis.TotalRecalls++
+				selectionViaHotkey = true
+				lastRecallLoop = evt.Loop
+				continue
+			case parser.ControlGroupClear:
+				continue
+			}
+
+			state.sets++
+			analysis.TotalSets++
+			if role := selectionRole(selections[index], unitTypes); role != "empty" {
+				state.roleCounts[role]++
+			}
+			for _, tag := range selections[index] {
+				if unitType, ok := unitTypes[tag]; ok {
+					state.unitTypes[unitType] = true
+				}
+			}
+
+		case "Cmd":
+			// Nur Produktionsbefehle ohne Ziel (Einheiten/Upgrades) zählen
+			if _, ok := evt.Data["abil"].(map[string]interface{}); !ok {
+				continue
+			}
+			if target, ok := evt.Data["data"].(map[string]interface{}); ok {
+				if _, isNone := target["None"]; !isNone {
+					continue
+				}
+			}
+			role := selectionRole(selections.Active(), unitTypes)
+			if role != "production" && role != "town_hall" {
+				continue
+			}
+			analysis.ProductionCommands++
+			if selectionViaHotkey {
+				analysis.ProductionViaHotkey++
+			}
+
+		case "CameraSave":
+			x, y, _ := getCameraTarget(evt.Data)
+			savedCameras[parser.GetInt(evt.Data, "which")] = [2]float64{x, y}
+			analysis.CameraSaves++
+
+		case "CameraUpdate":
+			x, y, ok := getCameraTarget(evt.Data)
+			if !ok {
+				continue
+			}
+			// Ein Kamera-Hotkey ist ein Sprung genau auf eine gespeicherte Position
+			if hasCamera && math.Hypot(x-lastCamX, y-lastCamY) > fixationDistance {
+				for _, pos := range savedCameras {
+					if math.Hypot(x-pos[0], y-pos[1]) <= cameraHotkeyDistance {
+						analysis.CameraHotkeyRecalls++
+						break
+					}
+				}
+			}
+			lastCamX, lastCamY = x, y
+			hasCamera = true
+		}
+	}
+
+	gameDurationMinutes := gameDuration / 60.0
+
+	// Control Groups in Tastenreihenfolge (1-9, 0)
+	indices := make([]int, 0, len(groups))
+	for index := range groups {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	for _, index := range indices {
+		state := groups[index]
+		usage := models.ControlGroupUsage{
+			Index:     (index + 1) % 10,
+			Sets:      state.sets,
+			Recalls:   state.recalls,
+			Role:      dominantRole(state.roleCounts),
+			UnitTypes: []string{},
+		}
+		if gameDurationMinutes > 0 {
+			usage.RecallsPerMinute = float64(state.recalls) / gameDurationMinutes
+		}
+		for unitType := range state.unitTypes {
+			usage.UnitTypes = append(usage.UnitTypes, unitType)
+		}
+		sort.Strings(usage.UnitTypes)
+		analysis.ControlGroups = append(analysis.ControlGroups, usage)
+	}
+
+	if gameDurationMinutes > 0 {
+		analysis.RecallsPerMinute = float64(analysis.TotalRecalls) / gameDurationMinutes
+		analysis.CameraHotkeysPerMinute = float64(analysis.CameraHotkeyRecalls) / gameDurationMinutes
+	}
+	if analysis.ProductionCommands > 0 {
+		analysis.ProductionHotkeyShare = float64(analysis.ProductionViaHotkey) / float64(analysis.ProductionCommands) * 100
+	}
+
+	return analysis
+}
+
+// gameTagTypes ordnet Game-Unit-Tags (Index << 18 + Recycle) ihren Einheitentyp zu
+// Bei Typwechseln gilt der letzte Typ
+func gameTagTypes(events *parser.ParsedEvents) map[int]string {
+	unitTypes := make(map[int]string)
+	for _, evt := range events.TrackerEvents {
+		switch evt.EventType {
+		case "UnitBorn", "UnitInit", "UnitTypeChange":
+			if unitType := getUnitTypeFromEvent(evt.Data); unitType != "" {
+				unitTypes[parser.GetUnitTag(evt.Data)] = unitType
+			}
+		}
+	}
+	return unitTypes
+}
+
+// selectionRole bestimmt die Rolle einer Auswahl anhand der Einheitentypen
+func selectionRole(tags []int, unitTypes map[int]string) string {
+	counts := make(map[string]int)
+	for _, tag := range tags {
+		if unitType, ok := unitTypes[tag]; ok {
+			counts[unitRole(unitType)]++
+		}
+	}
+	return dominantRole(counts)
+}
+
+// dominantRole gibt die Rolle mit klarer Mehrheit zurück
+func dominantRole(counts map[string]int) string {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return "empty"
+	}
+	for role, count := range counts {
+		if float64(count)/float64(total) >= roleMajority {
+			return role
+		}
+	}
+	return "mixed"
+}
+
+// unitRole ordnet einen Einheitentyp einer Hotkey-Rolle zu
+func unitRole(unitType string) string {
+	switch {
+	case parser.IsTownHall(unitType):
+		return "town_hall"
+	case parser.IsWorker(unitType):
+		return "workers"
+	}
+	switch strings.ToLower(unitType) {
+	case "barracks", "factory", "starport", "gateway", "warpgate",
+		"roboticsfacility", "stargate", "larva":
+		return "production"
+	}
+
+	if parser.IsArmyUnit(unitType) {
+		return "army"
+	}
+	return "tech"
+}
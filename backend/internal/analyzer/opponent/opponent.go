@@ -0,0 +1,151 @@
+package opponent
+
+import (
+	"math"
+	"sort"
+
+	"sc2-analytics/internal/analyzer/strategic"
+	"sc2-analytics/internal/models"
+)
+
+const (
+	// recentGames ist die Anzahl der gelisteten letzten direkten Duelle
+	recentGames = 10
+	// favouriteMaps ist die Anzahl der gelisteten Lieblingskarten
+	favouriteMaps = 5
+)
+
+// OpponentAnalyzer erstellt aus den gespeicherten Spielen eines Gegners sein Dossier
+type OpponentAnalyzer struct{}
+
+// NewOpponentAnalyzer erstellt einen neuen OpponentAnalyzer
+func NewOpponentAnalyzer() *OpponentAnalyzer {
+	return &OpponentAnalyzer{}
+}
+
+// Analyze fasst die Spiele eines Gegners zusammen; games ist nach Datum absteigend sortiert
+// und enthält bereits die erkannten Eröffnungen (Opening leer = nicht erkannt oder keine Analyse)
+// analyzed ist die Anzahl der Spiele je Matchup, für die eine Build Order vorlag
+func (oa *OpponentAnalyzer) Analyze(player models.Player, games []models.OpponentGame, analyzed map[string]int) *models.OpponentDossier {
+	dossier := &models.OpponentDossier{
+		Player:     player,
+		Names:      []string{},
+		Games:      len(games),
+		HeadToHead: models.HeadToHeadRecord{Recent: []models.OpponentGame{}},
+		Races:      []models.OpponentShare{},
+		Openings:   []models.OpponentOpening{},
+		Maps:       []models.OpponentShare{},
+	}
+	if len(games) == 0 {
+		return dossier
+	}
+
+	dossier.LastSeen = games[0].PlayedAt
+	dossier.FirstSeen = games[len(games)-1].PlayedAt
+	for _, n := range analyzed {
+		dossier.AnalyzedGames += n
+	}
+
+	seenNames := make(map[string]bool)
+	races := make(map[string]*models.OpponentShare)
+	maps := make(map[string]*models.OpponentShare)
+	openings := make(map[string]*models.OpponentOpening)
+	duration := 0
+	for _, g := range games {
+		if !seenNames[g.Name] {
+			seenNames[g.Name] = true
+			dossier.Names = append(dossier.Names, g.Name)
+		}
+		duration += g.Duration
+		countShare(races, g.Race, g.Result)
+		countShare(maps, g.Map, g.Result)
+
+		if g.Opening != "" {
+			matchup := strategic.Matchup(g.Race, g.OpponentRace)
+			key := matchup + ":" + g.Opening
+			if openings[key] == nil {
+				openings[key] = &models.OpponentOpening{Build: g.Opening, Name: g.OpeningName, Matchup: matchup}
+			}
+			openings[key].Games++
+		}
+
+		if !g.HeadToHead {
+			continue
+		}
+		h2h := &dossier.HeadToHead
+		h2h.Games++
+		// Ergebnis aus Sicht des Gegners: seine Niederlage ist unser Sieg
+		switch g.Result {
+		case "Loss":
+			h2h.Wins++
+		case "Win":
+			h2h.Losses++
+		}
+		if len(h2h.Recent) < recentGames {
+			h2h.Recent = append(h2h.Recent, g)
+		}
+	}
+
+	dossier.AverageDuration = duration / len(games)
+	if decided := dossier.HeadToHead.Wins + dossier.HeadToHead.Losses; decided > 0 {
+		dossier.HeadToHead.WinRate = percent(dossier.HeadToHead.Wins, decided)
+	}
+
+	dossier.Races = sortedShares(races, len(games), 0)
+	dossier.Maps = sortedShares(maps, len(games), favouriteMaps)
+
+	for _, o := range openings {
+		o.Percent = percent(o.Games, analyzed[o.Matchup])
+		dossier.Openings = append(dossier.Openings, *o)
+	}
+	sort.Slice(dossier.Openings, func(i, j int) bool {
+		a, b := dossier.Openings[i], dossier.Openings[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		if a.Matchup != b.Matchup {
+			return a.Matchup < b.Matchup
+		}
+		return a.Build < b.Build
+	})
+
+	return dossier
+}
+
+// countShare zählt ein Spiel für name, Siege aus Sicht des Gegners
+func countShare(shares map[string]*models.OpponentShare, name, result string) {
+	if shares[name] == nil {
+		shares[name] = &models.OpponentShare{Name: name}
+	}
+	shares[name].Games++
+	if result == "Win" {
+		shares[name].Wins++
+	}
+}
+
+// sortedShares sortiert Anteile nach Spielen absteigend und kürzt auf limit (0 = alle)
+func sortedShares(shares map[string]*models.OpponentShare, total, limit int) []models.OpponentShare {
+	result := []models.OpponentShare{}
+	for _, s := range shares {
+		s.Percent = percent(s.Games, total)
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Games != result[j].Games {
+			return result[i].Games > result[j].Games
+		}
+		return result[i].Name < result[j].Name
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// percent gibt part als Prozent von total zurück (eine Nachkommastelle)
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*1000) / 10
+}
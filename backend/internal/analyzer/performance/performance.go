@@ -0,0 +1,243 @@
+package performance
+
+import (
+	"math"
+	"sort"
+
+	"sc2-analytics/internal/analyzer/strategic"
+	"sc2-analytics/internal/models"
+)
+
+const (
+	// z ist das Quantil der Normalverteilung für 95 %-Konfidenzintervalle
+	z = 1.96
+	// WeakSpotMinGames ist die Mindestzahl entschiedener Spiele, ab der eine Schwachstelle gemeldet wird
+	WeakSpotMinGames = 10
+	// weakSpotAlpha ist das Signifikanzniveau des Binomialtests über alle getesteten Zellen (Holm-korrigiert)
+	weakSpotAlpha = 0.05
+)
+
+// PerformanceAnalyzer fasst die Spiele eines Benutzers je Matchup und Karte zusammen
+type PerformanceAnalyzer struct{}
+
+// NewPerformanceAnalyzer erstellt einen neuen PerformanceAnalyzer
+func NewPerformanceAnalyzer() *PerformanceAnalyzer {
+	return &PerformanceAnalyzer{}
+}
+
+// cellGames sammelt die Spiele einer Zelle
+type cellGames struct {
+	matchup string
+	gameMap string
+	games   []models.PerformanceGame
+}
+
+// Analyze erstellt die Matchup-Karten-Matrix und sucht Schwachstellen: Matchups und Karten, deren
+// Win Rate im einseitigen exakten Binomialtest signifikant unter der der übrigen Spiele liegt.
+// Die Baseline einer Karte umfasst auch dasselbe Matchup auf anderen Karten. Da viele Zellen
+// getestet werden, gelten die p-Werte erst nach Holm-Bonferroni-Korrektur als signifikant.
+func (pa *PerformanceAnalyzer) Analyze(games []models.PerformanceGame) *models.PerformanceMatrix {
+	matrix := &models.PerformanceMatrix{
+		Overall:   summarize("", "", games),
+		Matchups:  []models.PerformanceCell{},
+		Cells:     []models.PerformanceCell{},
+		WeakSpots: []models.PerformanceWeakSpot{},
+	}
+
+	var tested []models.PerformanceWeakSpot
+	matchups := make(map[string]*cellGames)
+	cells := make(map[string]*cellGames)
+	for _, g := range games {
+		matchup := strategic.Matchup(g.Race, g.OpponentRace)
+		if matchups[matchup] == nil {
+			matchups[matchup] = &cellGames{matchup: matchup}
+		}
+		matchups[matchup].games = append(matchups[matchup].games, g)
+
+		key := matchup + "|" + g.Map
+		if cells[key] == nil {
+			cells[key] = &cellGames{matchup: matchup, gameMap: g.Map}
+		}
+		cells[key].games = append(cells[key].games, g)
+	}
+
+	for _, c := range sortedCells(matchups) {
+		cell := summarize(c.matchup, "", c.games)
+		matrix.Matchups = append(matrix.Matchups, cell)
+		if spot := testCell(cell, matrix.Overall); spot != nil {
+			tested = append(tested, *spot)
+		}
+	}
+	for _, c := range sortedCells(cells) {
+		cell := summarize(c.matchup, c.gameMap, c.games)
+		matrix.Cells = append(matrix.Cells, cell)
+		// Wurde das Matchup nur auf dieser Karte gespielt, steht die Schwachstelle schon beim Matchup
+		if len(c.games) == len(matchups[c.matchup].games) {
+			continue
+		}
+		if spot := testCell(cell, matrix.Overall); spot != nil {
+			tested = append(tested, *spot)
+		}
+	}
+
+	matrix.TestedCells = len(tested)
+	matrix.WeakSpots = holm(tested)
+	return matrix
+}
+
+// sortedCells sortiert Zellen nach Matchup und Karte
+func sortedCells(cells map[string]*cellGames) []*cellGames {
+	result := make([]*cellGames, 0, len(cells))
+	for _, c := range cells {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].matchup != result[j].matchup {
+			return result[i].matchup < result[j].matchup
+		}
+		return result[i].gameMap < result[j].gameMap
+	})
+	return result
+}
+
+// summarize berechnet Bilanz, Win Rate und Mittelwerte einer Zelle
+func summarize(matchup, gameMap string, games []models.PerformanceGame) models.PerformanceCell {
+	cell := models.PerformanceCell{Matchup: matchup, Map: gameMap, Games: len(games)}
+	var apm, sq, supplyBlock []float64
+	for _, g := range games {
+		switch g.Result {
+		case "Win":
+			cell.Wins++
+		case "Loss":
+			cell.Losses++
+		}
+		if g.APM > 0 {
+			apm = append(apm, g.APM)
+		}
+		if g.SQ > 0 {
+			sq = append(sq, g.SQ)
+		}
+		if g.SupplyBlock != nil {
+			supplyBlock = append(supplyBlock, *g.SupplyBlock)
+		}
+	}
+
+	if decided := cell.Wins + cell.Losses; decided > 0 {
+		low, high := wilson(cell.Wins, decided)
+		cell.WinRate = round(float64(cell.Wins) / float64(decided) * 100)
+		cell.WinRateLow = round(low * 100)
+		cell.WinRateHigh = round(high * 100)
+	}
+	cell.APM = estimate(apm)
+	cell.SQ = estimate(sq)
+	cell.SupplyBlock = estimate(supplyBlock)
+	return cell
+}
+
+// testCell testet die Win Rate einer Zelle gegen die der übrigen Spiele (nil = zu wenige Spiele)
+func testCell(cell, overall models.PerformanceCell) *models.PerformanceWeakSpot {
+	decided := cell.Wins + cell.Losses
+	otherWins := overall.Wins - cell.Wins
+	otherDecided := overall.Wins + overall.Losses - decided
+	if decided < WeakSpotMinGames || otherDecided == 0 {
+		return nil
+	}
+
+	baseline := float64(otherWins) / float64(otherDecided)
+	return &models.PerformanceWeakSpot{
+		Matchup:  cell.Matchup,
+		Map:      cell.Map,
+		Games:    decided,
+		Wins:     cell.Wins,
+		WinRate:  cell.WinRate,
+		Baseline: round(baseline * 100),
+		PValue:   binomialCDF(cell.Wins, decided, baseline),
+	}
+}
+
+// holm wendet die Holm-Bonferroni-Korrektur auf die getesteten Zellen an und gibt
+// die signifikanten Schwachstellen nach p-Wert sortiert zurück
+func holm(tested []models.PerformanceWeakSpot) []models.PerformanceWeakSpot {
+	sort.SliceStable(tested, func(i, j int) bool {
+		return tested[i].PValue < tested[j].PValue
+	})
+
+	spots := []models.PerformanceWeakSpot{}
+	adjusted := 0.0
+	for i, spot := range tested {
+		adjusted = math.Max(adjusted, math.Min(1, float64(len(tested)-i)*spot.PValue))
+		if adjusted >= weakSpotAlpha {
+			break
+		}
+		spot.PValue = math.Round(spot.PValue*1000) / 1000
+		spot.AdjustedPValue = math.Round(adjusted*1000) / 1000
+		spots = append(spots, spot)
+	}
+	return spots
+}
+
+// wilson gibt das 95 %-Wilson-Konfidenzintervall einer Erfolgsquote zurück
+func wilson(successes, n int) (float64, float64) {
+	p := float64(successes) / float64(n)
+	nf := float64(n)
+	denominator := 1 + z*z/nf
+	center := (p + z*z/(2*nf)) / denominator
+	half := z * math.Sqrt(p*(1-p)/nf+z*z/(4*nf*nf)) / denominator
+	return math.Max(0, center-half), math.Min(1, center+half)
+}
+
+// binomialCDF ist die Wahrscheinlichkeit für höchstens k Erfolge in n Versuchen mit Erfolgswahrscheinlichkeit p
+func binomialCDF(k, n int, p float64) float64 {
+	if p <= 0 {
+		return 1
+	}
+	if p >= 1 {
+		if k >= n {
+			return 1
+		}
+		return 0
+	}
+	sum := 0.0
+	for i := 0; i <= k; i++ {
+		sum += math.Exp(logChoose(n, i) + float64(i)*math.Log(p) + float64(n-i)*math.Log(1-p))
+	}
+	return math.Min(1, sum)
+}
+
+// logChoose ist der Logarithmus des Binomialkoeffizienten n über k
+func logChoose(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+// estimate berechnet Mittelwert und 95 %-Konfidenzintervall (Normalapproximation, nicht unter 0)
+func estimate(values []float64) models.MetricEstimate {
+	n := len(values)
+	if n == 0 {
+		return models.MetricEstimate{}
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(n)
+	result := models.MetricEstimate{Average: round(mean), Low: round(mean), High: round(mean), Games: n}
+	if n < 2 {
+		return result
+	}
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	half := z * math.Sqrt(variance/float64(n-1)/float64(n))
+	result.Low = round(math.Max(0, mean-half))
+	result.High = round(mean + half)
+	return result
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,87 @@
+package performance
+
+import (
+	"math"
+	"testing"
+
+	"sc2-analytics/internal/models"
+)
+
+func TestBinomialCDF(t *testing.T) {
+	tests := []struct {
+		name string
+		k, n int
+		p    float64
+		want float64
+	}{
+		{"all outcomes", 10, 10, 0.5, 1},
+		{"no success", 0, 10, 0.5, 0.000977},
+		{"fair coin half", 5, 10, 0.5, 0.623047},
+		{"three of twenty at 50%", 3, 20, 0.5, 0.001288},
+		{"p zero", 0, 10, 0, 1},
+		{"p one below n", 9, 10, 1, 0},
+		{"p one at n", 10, 10, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := binomialCDF(tt.k, tt.n, tt.p); math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("binomialCDF(%d, %d, %v) = %.6f, want %.6f", tt.k, tt.n, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWilson(t *testing.T) {
+	tests := []struct {
+		successes, n int
+		low, high    float64
+	}{
+		{5, 10, 0.2366, 0.7634},
+		{0, 10, 0, 0.2775},
+		{10, 10, 0.7225, 1},
+		{80, 100, 0.7112, 0.8666},
+	}
+	for _, tt := range tests {
+		low, high := wilson(tt.successes, tt.n)
+		if math.Abs(low-tt.low) > 1e-4 || math.Abs(high-tt.high) > 1e-4 {
+			t.Errorf("wilson(%d, %d) = [%.4f, %.4f], want [%.4f, %.4f]", tt.successes, tt.n, low, high, tt.low, tt.high)
+		}
+	}
+}
+
+func TestHolm(t *testing.T) {
+	spots := func(pValues ...float64) []models.PerformanceWeakSpot {
+		result := make([]models.PerformanceWeakSpot, len(pValues))
+		for i, p := range pValues {
+			result[i] = models.PerformanceWeakSpot{Matchup: string(rune('A' + i)), PValue: p}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		tested   []models.PerformanceWeakSpot
+		want     []string
+		adjusted []float64
+	}{
+		{"nothing tested", nil, []string{}, nil},
+		{"single significant cell", spots(0.01), []string{"A"}, []float64{0.01}},
+		{"significant alone but not across cells", spots(0.02, 0.5, 0.6), []string{}, nil},
+		{"step-down keeps sorted order", spots(0.06, 0.001, 0.012), []string{"B", "C"}, []float64{0.003, 0.024}},
+		{"stops at first non-significant", spots(0.001, 0.03, 0.026), []string{"A"}, []float64{0.003}},
+		{"adjusted values are monotone", spots(0.004, 0.005, 0.5), []string{"A", "B"}, []float64{0.012, 0.012}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := holm(tt.tested)
+			if len(got) != len(tt.want) {
+				t.Fatalf("holm() = %+v, want matchups %v", got, tt.want)
+			}
+			for i, spot := range got {
+				if spot.Matchup != tt.want[i] || math.Abs(spot.AdjustedPValue-tt.adjusted[i]) > 1e-9 {
+					t.Errorf("spot %d = %s (adjusted %.3f), want %s (adjusted %.3f)", i, spot.Matchup, spot.AdjustedPValue, tt.want[i], tt.adjusted[i])
+				}
+			}
+		})
+	}
+}
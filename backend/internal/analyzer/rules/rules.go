@@ -0,0 +1,781 @@
+package rules
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// RuleSetVersion ist die unterstützte Version des Regel-Formats
+const RuleSetVersion = 1
+
+//go:embed default_rules.json
+var defaultRulesJSON []byte
+
+// RuleEngine erzeugt Verbesserungsvorschläge aus konfigurierbaren Regeln
+type RuleEngine struct {
+	rules []compiledRule
+}
+
+// compiledRule ist eine Regel mit vorab geparsten Templates je Sprache
+type compiledRule struct {
+	models.SuggestionRule
+	texts map[string]*ruleTemplates
+}
+
+// ruleTemplates sind die geparsten Templates einer Regel in einer Sprache
+type ruleTemplates struct {
+	title   *template.Template
+	message *template.Template
+	target  *template.Template
+}
+
+// NewRuleEngine erstellt eine RuleEngine mit den Standard-Regeln
+func NewRuleEngine() *RuleEngine {
+	re := &RuleEngine{}
+	if err := re.SetRules(models.SuggestionRuleSet{}); err != nil {
+		panic(fmt.Sprintf("Standard-Regeln ungültig: %v", err))
+	}
+	return re
+}
+
+// DefaultRules gibt die mitgelieferten Standard-Regeln zurück
+func DefaultRules() models.SuggestionRuleSet {
+	var set models.SuggestionRuleSet
+	if err := json.Unmarshal(defaultRulesJSON, &set); err != nil {
+		panic(fmt.Sprintf("Standard-Regeln nicht lesbar: %v", err))
+	}
+	return set
+}
+
+// LoadRules liest Regeln aus einer JSON-Datei und prüft sie
+func LoadRules(path string) (models.SuggestionRuleSet, error) {
+	var set models.SuggestionRuleSet
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return set, fmt.Errorf("konnte Regeln nicht lesen: %w", err)
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return set, fmt.Errorf("ungültiges Regel-Format: %w", err)
+	}
+	if set.Version != RuleSetVersion {
+		return set, fmt.Errorf("nicht unterstützte Regel-Version %d (erwartet %d)", set.Version, RuleSetVersion)
+	}
+	for _, rule := range set.Rules {
+		if rule.Disabled {
+			continue
+		}
+		if _, err := compileRule(rule); err != nil {
+			return set, err
+		}
+	}
+	return set, nil
+}
+
+// SetRules ergänzt die Standard-Regeln um konfigurierte Regeln
+// Regeln mit gleicher ID ersetzen die Standard-Regel, neue IDs werden angehängt
+func (re *RuleEngine) SetRules(set models.SuggestionRuleSet) error {
+	merged := DefaultRules().Rules
+	index := make(map[string]int)
+	for i, rule := range merged {
+		index[rule.ID] = i
+	}
+	for _, rule := range set.Rules {
+		if i, ok := index[rule.ID]; ok {
+			merged[i] = rule
+			continue
+		}
+		index[rule.ID] = len(merged)
+		merged = append(merged, rule)
+	}
+
+	var compiled []compiledRule
+	for _, rule := range merged {
+		if rule.Disabled {
+			continue
+		}
+		cr, err := compileRule(rule)
+		if err != nil {
+			return err
+		}
+		compiled = append(compiled, *cr)
+	}
+	re.rules = compiled
+	return nil
+}
+
+// compileRule prüft eine Regel und parst ihre Templates
+func compileRule(rule models.SuggestionRule) (*compiledRule, error) {
+	if rule.ID == "" {
+		return nil, fmt.Errorf("Regel ohne ID")
+	}
+	switch rule.Priority {
+	case "high", "medium", "low":
+	default:
+		return nil, fmt.Errorf("Regel %s: ungültige Priorität %q", rule.ID, rule.Priority)
+	}
+	if err := checkConditions(rule.Conditions); err != nil {
+		return nil, fmt.Errorf("Regel %s: %w", rule.ID, err)
+	}
+
+	cr := &compiledRule{SuggestionRule: rule, texts: make(map[string]*ruleTemplates)}
+	for _, lang := range i18n.Languages() {
+		text := ruleText(rule, lang)
+		if lang == i18n.DefaultLanguage && text.Title == "" {
+			return nil, fmt.Errorf("Regel %s: kein Titel (weder in der Regel noch im Katalog)", rule.ID)
+		}
+
+		rt := &ruleTemplates{}
+		var err error
+		if rt.title, err = parseTemplate(rule.ID+".title", lang, text.Title); err != nil {
+			return nil, err
+		}
+		if rt.message, err = parseTemplate(rule.ID+".message", lang, text.Message); err != nil {
+			return nil, err
+		}
+		if rt.target, err = parseTemplate(rule.ID+".target_value", lang, text.TargetValue); err != nil {
+			return nil, err
+		}
+		cr.texts[lang] = rt
+	}
+	return cr, nil
+}
+
+// ruleText wählt die Templates einer Regel für eine Sprache: Übersetzung in der
+// Regel, sonst der Text der Regel, sonst der Katalog-Text suggestion.<id>.*
+func ruleText(rule models.SuggestionRule, lang string) models.RuleText {
+	text := models.RuleText{Title: rule.Title, Message: rule.Message, TargetValue: rule.TargetValue}
+	if tr, ok := rule.Translations[lang]; ok {
+		if tr.Title != "" {
+			text.Title = tr.Title
+		}
+		if tr.Message != "" {
+			text.Message = tr.Message
+		}
+		if tr.TargetValue != "" {
+			text.TargetValue = tr.TargetValue
+		}
+	}
+
+	prefix := "suggestion." + rule.ID + "."
+	if text.Title == "" {
+		text.Title, _ = i18n.Lookup(lang, prefix+"title")
+	}
+	if text.Message == "" {
+		text.Message, _ = i18n.Lookup(lang, prefix+"message")
+	}
+	if text.TargetValue == "" {
+		text.TargetValue, _ = i18n.Lookup(lang, prefix+"target_value")
+	}
+	return text
+}
+
+// checkConditions prüft Operatoren und Pfade der Bedingungen
+func checkConditions(conditions []models.RuleCondition) error {
+	for _, c := range conditions {
+		if len(c.Any) > 0 {
+			if err := checkConditions(c.Any); err != nil {
+				return err
+			}
+			continue
+		}
+		if c.Metric == "" {
+			return fmt.Errorf("Bedingung ohne Metrik")
+		}
+		switch c.Operator {
+		case "<", "<=", ">", ">=", "==", "!=":
+		default:
+			return fmt.Errorf("ungültiger Operator %q für %s", c.Operator, c.Metric)
+		}
+	}
+	return nil
+}
+
+// parseTemplate parst ein Nachrichten-Template für eine Sprache
+func parseTemplate(name, lang, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Funcs(languageFuncs(lang)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("ungültiges Template %s (%s): %w", name, lang, err)
+	}
+	return tmpl, nil
+}
+
+// languageFuncs sind die sprachabhängigen Template-Funktionen
+func languageFuncs(lang string) template.FuncMap {
+	return template.FuncMap{
+		// t übersetzt einen Katalog-Schlüssel, optional mit Ersatztext falls er fehlt
+		"t": func(key string, fallback ...interface{}) string {
+			if text, ok := i18n.Lookup(lang, key); ok {
+				return text
+			}
+			if len(fallback) > 0 && fallback[0] != nil {
+				return fmt.Sprint(fallback[0])
+			}
+			return key
+		},
+		// phase gibt den Anzeigenamen einer Spielphase zurück
+		"phase": func(v interface{}) string {
+			switch v {
+			case models.PhaseEarly:
+				return i18n.T(lang, "phase.early")
+			case models.PhaseMid:
+				return i18n.T(lang, "phase.mid")
+			default:
+				return i18n.T(lang, "phase.late")
+			}
+		},
+	}
+}
+
+// templateFuncs stehen in allen Nachrichten-Templates zur Verfügung
+var templateFuncs = template.FuncMap{
+	// f0/f1 formatieren Zahlen mit 0 bzw. 1 Nachkommastelle
+	"f0": func(v interface{}) string { return formatNumber(v, 0) },
+	"f1": func(v interface{}) string { return formatNumber(v, 1) },
+	// mmss formatiert Sekunden als m:ss
+	"mmss": func(v interface{}) string {
+		f, _ := toFloat(v)
+		return parser.FormatTime(f)
+	},
+	// count gibt die Länge einer Liste zurück (0 für fehlende Listen)
+	"count": func(v interface{}) int {
+		list, _ := v.([]interface{})
+		return len(list)
+	},
+	// first gibt das erste Element einer Liste zurück ("" für leere Listen)
+	"first": func(v interface{}) interface{} {
+		if list, ok := v.([]interface{}); ok && len(list) > 0 {
+			return list[0]
+		}
+		return ""
+	},
+}
+
+// formatNumber formatiert eine Zahl mit fester Anzahl Nachkommastellen
+func formatNumber(v interface{}, decimals int) string {
+	f, ok := toFloat(v)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	return strconv.FormatFloat(f, 'f', decimals, 64)
+}
+
+// scope ist der Kontext, in dem Metrik-Pfade einer Regel ausgewertet werden
+type scope struct {
+	root   map[string]interface{}
+	item   interface{}
+	prev   interface{}
+	parent interface{}
+}
+
+// GenerateSuggestions wertet alle Regeln gegen die Analyse aus und erzeugt
+// die Texte in der angegebenen Sprache
+func (re *RuleEngine) GenerateSuggestions(data *models.AnalysisData, lang string) []models.Suggestion {
+	var suggestions []models.Suggestion
+
+	if data == nil {
+		return suggestions
+	}
+
+	// Regeln arbeiten auf der JSON-Darstellung, damit Pfade den API-Feldern entsprechen
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return suggestions
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return suggestions
+	}
+
+	firedGroups := make(map[string]bool)
+	for i := range re.rules {
+		rule := &re.rules[i]
+		if rule.Group != "" && firedGroups[rule.Group] {
+			continue
+		}
+
+		var produced []models.Suggestion
+		if rule.ForEach == "" {
+			s := scope{root: root}
+			if evalConditions(rule.Conditions, s) {
+				produced = append(produced, rule.render(s, lang))
+			}
+		} else {
+			node, ok := resolve(root, splitPath(rule.ForEach))
+			if !ok {
+				continue
+			}
+			items, _ := node.value.([]interface{})
+			parents := node.parents
+			if rule.OrderBy != "" {
+				items, parents = orderItems(items, parents, rule.OrderBy)
+			}
+			for j, item := range items {
+				s := scope{root: root, item: item}
+				if j > 0 {
+					s.prev = items[j-1]
+				}
+				if j < len(parents) {
+					s.parent = parents[j]
+				}
+				if !evalConditions(rule.Conditions, s) {
+					continue
+				}
+				produced = append(produced, rule.render(s, lang))
+				if rule.Limit > 0 && len(produced) >= rule.Limit {
+					break
+				}
+			}
+		}
+
+		if len(produced) > 0 && rule.Group != "" {
+			firedGroups[rule.Group] = true
+		}
+		suggestions = append(suggestions, produced...)
+	}
+
+	return suggestions
+}
+
+// render erzeugt den Vorschlag einer Regel im angegebenen Kontext
+func (cr *compiledRule) render(s scope, lang string) models.Suggestion {
+	texts, ok := cr.texts[lang]
+	if !ok {
+		texts = cr.texts[i18n.DefaultLanguage]
+	}
+
+	// Template-Daten: alle Analysen plus item/prev/parent und der Wert der ersten Bedingung
+	data := make(map[string]interface{}, len(s.root)+4)
+	for k, v := range s.root {
+		data[k] = v
+	}
+	data["item"] = s.item
+	data["prev"] = s.prev
+	data["parent"] = s.parent
+	if len(cr.Conditions) > 0 && cr.Conditions[0].Metric != "" {
+		data["value"], _ = s.lookup(cr.Conditions[0].Metric)
+	}
+
+	suggestion := models.Suggestion{
+		Priority:    cr.Priority,
+		Category:    cr.Category,
+		Title:       execute(texts.title, data),
+		Description: execute(texts.message, data),
+		TargetValue: execute(texts.target, data),
+		RuleID:      cr.ID,
+	}
+	if cr.Timestamp != "" {
+		if v, ok := s.lookup(cr.Timestamp); ok {
+			suggestion.Timestamp, _ = toFloat(v)
+		}
+	}
+	return suggestion
+}
+
+// execute rendert ein Template, bei Fehlern bleibt der Text leer
+func execute(tmpl *template.Template, data map[string]interface{}) string {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// evalConditions prüft ob alle Bedingungen erfüllt sind
+func evalConditions(conditions []models.RuleCondition, s scope) bool {
+	for _, c := range conditions {
+		if !evalCondition(c, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// evalCondition prüft eine einzelne Bedingung, fehlende Metriken gelten als nicht erfüllt
+func evalCondition(c models.RuleCondition, s scope) bool {
+	if len(c.Any) > 0 {
+		for _, sub := range c.Any {
+			if evalCondition(sub, s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, ok := s.lookup(c.Metric)
+	if !ok {
+		return false
+	}
+
+	if c.RelativeTo != "" {
+		numerator, ok := toFloat(value)
+		if !ok {
+			return false
+		}
+		base, ok := s.lookup(c.RelativeTo)
+		if !ok {
+			return false
+		}
+		denominator, ok := toFloat(base)
+		if !ok || denominator == 0 {
+			return false
+		}
+		value = numerator / denominator
+	}
+
+	return compare(value, c.Operator, c.Value)
+}
+
+// compare vergleicht numerisch, sonst als Text (nur == und !=)
+func compare(actual interface{}, operator string, expected interface{}) bool {
+	a, aNum := toFloat(actual)
+	e, eNum := toFloat(expected)
+	if aNum && eNum {
+		switch operator {
+		case "<":
+			return a < e
+		case "<=":
+			return a <= e
+		case ">":
+			return a > e
+		case ">=":
+			return a >= e
+		case "==":
+			return a == e
+		case "!=":
+			return a != e
+		}
+		return false
+	}
+
+	switch operator {
+	case "==":
+		return fmt.Sprint(actual) == fmt.Sprint(expected)
+	case "!=":
+		return fmt.Sprint(actual) != fmt.Sprint(expected)
+	}
+	return false
+}
+
+// lookup löst einen Metrik-Pfad im Kontext auf
+func (s scope) lookup(path string) (interface{}, bool) {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return nil, false
+	}
+
+	var start interface{} = s.root
+	switch segments[0] {
+	case "item":
+		start, segments = s.item, segments[1:]
+	case "prev":
+		start, segments = s.prev, segments[1:]
+	case "parent":
+		start, segments = s.parent, segments[1:]
+	}
+	if start == nil {
+		return nil, false
+	}
+
+	node, ok := resolve(start, segments)
+	if !ok || node.value == nil {
+		return nil, false
+	}
+	return node.value, true
+}
+
+// node ist ein Zwischenergebnis der Pfadauflösung
+// Bei Listen enthält parents für jedes Element das Objekt, aus dem es stammt
+type node struct {
+	value   interface{}
+	parents []interface{}
+}
+
+// splitPath zerlegt einen Pfad an Punkten außerhalb von Filtern
+func splitPath(path string) []string {
+	var segments []string
+	depth, start := 0, 0
+	for i, r := range path {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				segments = append(segments, path[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(path) {
+		segments = append(segments, path[start:])
+	}
+	return segments
+}
+
+// resolve wertet Pfadsegmente aus
+// Unterstützt Feldzugriff (auch über Listen hinweg), Indizes, Filter wie
+// "blocks[cause=no_supply_started]" sowie "#" (Anzahl) und "@sum", "@avg", "@min", "@max"
+func resolve(start interface{}, segments []string) (node, bool) {
+	current := node{value: start}
+
+	for _, segment := range segments {
+		switch segment {
+		case "#":
+			list, ok := asList(current.value)
+			if !ok {
+				return node{}, false
+			}
+			current = node{value: float64(len(list))}
+			continue
+		case "@sum", "@avg", "@min", "@max":
+			list, ok := asList(current.value)
+			if !ok {
+				return node{}, false
+			}
+			v, ok := aggregate(segment, list)
+			if !ok {
+				return node{}, false
+			}
+			current = node{value: v}
+			continue
+		}
+
+		name, filters := parseSegment(segment)
+		if name != "" {
+			next, ok := access(current, name)
+			if !ok {
+				return node{}, false
+			}
+			current = next
+		}
+		for _, f := range filters {
+			list, ok := asList(current.value)
+			if !ok {
+				return node{}, false
+			}
+			current = applyFilter(list, current.parents, f)
+		}
+	}
+
+	return current, true
+}
+
+// access greift auf ein Feld zu; auf Listen wird es für jedes Element ausgewertet
+func access(current node, name string) (node, bool) {
+	switch v := current.value.(type) {
+	case map[string]interface{}:
+		child, ok := v[name]
+		if !ok {
+			return node{}, false
+		}
+		if list, ok := child.([]interface{}); ok {
+			parents := make([]interface{}, len(list))
+			for i := range parents {
+				parents[i] = v
+			}
+			return node{value: list, parents: parents}, true
+		}
+		return node{value: child}, true
+
+	case []interface{}:
+		// Index
+		if i, err := strconv.Atoi(name); err == nil {
+			if i < 0 || i >= len(v) {
+				return node{}, false
+			}
+			return node{value: v[i]}, true
+		}
+		// Feld aller Elemente, verschachtelte Listen werden flach zusammengeführt
+		result := []interface{}{}
+		var parents []interface{}
+		for _, elem := range v {
+			m, ok := elem.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			child, ok := m[name]
+			if !ok || child == nil {
+				continue
+			}
+			if list, ok := child.([]interface{}); ok {
+				for _, c := range list {
+					result = append(result, c)
+					parents = append(parents, m)
+				}
+				continue
+			}
+			result = append(result, child)
+			parents = append(parents, m)
+		}
+		return node{value: result, parents: parents}, true
+	}
+	return node{}, false
+}
+
+// filter ist ein Filter wie [feld=a|b] oder [feld!=a]
+type filter struct {
+	field  string
+	values []string
+	negate bool
+}
+
+// parseSegment trennt Feldname und Filter eines Pfadsegments
+func parseSegment(segment string) (string, []filter) {
+	open := strings.Index(segment, "[")
+	if open < 0 {
+		return segment, nil
+	}
+	name := segment[:open]
+
+	var filters []filter
+	for _, part := range strings.Split(segment[open:], "]") {
+		part = strings.TrimPrefix(part, "[")
+		if part == "" {
+			continue
+		}
+		f := filter{}
+		if i := strings.Index(part, "!="); i >= 0 {
+			f.field, f.values, f.negate = part[:i], strings.Split(part[i+2:], "|"), true
+		} else if i := strings.Index(part, "="); i >= 0 {
+			f.field, f.values = part[:i], strings.Split(part[i+1:], "|")
+		} else {
+			continue
+		}
+		filters = append(filters, f)
+	}
+	return name, filters
+}
+
+// applyFilter behält die Listenelemente, deren Feld zu einem der Werte passt
+func applyFilter(list []interface{}, parents []interface{}, f filter) node {
+	result := []interface{}{}
+	var resultParents []interface{}
+	for i, elem := range list {
+		m, ok := elem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matched := false
+		actual := fmt.Sprint(m[f.field])
+		for _, v := range f.values {
+			if actual == v {
+				matched = true
+				break
+			}
+		}
+		if matched == f.negate {
+			continue
+		}
+		result = append(result, elem)
+		if i < len(parents) {
+			resultParents = append(resultParents, parents[i])
+		}
+	}
+	return node{value: result, parents: resultParents}
+}
+
+// asList behandelt fehlende Listen (null) als leer
+func asList(v interface{}) ([]interface{}, bool) {
+	if v == nil {
+		return []interface{}{}, true
+	}
+	list, ok := v.([]interface{})
+	return list, ok
+}
+
+// aggregate fasst eine Liste von Zahlen zusammen
+func aggregate(op string, list []interface{}) (float64, bool) {
+	if op == "@sum" && len(list) == 0 {
+		return 0, true
+	}
+	var sum, min, max float64
+	n := 0
+	for _, elem := range list {
+		f, ok := toFloat(elem)
+		if !ok {
+			continue
+		}
+		if n == 0 || f < min {
+			min = f
+		}
+		if n == 0 || f > max {
+			max = f
+		}
+		sum += f
+		n++
+	}
+	if n == 0 {
+		return 0, op == "@sum"
+	}
+	switch op {
+	case "@sum":
+		return sum, true
+	case "@avg":
+		return sum / float64(n), true
+	case "@min":
+		return min, true
+	default:
+		return max, true
+	}
+}
+
+// orderItems sortiert Listenelemente stabil nach einem Feld ("-" für absteigend)
+func orderItems(items, parents []interface{}, orderBy string) ([]interface{}, []interface{}) {
+	field := strings.TrimPrefix(orderBy, "-")
+	descending := strings.HasPrefix(orderBy, "-")
+
+	indices := make([]int, len(items))
+	for i := range indices {
+		indices[i] = i
+	}
+	key := func(i int) float64 {
+		m, _ := items[i].(map[string]interface{})
+		f, _ := toFloat(m[field])
+		return f
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		if descending {
+			return key(indices[a]) > key(indices[b])
+		}
+		return key(indices[a]) < key(indices[b])
+	})
+
+	sorted := make([]interface{}, len(items))
+	var sortedParents []interface{}
+	if len(parents) == len(items) {
+		sortedParents = make([]interface{}, len(items))
+	}
+	for i, idx := range indices {
+		sorted[i] = items[idx]
+		if sortedParents != nil {
+			sortedParents[i] = parents[idx]
+		}
+	}
+	return sorted, sortedParents
+}
+
+// toFloat wandelt JSON-Zahlen in float64 um
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,216 @@
+package rules
+
+import (
+	"testing"
+
+	"sc2-analytics/internal/models"
+)
+
+// testData ist eine Analyse mit drei Supply Blocks unterschiedlicher Ursache
+func testData() *models.AnalysisData {
+	return &models.AnalysisData{
+		SupplyAnalysis: &models.SupplyAnalysis{
+			BlockPercentage: 12,
+			Blocks: []models.SupplyBlock{
+				{StartTime: 50, Duration: 8, Severity: "medium", Cause: "no_supply_started"},
+				{StartTime: 120, Duration: 20, Severity: "high", Cause: "no_supply_started"},
+				{StartTime: 300, Duration: 16, Severity: "high", Cause: "supply_provider_killed"},
+			},
+			Phases: []models.SupplyPhase{
+				{Phase: models.PhaseEarly, Start: 0, End: 240, BlockPercentage: 20},
+				{Phase: models.PhaseMid, Start: 240, End: 600, BlockPercentage: 3},
+			},
+		},
+	}
+}
+
+// rule baut eine Regel mit einer Bedingung und einem Titel-Template
+func rule(id, metric, operator string, value interface{}, title string) models.SuggestionRule {
+	return models.SuggestionRule{
+		ID:         id,
+		Conditions: []models.RuleCondition{{Metric: metric, Operator: operator, Value: value}},
+		Priority:   "medium",
+		Category:   "macro",
+		Title:      title,
+	}
+}
+
+// newTestEngine erstellt eine RuleEngine nur mit den angegebenen Regeln (ohne Standard-Regeln)
+func newTestEngine(t *testing.T, rules ...models.SuggestionRule) *RuleEngine {
+	t.Helper()
+	re := &RuleEngine{}
+	for _, r := range rules {
+		cr, err := compileRule(r)
+		if err != nil {
+			t.Fatalf("compileRule(%s): %v", r.ID, err)
+		}
+		re.rules = append(re.rules, *cr)
+	}
+	return re
+}
+
+func TestGenerateSuggestions(t *testing.T) {
+	grouped := func(r models.SuggestionRule, group string) models.SuggestionRule {
+		r.Group = group
+		return r
+	}
+	severe := rule("severe", "item.duration", ">", 10, "{{mmss .item.start_time}}")
+	severe.ForEach = "supply_analysis.blocks[severity=high]"
+	severe.Timestamp = "item.start_time"
+	longest := severe
+	longest.ID, longest.OrderBy, longest.Limit = "longest", "-duration", 1
+	relative := rule("relative", "supply_analysis.blocks.duration.@max", ">", 0.4, "{{f0 .value}}")
+	relative.Conditions[0].RelativeTo = "supply_analysis.blocks.duration.@sum"
+	relativeHigh := relative
+	relativeHigh.Conditions = []models.RuleCondition{relative.Conditions[0]}
+	relativeHigh.Conditions[0].Value = 0.5
+	anyOf := models.SuggestionRule{ID: "any", Priority: "low", Category: "macro", Title: "any", Conditions: []models.RuleCondition{
+		{Any: []models.RuleCondition{
+			{Metric: "supply_analysis.maxed_out_time", Operator: ">", Value: 60},
+			{Metric: "supply_analysis.blocks[cause=supply_provider_killed].#", Operator: ">", Value: 0},
+		}},
+	}}
+	translated := rule("translated", "supply_analysis.block_percentage", ">", 10, "Zu viele Blocks")
+	translated.Translations = map[string]models.RuleText{"en": {Title: "Too many blocks"}}
+	phase := rule("supply_block_phase", "item.block_percentage", ">", 15, "")
+	phase.ForEach = "supply_analysis.phases"
+
+	tests := []struct {
+		name  string
+		rules []models.SuggestionRule
+		lang  string
+		want  []models.Suggestion
+	}{
+		{
+			name:  "filter count",
+			rules: []models.SuggestionRule{rule("count", "supply_analysis.blocks[cause=no_supply_started].#", ">=", 2, "{{f0 .value}} Blocks")},
+			want:  []models.Suggestion{{RuleID: "count", Title: "2 Blocks"}},
+		},
+		{
+			name:  "negated filter",
+			rules: []models.SuggestionRule{rule("negated", "supply_analysis.blocks[cause!=no_supply_started].#", "==", 1, "x")},
+			want:  []models.Suggestion{{RuleID: "negated", Title: "x"}},
+		},
+		{
+			name:  "filter with alternatives",
+			rules: []models.SuggestionRule{rule("alternatives", "supply_analysis.blocks[cause=no_supply_started|supply_provider_killed].#", "==", 3, "x")},
+			want:  []models.Suggestion{{RuleID: "alternatives", Title: "x"}},
+		},
+		{
+			name:  "chained filters and aggregate",
+			rules: []models.SuggestionRule{rule("chained", "supply_analysis.blocks[severity=high][cause=no_supply_started].duration.@sum", "==", 20, "{{f1 .value}}")},
+			want:  []models.Suggestion{{RuleID: "chained", Title: "20.0"}},
+		},
+		{
+			name:  "missing metric does not fire",
+			rules: []models.SuggestionRule{rule("missing", "inject_analysis.efficiency", "<", 100, "x")},
+			want:  []models.Suggestion{},
+		},
+		{
+			name:  "text comparison",
+			rules: []models.SuggestionRule{rule("text", "supply_analysis.blocks.0.cause", "==", "no_supply_started", "x")},
+			want:  []models.Suggestion{{RuleID: "text", Title: "x"}},
+		},
+		{
+			name:  "relative to another metric",
+			rules: []models.SuggestionRule{relative},
+			want:  []models.Suggestion{{RuleID: "relative", Title: "20"}},
+		},
+		{
+			name:  "relative value below the threshold",
+			rules: []models.SuggestionRule{relativeHigh},
+			want:  []models.Suggestion{},
+		},
+		{
+			name:  "any condition",
+			rules: []models.SuggestionRule{anyOf},
+			want:  []models.Suggestion{{RuleID: "any", Title: "any"}},
+		},
+		{
+			name: "only the first rule of a group fires",
+			rules: []models.SuggestionRule{
+				grouped(rule("high", "supply_analysis.block_percentage", ">", 15, "high"), "total"),
+				grouped(rule("medium", "supply_analysis.block_percentage", ">", 10, "medium"), "total"),
+				grouped(rule("low", "supply_analysis.block_percentage", ">", 5, "low"), "total"),
+				grouped(rule("other", "supply_analysis.block_percentage", ">", 5, "other"), "other"),
+			},
+			want: []models.Suggestion{{RuleID: "medium", Title: "medium"}, {RuleID: "other", Title: "other"}},
+		},
+		{
+			name:  "for each item with timestamp",
+			rules: []models.SuggestionRule{severe},
+			want: []models.Suggestion{
+				{RuleID: "severe", Title: "2:00", Timestamp: 120},
+				{RuleID: "severe", Title: "5:00", Timestamp: 300},
+			},
+		},
+		{
+			name:  "order and limit",
+			rules: []models.SuggestionRule{longest},
+			want:  []models.Suggestion{{RuleID: "longest", Title: "2:00", Timestamp: 120}},
+		},
+		{
+			name:  "rule text in the default language",
+			rules: []models.SuggestionRule{translated},
+			lang:  "de",
+			want:  []models.Suggestion{{RuleID: "translated", Title: "Zu viele Blocks"}},
+		},
+		{
+			name:  "rule translation",
+			rules: []models.SuggestionRule{translated},
+			lang:  "en",
+			want:  []models.Suggestion{{RuleID: "translated", Title: "Too many blocks"}},
+		},
+		{
+			name:  "unknown language falls back to the default",
+			rules: []models.SuggestionRule{translated},
+			lang:  "fr",
+			want:  []models.Suggestion{{RuleID: "translated", Title: "Zu viele Blocks"}},
+		},
+		{
+			name:  "catalog text in German",
+			rules: []models.SuggestionRule{phase},
+			lang:  "de",
+			want:  []models.Suggestion{{RuleID: "supply_block_phase", Title: "Supply Blocks im Early Game"}},
+		},
+		{
+			name:  "catalog text in English",
+			rules: []models.SuggestionRule{phase},
+			lang:  "en",
+			want:  []models.Suggestion{{RuleID: "supply_block_phase", Title: "Supply blocks in the early game"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lang := tt.lang
+			if lang == "" {
+				lang = "de"
+			}
+			got := newTestEngine(t, tt.rules...).GenerateSuggestions(testData(), lang)
+			if len(got) != len(tt.want) {
+				t.Fatalf("GenerateSuggestions() = %+v, want %+v", got, tt.want)
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.RuleID != w.RuleID || g.Title != w.Title || g.Timestamp != w.Timestamp {
+					t.Errorf("suggestion %d = {%s %q %v}, want {%s %q %v}", i, g.RuleID, g.Title, g.Timestamp, w.RuleID, w.Title, w.Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultRulesCompile(t *testing.T) {
+	re := NewRuleEngine()
+	if len(re.rules) == 0 {
+		t.Fatal("no default rules")
+	}
+	for _, lang := range []string{"de", "en"} {
+		for _, s := range re.GenerateSuggestions(testData(), lang) {
+			if s.Title == "" || s.Description == "" {
+				t.Errorf("%s (%s): empty text %+v", s.RuleID, lang, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package scouting
+
+import (
+	"math"
+	"strings"
+)
+
+// unitSpeeds sind Laufgeschwindigkeiten in Map-Zellen pro Echtzeitsekunde (Faster, ohne Upgrades und Creep)
+var unitSpeeds = map[string]float64{
+	"scv": 3.94, "probe": 3.94, "drone": 3.94,
+	"overlord": 0.902, "overlordtransport": 0.902, "overseer": 2.62,
+	"observer": 2.63, "zergling": 4.13, "reaper": 5.25, "hellion": 5.95,
+	"adept": 3.5, "stalker": 4.13, "oracle": 5.6, "phoenix": 5.95,
+	"mutalisk": 5.6, "banshee": 3.85, "medivac": 3.5, "vikingfighter": 3.85,
+}
+
+// defaultUnitSpeed gilt für Einheiten ohne Eintrag in unitSpeeds
+const defaultUnitSpeed = 3.15
+
+// positionSample ist eine bekannte Position einer eigenen Einheit
+type positionSample struct {
+	Time float64
+	Pos  point
+}
+
+// unitMove ist der aktuelle Bewegungsbefehl einer Einheit
+type unitMove struct {
+	From, To       point
+	Start, Arrival float64
+	Scout          *pendingScout // vorläufiger Scout dieses Befehls oder nil
+}
+
+// pendingScout ist ein befehlsbasierter Scout, der erst bei Ankunft der Einheit zählt
+type pendingScout struct {
+	Candidate scoutCandidate
+	Tag       int
+	Target    point
+	Issued    float64
+	Arrival   float64 // geschätzte Ankunft
+	Cancelled bool    // durch einen neuen Befehl vor der Ankunft ersetzt
+}
+
+// movements schätzt, wo sich eigene Einheiten befinden und wann sie ein Befehlsziel erreichen.
+// Bekannte Positionen stammen aus UnitBorn und UnitPositions, dazwischen wird
+// geradlinig interpoliert (Luftlinie, die Ankunft wird also eher zu früh geschätzt)
+type movements struct {
+	samples map[int][]positionSample // Game-Tag -> bekannte Positionen (zeitlich sortiert)
+	deaths  map[int]float64          // Game-Tag -> Todeszeit
+	moves   map[int]*unitMove        // Game-Tag -> aktueller Bewegungsbefehl
+}
+
+// newMovements erstellt eine leere Bewegungsschätzung
+func newMovements() *movements {
+	return &movements{
+		samples: make(map[int][]positionSample),
+		deaths:  make(map[int]float64),
+		moves:   make(map[int]*unitMove),
+	}
+}
+
+// sample speichert eine bekannte Position
+func (m *movements) sample(tag int, t float64, p point) {
+	m.samples[tag] = append(m.samples[tag], positionSample{Time: t, Pos: p})
+}
+
+// died speichert die Todeszeit einer Einheit
+func (m *movements) died(tag int, t float64) {
+	m.deaths[tag] = t
+}
+
+// order registriert einen Befehl mit Ziel und liefert die neue Bewegung (nil ohne bekannte Position).
+// Ein nicht eingereihter Befehl ersetzt die laufende Bewegung und verwirft deren vorläufigen Scout
+func (m *movements) order(tag int, unitType string, target point, t float64, queued bool) *unitMove {
+	prev := m.moves[tag]
+	from, start := point{}, t
+	if queued && prev != nil && prev.Arrival > t {
+		from, start = prev.To, prev.Arrival
+	} else {
+		pos, ok := m.position(tag, unitType, t)
+		if !ok {
+			return nil
+		}
+		if prev != nil && prev.Arrival > t && prev.Scout != nil {
+			prev.Scout.Cancelled = true
+		}
+		from = pos
+	}
+	move := &unitMove{From: from, To: target, Start: start, Arrival: start + distance(from, target)/unitSpeed(unitType)}
+	m.moves[tag] = move
+	return move
+}
+
+// position schätzt die Position einer Einheit zum Zeitpunkt t aus dem letzten Sample und der laufenden Bewegung
+func (m *movements) position(tag int, unitType string, t float64) (point, bool) {
+	var base *positionSample
+	for i, s := range m.samples[tag] {
+		if s.Time > t {
+			break
+		}
+		base = &m.samples[tag][i]
+	}
+	move := m.moves[tag]
+	speed := unitSpeed(unitType)
+	switch {
+	case move != nil && (base == nil || move.Start >= base.Time):
+		return advance(move.From, move.To, speed*(t-move.Start)), true
+	case base == nil:
+		return point{}, false
+	case move != nil && move.Arrival > base.Time:
+		// Sample während der Bewegung: von dort weiter zum Ziel
+		return advance(base.Pos, move.To, speed*(t-base.Time)), true
+	}
+	return base.Pos, true
+}
+
+// confirm bestätigt einen vorläufigen Scout: Ankunftszeit laut erstem UnitPositions-Sample
+// am Ziel oder laut Schätzung; false wenn die Einheit vorher gestorben ist
+func (m *movements) confirm(p *pendingScout) (float64, bool) {
+	estimated := p.Arrival
+	for _, s := range m.samples[p.Tag] {
+		if s.Time > estimated {
+			break
+		}
+		if s.Time >= p.Issued && distance(s.Pos, p.Target) <= baseScoutRadius {
+			estimated = s.Time
+			break
+		}
+	}
+	if death, ok := m.deaths[p.Tag]; ok && death < estimated {
+		return 0, false
+	}
+	return estimated, true
+}
+
+// unitSpeed liefert die Laufgeschwindigkeit eines Einheitentyps
+func unitSpeed(unitType string) float64 {
+	if speed, ok := unitSpeeds[strings.ToLower(unitType)]; ok {
+		return speed
+	}
+	return defaultUnitSpeed
+}
+
+// advance bewegt eine Position um dist in Richtung Ziel, höchstens bis zum Ziel
+func advance(from, to point, dist float64) point {
+	total := distance(from, to)
+	if total == 0 || dist >= total {
+		return to
+	}
+	f := math.Max(dist, 0) / total
+	return point{X: from.X + (to.X-from.X)*f, Y: from.Y + (to.Y-from.Y)*f}
+}
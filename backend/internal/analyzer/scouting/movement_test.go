@@ -0,0 +1,67 @@
+package scouting
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMovementsConfirm(t *testing.T) {
+	target := point{X: 100, Y: 0}
+	// Worker bei (0,0), 100 Zellen bis zum Ziel
+	travel := 100 / unitSpeed("Probe")
+
+	tests := []struct {
+		name        string
+		setup       func(m *movements)
+		queued      bool
+		wantArrival float64
+		wantOK      bool
+		wantCancel  bool
+	}{
+		{"estimated travel time", nil, false, 10 + travel, true, false},
+		{"confirmed by a position sample", func(m *movements) { m.sample(1, 15, point{X: 90, Y: 0}) }, false, 15, true, false},
+		{"sample far from the target", func(m *movements) { m.sample(1, 15, point{X: 40, Y: 0}) }, false, 10 + travel, true, false},
+		{"died before arriving", func(m *movements) { m.died(1, 20) }, false, 0, false, false},
+		{"died after arriving", func(m *movements) { m.died(1, 60) }, false, 10 + travel, true, false},
+		{"replaced by a new order", func(m *movements) { m.order(1, "Probe", point{X: 0, Y: 50}, 12, false) }, false, 10 + travel, true, true},
+		{"queued order keeps the scout", func(m *movements) { m.order(1, "Probe", point{X: 0, Y: 50}, 12, true) }, false, 10 + travel, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMovements()
+			m.sample(1, 0, point{})
+			move := m.order(1, "Probe", target, 10, tt.queued)
+			p := &pendingScout{Tag: 1, Target: target, Issued: 10, Arrival: move.Arrival}
+			move.Scout = p
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			arrival, ok := m.confirm(p)
+			if ok != tt.wantOK || (ok && math.Abs(arrival-tt.wantArrival) > 1e-9) || p.Cancelled != tt.wantCancel {
+				t.Errorf("confirm() = (%.2f, %v), cancelled %v, want (%.2f, %v), cancelled %v", arrival, ok, p.Cancelled, tt.wantArrival, tt.wantOK, tt.wantCancel)
+			}
+		})
+	}
+}
+
+func TestMovementsPosition(t *testing.T) {
+	m := newMovements()
+	m.sample(1, 0, point{})
+	m.order(1, "Probe", point{X: 100, Y: 0}, 0, false)
+	speed := unitSpeed("Probe")
+
+	if got, _ := m.position(1, "Probe", 10); math.Abs(got.X-10*speed) > 1e-9 {
+		t.Errorf("position after 10s = %+v, want x=%.2f", got, 10*speed)
+	}
+	if got, _ := m.position(1, "Probe", 100); got != (point{X: 100, Y: 0}) {
+		t.Errorf("position after arrival = %+v, want target", got)
+	}
+	// Ein Sample unterwegs korrigiert die Schätzung
+	m.sample(1, 5, point{X: 0, Y: 0})
+	if got, _ := m.position(1, "Probe", 10); math.Abs(got.X-5*speed) > 1e-9 {
+		t.Errorf("position after sample = %+v, want x=%.2f", got, 5*speed)
+	}
+	if _, ok := m.position(2, "Probe", 10); ok {
+		t.Error("position of an unknown unit should be unknown")
+	}
+}
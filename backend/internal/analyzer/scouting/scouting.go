@@ -0,0 +1,479 @@
+package scouting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// ScoutingAnalyzer erkennt, wann und womit ein Spieler den Gegner gescoutet hat
+type ScoutingAnalyzer struct{}
+
+// NewScoutingAnalyzer erstellt einen neuen ScoutingAnalyzer
+func NewScoutingAnalyzer() *ScoutingAnalyzer {
+	return &ScoutingAnalyzer{}
+}
+
+const (
+	// baseScoutRadius ist der Abstand (Map-Zellen) zur gegnerischen Basis, ab dem ein Scout zählt
+	baseScoutRadius = 20.0
+	// infoRadius ist der Abstand, in dem gegnerische Gebäude als gesehen gelten
+	infoRadius = 25.0
+	// ownBaseRadius: Positionen näher an eigenen Basen zählen nicht als Scout (z.B. Rally-Punkte)
+	ownBaseRadius = 25.0
+	// scoutCooldown verhindert, dass eine Armee im Angriff viele Scouts erzeugt
+	scoutCooldown = 60.0
+)
+
+// scannerSweepAbilityLinks sind die bekannten abilLink-Werte von Scanner Sweep
+// Die IDs hängen vom Patch ab: 223 gilt von 2.1 bis 3.2, ab 4.0 sind die Abilities
+// wie Spawn Larva (macro/inject.go, 103 -> 183-185) um 80 verschoben
+var scannerSweepAbilityLinks = map[int]bool{
+	223: true,
+	303: true, 304: true, 305: true,
+}
+
+// scoutWindowDef definiert ein Scouting-Zeitfenster
+type scoutWindowDef struct {
+	Key        string // Katalog-Schlüssel scouting.window.<key>.name/description
+	Start, End float64
+	BaseOnly   bool // nur Scouts in gegnerischen Basen zählen
+}
+
+// earlyScoutWindows sind die frühen Scouting-Fenster je gegnerischer Rasse
+var earlyScoutWindows = map[string]scoutWindowDef{
+	"Zerg":    {"early_zerg", 0, 180, true},
+	"Terran":  {"early_terran", 0, 210, true},
+	"Protoss": {"early_protoss", 0, 180, true},
+}
+
+// laterScoutWindows gelten in jedem Matchup
+var laterScoutWindows = []scoutWindowDef{
+	{"tech", 300, 420, false},
+	{"late", 600, 780, false},
+}
+
+// point ist eine Position in Map-Zellen
+type point struct {
+	X, Y float64
+}
+
+// baseInfo ist eine Basis mit Fertigstellungszeit
+type baseInfo struct {
+	Pos  point
+	Time float64
+}
+
+// structureInfo ist ein gegnerisches Gebäude mit Lebensdauer
+type structureInfo struct {
+	Type       string
+	Pos        point
+	Start, End float64
+}
+
+// unitInfo ist eine eigene Einheit
+type unitInfo struct {
+	Type string
+	Tag  int
+}
+
+// scoutCandidate ist ein möglicher Scout; Unit ist der Game-Tag der Einheit oder -1
+type scoutCandidate struct {
+	Event models.ScoutEvent
+	Unit  int
+}
+
+// Analyze analysiert das Scouting eines Spielers
+func (sa *ScoutingAnalyzer) Analyze(events *parser.ParsedEvents, playerID int, gameDuration float64) *models.ScoutingAnalysis {
+	if events == nil {
+		return nil
+	}
+
+	analysis := &models.ScoutingAnalysis{
+		Events:  []models.ScoutEvent{},
+		Windows: []models.ScoutingWindow{},
+	}
+
+	var ownBases, enemyBases []baseInfo
+	var structures []*structureInfo
+	enemyStructures := make(map[int]*structureInfo) // unitTagIndex -> Gebäude
+	owners := make(map[int]int)                     // unitTagIndex -> Spieler
+	units := make(map[int]*unitInfo)                // unitTagIndex -> eigene Einheit
+	tagTypes := make(map[int]string)                // Game-Tag -> Einheitentyp
+	moves := newMovements()
+
+	// Kandidaten aus Tracker- und Game-Events werden erst nach zeitlicher
+	// Sortierung entdupliziert (siehe dedupeScouts)
+	var candidates []scoutCandidate
+	addCandidate := func(unit int, evt models.ScoutEvent) {
+		candidates = append(candidates, scoutCandidate{Event: evt, Unit: unit})
+	}
+
+	for _, evt := range events.TrackerEvents {
+		timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+		index := parser.GetInt(evt.Data, "unitTagIndex")
+
+		switch evt.EventType {
+		case "UnitBorn", "UnitInit":
+			owner := parser.GetInt(evt.Data, "controlPlayerId")
+			owners[index] = owner
+			unitType := parser.GetString(evt.Data, "unitTypeName")
+			tagTypes[parser.GetUnitTag(evt.Data)] = unitType
+			pos := point{X: float64(parser.GetInt(evt.Data, "x")), Y: float64(parser.GetInt(evt.Data, "y"))}
+
+			if parser.IsTownHall(unitType) {
+				if owner == playerID {
+					ownBases = append(ownBases, baseInfo{Pos: pos, Time: timeSeconds})
+				} else if owner > 0 {
+					enemyBases = append(enemyBases, baseInfo{Pos: pos, Time: timeSeconds})
+					if analysis.OpponentRace == "" {
+						analysis.OpponentRace = raceOfTownHall(unitType)
+					}
+				}
+			}
+
+			if owner != playerID {
+				// Gegnerische Gebäude: UnitInit (außer Warp-Ins) und Start-Gebäude
+				if owner > 0 && ((evt.EventType == "UnitInit" && !isWarpInUnit(unitType)) || (evt.Loop == 0 && parser.IsTownHall(unitType))) {
+					s := &structureInfo{Type: unitType, Pos: pos, Start: timeSeconds, End: math.MaxFloat64}
+					enemyStructures[index] = s
+					structures = append(structures, s)
+				}
+				continue
+			}
+
+			if evt.EventType == "UnitBorn" || isWarpInUnit(unitType) {
+				units[index] = &unitInfo{Type: unitType, Tag: parser.GetUnitTag(evt.Data)}
+				moves.sample(parser.GetUnitTag(evt.Data), timeSeconds, pos)
+				// Halluzinationen entstehen mit eigener Creator-Ability
+				if strings.Contains(parser.GetString(evt.Data, "creatorAbilityName"), "Hallucination") {
+					analysis.Hallucinations++
+					target := classifyTarget(pos, enemyBases, timeSeconds)
+					addCandidate(-1, models.ScoutEvent{Time: timeSeconds, Method: "hallucination", UnitType: unitType, Target: target, X: int(pos.X), Y: int(pos.Y)})
+				}
+			}
+
+		case "UnitTypeChange":
+			unitType := parser.GetString(evt.Data, "unitTypeName")
+			tagTypes[parser.GetUnitTag(evt.Data)] = unitType
+			if s, ok := enemyStructures[index]; ok {
+				s.Type = unitType
+			}
+			if u, ok := units[index]; ok {
+				u.Type = unitType
+			}
+
+		case "UnitDied":
+			if s, ok := enemyStructures[index]; ok {
+				s.End = timeSeconds
+				delete(enemyStructures, index)
+			}
+			if u, ok := units[index]; ok {
+				moves.died(u.Tag, timeSeconds)
+			}
+			delete(units, index)
+
+		case "UnitPositions":
+			// Ergänzung zu den Befehlen: UnitPositions enthält nur Einheiten,
+			// die Schaden genommen oder ausgeteilt haben
+			for _, p := range evt.UnitPositions() {
+				u, ok := units[p.UnitIndex]
+				if !ok || owners[p.UnitIndex] != playerID {
+					continue
+				}
+				moves.sample(u.Tag, timeSeconds, point{X: p.X, Y: p.Y})
+				if c, ok := scoutAt(u.Tag, u.Type, point{X: p.X, Y: p.Y}, timeSeconds, enemyBases, ownBases); ok {
+					candidates = append(candidates, c)
+				}
+			}
+		}
+	}
+
+	// Game-Events: Move-/Angriffsbefehle eigener Einheiten und Scans
+	// Befehlsbasierte Scouts sind vorläufig, bis die Einheit das Ziel erreicht
+	selections := make(parser.Selections)
+	var pending []*pendingScout
+	for _, evt := range events.GameEvents {
+		if evt.PlayerID != playerID {
+			continue
+		}
+
+		switch evt.EventType {
+		case "SelectionDelta", "ControlGroupUpdate":
+			selections.Apply(evt)
+
+		case "Cmd":
+			x, y, ok := evt.CmdTarget()
+			if !ok {
+				continue
+			}
+			target := point{X: x, Y: y}
+			timeSeconds := parser.LoopsToRealSeconds(evt.Loop)
+			active := selections.Active()
+
+			if !scannerSweepAbilityLinks[evt.AbilityLink()] || !isOrbitalSelection(active, tagTypes) {
+				for _, tag := range active {
+					unitType := tagTypes[tag]
+					if unitType == "" || parser.IsBuilding(unitType) {
+						continue
+					}
+					move := moves.order(tag, unitType, target, timeSeconds, evt.CmdQueued())
+					// Befehle auf Mineralien/Gas sind Abbau, kein Scout
+					if move == nil || evt.CmdTargetsNeutral() {
+						continue
+					}
+					if c, ok := scoutAt(tag, unitType, target, move.Arrival, enemyBases, ownBases); ok {
+						move.Scout = &pendingScout{Candidate: c, Tag: tag, Target: target, Issued: timeSeconds, Arrival: move.Arrival}
+						pending = append(pending, move.Scout)
+					}
+				}
+				continue
+			}
+
+			analysis.Scans++
+			addCandidate(-1, models.ScoutEvent{
+				Time:   timeSeconds,
+				Method: "scan",
+				Target: classifyTarget(target, enemyBases, timeSeconds),
+				X:      int(target.X),
+				Y:      int(target.Y),
+			})
+		}
+	}
+
+	// Vorläufige Scouts zählen erst bei Ankunft; ersetzte Befehle und
+	// Einheiten, die vor der Ankunft sterben, fallen weg
+	for _, p := range pending {
+		if p.Cancelled {
+			continue
+		}
+		if arrival, ok := moves.confirm(p); ok && arrival <= gameDuration {
+			c := p.Candidate
+			c.Event.Time = arrival
+			candidates = append(candidates, c)
+		}
+	}
+
+	analysis.Events = dedupeScouts(candidates, analysis)
+
+	// Gesehene Informationen
+	for i := range analysis.Events {
+		e := &analysis.Events[i]
+		e.InfoSeen = structuresSeen(point{X: float64(e.X), Y: float64(e.Y)}, e.Time, structures)
+	}
+
+	if len(analysis.Events) > 0 {
+		analysis.FirstScoutTime = analysis.Events[0].Time
+		analysis.FirstScoutMethod = analysis.Events[0].Method
+	}
+
+	// Scouting-Fenster
+	var windows []scoutWindowDef
+	if early, ok := earlyScoutWindows[analysis.OpponentRace]; ok {
+		windows = append(windows, early)
+	}
+	windows = append(windows, laterScoutWindows...)
+
+	for _, def := range windows {
+		if def.Start >= gameDuration {
+			continue
+		}
+		window := models.ScoutingWindow{
+			Key:         def.Key,
+			Name:        i18n.T(i18n.DefaultLanguage, "scouting.window."+def.Key+".name"),
+			Start:       def.Start,
+			End:         math.Min(def.End, gameDuration),
+			Description: i18n.T(i18n.DefaultLanguage, "scouting.window."+def.Key+".description"),
+		}
+		for _, e := range analysis.Events {
+			if e.Time < window.Start || e.Time > window.End {
+				continue
+			}
+			if def.BaseOnly && e.Target == "map" {
+				continue
+			}
+			window.Scouted = true
+			window.FirstScoutTime = e.Time
+			break
+		}
+		if !window.Scouted {
+			analysis.MissedWindows++
+		}
+		analysis.Windows = append(analysis.Windows, window)
+	}
+
+	return analysis
+}
+
+// classifyTarget ordnet eine Position der gegnerischen Main, Natural oder der Karte zu
+func classifyTarget(p point, enemyBases []baseInfo, timeSeconds float64) string {
+	if len(enemyBases) > 0 && distance(p, enemyBases[0].Pos) <= baseScoutRadius {
+		return "main"
+	}
+	natural := parser.NaturalIndex(len(enemyBases), func(i int) (float64, float64) {
+		return enemyBases[i].Pos.X, enemyBases[i].Pos.Y
+	})
+	if natural >= 0 && enemyBases[natural].Time <= timeSeconds && distance(p, enemyBases[natural].Pos) <= baseScoutRadius {
+		return "natural"
+	}
+	return "map"
+}
+
+// structuresSeen listet gegnerische Gebäude, die zum Zeitpunkt in Sichtweite standen
+func structuresSeen(p point, timeSeconds float64, structures []*structureInfo) []string {
+	counts := make(map[string]int)
+	for _, s := range structures {
+		if s.Start > timeSeconds || s.End <= timeSeconds {
+			continue
+		}
+		if distance(p, s.Pos) <= infoRadius {
+			counts[s.Type]++
+		}
+	}
+
+	seen := make([]string, 0, len(counts))
+	for unitType, count := range counts {
+		if count > 1 {
+			seen = append(seen, fmt.Sprintf("%s x%d", unitType, count))
+		} else {
+			seen = append(seen, unitType)
+		}
+	}
+	sort.Strings(seen)
+	return seen
+}
+
+// scoutMethod bestimmt die Scouting-Methode einer Einheit ("" = zählt nicht)
+func scoutMethod(unitType string) string {
+	switch strings.ToLower(unitType) {
+	case "scv", "probe", "drone":
+		return "worker"
+	case "overlord", "overlordtransport", "overseer", "overseersiegemode":
+		return "overlord"
+	case "observer", "observersiegemode":
+		return "observer"
+	case "larva", "egg", "mule", "broodling", "locust", "interceptor", "adeptphaseshift":
+		return ""
+	}
+	if parser.IsTownHall(unitType) {
+		return ""
+	}
+	return "unit"
+}
+
+// scoutAt prüft, ob eine eigene Einheit an (bzw. auf dem Weg zu) pos scoutet:
+// in gegnerischer Main/Natural oder als Overlord/Observer außerhalb der eigenen Basen
+func scoutAt(unit int, unitType string, pos point, t float64, enemyBases, ownBases []baseInfo) (scoutCandidate, bool) {
+	method := scoutMethod(unitType)
+	if method == "" {
+		return scoutCandidate{}, false
+	}
+	evt := models.ScoutEvent{Time: t, Method: method, UnitType: unitType, X: int(pos.X), Y: int(pos.Y)}
+	if target := classifyTarget(pos, enemyBases, t); target != "map" {
+		evt.Target = target
+		return scoutCandidate{Event: evt, Unit: unit}, true
+	}
+	if (method == "overlord" || method == "observer") && distanceToNearestBase(pos, ownBases) > ownBaseRadius {
+		evt.Target = "map"
+		return scoutCandidate{Event: evt, Unit: unit}, true
+	}
+	return scoutCandidate{}, false
+}
+
+// dedupeScouts sortiert die Kandidaten zeitlich und verwirft Wiederholungen:
+// je Einheit und Ziel sowie je Ziel und Methode gilt scoutCooldown,
+// Overlords/Observer zählen nur ihre erste Platzierung
+func dedupeScouts(candidates []scoutCandidate, analysis *models.ScoutingAnalysis) []models.ScoutEvent {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Event.Time < candidates[j].Event.Time
+	})
+
+	result := []models.ScoutEvent{}
+	placed := make(map[int]bool)
+	lastUnitScout := make(map[string]float64)   // Einheit/Ziel -> letzter Scout
+	lastTargetScout := make(map[string]float64) // Ziel/Methode -> letzter Scout
+	for _, c := range candidates {
+		evt := c.Event
+		if c.Unit >= 0 {
+			if evt.Target == "map" {
+				if placed[c.Unit] {
+					continue
+				}
+				placed[c.Unit] = true
+				if evt.Method == "overlord" {
+					analysis.OverlordPlacements++
+				} else {
+					analysis.ObserverPlacements++
+				}
+			} else {
+				key := fmt.Sprintf("%d:%s", c.Unit, evt.Target)
+				if last, ok := lastUnitScout[key]; ok && evt.Time-last < scoutCooldown {
+					continue
+				}
+				lastUnitScout[key] = evt.Time
+			}
+		}
+
+		key := evt.Target + ":" + evt.Method
+		if last, ok := lastTargetScout[key]; ok && evt.Time-last < scoutCooldown && evt.Target != "map" {
+			continue
+		}
+		lastTargetScout[key] = evt.Time
+		result = append(result, evt)
+	}
+	return result
+}
+
+// isOrbitalSelection prüft ob die Auswahl nur aus Orbital Commands besteht
+func isOrbitalSelection(tags []int, tagTypes map[int]string) bool {
+	if len(tags) == 0 {
+		return false
+	}
+	for _, tag := range tags {
+		if !strings.EqualFold(tagTypes[tag], "OrbitalCommand") {
+			return false
+		}
+	}
+	return true
+}
+
+// raceOfTownHall leitet die Rasse aus dem Hauptgebäude ab
+func raceOfTownHall(unitType string) string {
+	switch strings.ToLower(unitType) {
+	case "hatchery", "lair", "hive":
+		return "Zerg"
+	case "nexus":
+		return "Protoss"
+	}
+	return "Terran"
+}
+
+// isWarpInUnit prüft ob eine Einheit per Warp-In entsteht (UnitInit, aber kein Gebäude)
+func isWarpInUnit(unitType string) bool {
+	switch strings.ToLower(unitType) {
+	case "zealot", "stalker", "sentry", "adept", "hightemplar", "darktemplar":
+		return true
+	}
+	return false
+}
+
+// distanceToNearestBase berechnet den Abstand zur nächsten Basis
+func distanceToNearestBase(p point, bases []baseInfo) float64 {
+	best := math.MaxFloat64
+	for _, b := range bases {
+		if d := distance(p, b.Pos); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// distance berechnet den Abstand zweier Positionen
+func distance(a, b point) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
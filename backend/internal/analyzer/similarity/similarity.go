@@ -0,0 +1,166 @@
+package similarity
+
+import (
+	"math"
+	"sort"
+
+	"sc2-analytics/internal/models"
+)
+
+// Gewichte der Anteile am Ähnlichkeitswert (Summe 1)
+const (
+	weightBuild         = 0.45
+	weightOpponentBuild = 0.2
+	weightMatchup       = 0.15
+	weightMap           = 0.1
+	weightLength        = 0.1
+)
+
+// SimilarityAnalyzer sucht zu einem Spiel die ähnlichsten Spiele im Index
+type SimilarityAnalyzer struct{}
+
+// NewSimilarityAnalyzer erstellt einen neuen SimilarityAnalyzer
+func NewSimilarityAnalyzer() *SimilarityAnalyzer {
+	return &SimilarityAnalyzer{}
+}
+
+// Signature gibt die Build-Schritte eines Build Orders für den Index zurück
+// Arbeiter sowie abgebrochene und zerstörte Gebäude zählen nicht, sie überdecken sonst den eigentlichen Build
+func Signature(buildOrder []models.BuildOrderItem) []string {
+	steps := []string{}
+	for _, item := range buildOrder {
+		if item.Action == "Train Worker" || item.Status == "cancelled" || item.Status == "destroyed" {
+			continue
+		}
+		steps = append(steps, item.UnitOrBuilding)
+	}
+	return steps
+}
+
+// BuildSimilarity vergleicht zwei Build Orders wie der Build-Anteil von Score (0 bis 1, 1 = gleiche Folge)
+func BuildSimilarity(a, b []models.BuildOrderItem) float64 {
+	stepsA, stepsB := Signature(a), Signature(b)
+	return round(sequenceSimilarity(EditDistance(stepsA, stepsB), len(stepsA), len(stepsB)))
+}
+
+// Rank bewertet alle Kandidaten gegenüber target und gibt die limit ähnlichsten absteigend zurück
+// Kandidaten aus demselben Replay und Spieler werden übersprungen
+func (sa *SimilarityAnalyzer) Rank(target models.GameSignature, candidates []models.GameSignature, limit int) []models.SimilarGame {
+	games := []models.SimilarGame{}
+	for _, c := range candidates {
+		if c.ReplayID == target.ReplayID && c.PlayerID == target.PlayerID {
+			continue
+		}
+		games = append(games, sa.Score(target, c))
+	}
+
+	sort.SliceStable(games, func(i, j int) bool {
+		if games[i].Score != games[j].Score {
+			return games[i].Score > games[j].Score
+		}
+		return games[i].PlayedAt.After(games[j].PlayedAt)
+	})
+	if limit > 0 && len(games) > limit {
+		games = games[:limit]
+	}
+	return games
+}
+
+// Score berechnet die Ähnlichkeit eines Kandidaten zu target
+func (sa *SimilarityAnalyzer) Score(target, c models.GameSignature) models.SimilarGame {
+	distance := EditDistance(target.Build, c.Build)
+	scores := models.SimilarityScores{
+		Build:         sequenceSimilarity(distance, len(target.Build), len(c.Build)),
+		OpponentBuild: sequenceSimilarity(EditDistance(target.OpponentBuild, c.OpponentBuild), len(target.OpponentBuild), len(c.OpponentBuild)),
+		Matchup:       matchupSimilarity(target.Matchup, c.Matchup),
+		Length:        lengthSimilarity(target.Duration, c.Duration),
+	}
+	if target.Map != "" && target.Map == c.Map {
+		scores.Map = 1
+	}
+
+	score := weightBuild*scores.Build +
+		weightOpponentBuild*scores.OpponentBuild +
+		weightMatchup*scores.Matchup +
+		weightMap*scores.Map +
+		weightLength*scores.Length
+
+	return models.SimilarGame{
+		ReplayID:     c.ReplayID,
+		PlayerID:     c.PlayerID,
+		Name:         c.Name,
+		Race:         c.Race,
+		Result:       c.Result,
+		Opponent:     c.Opponent,
+		OpponentRace: c.OpponentRace,
+		Matchup:      c.Matchup,
+		Map:          c.Map,
+		Duration:     c.Duration,
+		PlayedAt:     c.PlayedAt,
+		IsReference:  c.Reference != "",
+		Reference:    c.Reference,
+		Score:        round(score),
+		EditDistance: distance,
+		Components: models.SimilarityScores{
+			Build:         round(scores.Build),
+			OpponentBuild: round(scores.OpponentBuild),
+			Matchup:       scores.Matchup,
+			Map:           scores.Map,
+			Length:        round(scores.Length),
+		},
+	}
+}
+
+// EditDistance ist die Levenshtein-Distanz zweier Schrittfolgen
+func EditDistance(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// sequenceSimilarity normiert eine Edit-Distanz auf 0 bis 1 (1 = gleiche Folge)
+func sequenceSimilarity(distance, lenA, lenB int) float64 {
+	longest := max(lenA, lenB)
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(distance)/float64(longest)
+}
+
+// matchupSimilarity: gleiches Matchup 1, gleiche eigene Rasse 0.5, sonst 0
+func matchupSimilarity(a, b string) float64 {
+	switch {
+	case a == b:
+		return 1
+	case len(a) > 0 && len(b) > 0 && a[0] == b[0]:
+		return 0.5
+	}
+	return 0
+}
+
+// lengthSimilarity vergleicht Spieldauern relativ zur längeren
+func lengthSimilarity(a, b int) float64 {
+	longest := max(a, b)
+	if longest == 0 {
+		return 1
+	}
+	return 1 - math.Abs(float64(a-b))/float64(longest)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,75 @@
+package similarity
+
+import (
+	"testing"
+
+	"sc2-analytics/internal/models"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want int
+	}{
+		{"both empty", nil, nil, 0},
+		{"empty against build", nil, []string{"Pylon", "Gateway"}, 2},
+		{"identical", []string{"Pylon", "Gateway", "Assimilator"}, []string{"Pylon", "Gateway", "Assimilator"}, 0},
+		{"one substitution", []string{"Pylon", "Gateway", "Assimilator"}, []string{"Pylon", "Forge", "Assimilator"}, 1},
+		{"one insertion", []string{"Pylon", "Assimilator"}, []string{"Pylon", "Gateway", "Assimilator"}, 1},
+		{"one deletion", []string{"Pylon", "Gateway", "Assimilator"}, []string{"Pylon", "Assimilator"}, 1},
+		{"swapped steps", []string{"Gateway", "Assimilator"}, []string{"Assimilator", "Gateway"}, 2},
+		{"nothing in common", []string{"Hatchery", "Pool"}, []string{"Barracks", "Refinery", "Factory"}, 3},
+		{"kitten sitting", []string{"k", "i", "t", "t", "e", "n"}, []string{"s", "i", "t", "t", "i", "n", "g"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EditDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("EditDistance(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := EditDistance(tt.b, tt.a); got != tt.want {
+				t.Errorf("EditDistance(%v, %v) = %d, want %d (symmetric)", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSimilarity(t *testing.T) {
+	item := func(action, unit, status string) models.BuildOrderItem {
+		return models.BuildOrderItem{Action: action, UnitOrBuilding: unit, Status: status}
+	}
+	base := []models.BuildOrderItem{
+		item("Build", "Pylon", ""),
+		item("Train Worker", "Probe", ""),
+		item("Build", "Gateway", ""),
+		item("Build", "Assimilator", ""),
+		item("Build", "CyberneticsCore", ""),
+	}
+	withoutWorkers := []models.BuildOrderItem{base[0], base[2], base[3], base[4]}
+	cancelled := append([]models.BuildOrderItem{item("Build", "Nexus", "cancelled")}, base...)
+	forge := []models.BuildOrderItem{base[0], item("Build", "Forge", ""), base[3], base[4]}
+
+	tests := []struct {
+		name string
+		a, b []models.BuildOrderItem
+		want float64
+	}{
+		{"identical", base, base, 1},
+		{"workers do not count", base, withoutWorkers, 1},
+		{"cancelled steps do not count", base, cancelled, 1},
+		{"one substituted step", base, forge, 0.75},
+		{"empty against build", nil, base, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildSimilarity(tt.a, tt.b); got != tt.want {
+				t.Errorf("BuildSimilarity() = %v, want %v", got, tt.want)
+			}
+			// gleiche Bewertung wie der Build-Anteil von Score
+			score := NewSimilarityAnalyzer().Score(models.GameSignature{Build: Signature(tt.a)}, models.GameSignature{Build: Signature(tt.b)})
+			if score.Components.Build != tt.want {
+				t.Errorf("Score().Components.Build = %v, want %v", score.Components.Build, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,507 @@
+package story
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+// Wichtigkeit eines Eintrags im Spielverlauf
+const (
+	ImportanceDetail  = 1 // z.B. einzelne Gebäude, Chat
+	ImportanceNotable = 2 // z.B. Expansionen, Tech, wichtige Upgrades
+	ImportanceKey     = 3 // z.B. große Kämpfe, schwerer Harass, Spielende
+)
+
+const (
+	// engagementGap: Verluste mit kürzerem Abstand (Sekunden) gehören zum selben Kampf
+	engagementGap = 10.0
+	// engagementMinUnits ist die Mindestzahl verlorener Armee-Einheiten für einen Kampf
+	engagementMinUnits = 6
+)
+
+// StoryAnalyzer fügt Build Order, Expansionen, Tech, Upgrades, Supply Blocks, Kämpfe,
+// Harass und Chat zu einem chronologischen Spielverlauf zusammen
+type StoryAnalyzer struct {
+	lang string
+}
+
+// NewStoryAnalyzer erstellt einen neuen StoryAnalyzer für die angegebene Sprache
+func NewStoryAnalyzer(lang string) *StoryAnalyzer {
+	if lang == "" {
+		lang = i18n.DefaultLanguage
+	}
+	return &StoryAnalyzer{lang: lang}
+}
+
+// t übersetzt einen Katalog-Schlüssel in der Sprache des Analyzers
+func (sa *StoryAnalyzer) t(key string, params ...i18n.Params) string {
+	return i18n.T(sa.lang, key, params...)
+}
+
+// storyBuilder sammelt die Einträge während der Analyse
+type storyBuilder struct {
+	sa      *StoryAnalyzer
+	names   map[int]string // Spieler-Slot -> Name
+	results map[int]string // Spieler-Slot -> Ergebnis, für Teams in Kämpfen
+	entries []models.StoryEntry
+}
+
+// add fügt einen Eintrag hinzu; der Titel-Schlüssel erhält den Spielernamen als {actor}
+func (b *storyBuilder) add(t float64, kind string, slot, importance int, titleKey string, params i18n.Params, detail string) {
+	if params == nil {
+		params = i18n.Params{}
+	}
+	actor := b.names[slot]
+	params["actor"] = actor
+	b.entries = append(b.entries, models.StoryEntry{
+		Time:       t,
+		Label:      parser.FormatTime(t),
+		Kind:       kind,
+		Actor:      actor,
+		ActorSlot:  slot,
+		Importance: importance,
+		Title:      b.sa.t(titleKey, params),
+		Detail:     detail,
+	})
+}
+
+// Analyze erstellt den Spielverlauf eines Replays
+// analyses enthält die Analysen nach Spieler-Slot (Build Order, Supply Blocks, Harass);
+// Expansionen, Tech, Upgrades, Kämpfe und Chat kommen direkt aus den Events
+func (sa *StoryAnalyzer) Analyze(replay *parser.ParsedReplay, analyses map[int]*models.AnalysisData) *models.GameStory {
+	story := &models.GameStory{
+		Map:      replay.Map,
+		Duration: float64(replay.Duration),
+		Players:  []models.StoryPlayer{},
+		Entries:  []models.StoryEntry{},
+	}
+
+	b := &storyBuilder{sa: sa, names: make(map[int]string), results: make(map[int]string)}
+	for _, p := range replay.Players {
+		b.names[p.Slot] = p.Name
+		b.results[p.Slot] = p.Result
+		story.Players = append(story.Players, models.StoryPlayer{
+			PlayerSlot: p.Slot,
+			Name:       p.Name,
+			Race:       p.Race,
+			Result:     p.Result,
+		})
+	}
+
+	for _, p := range replay.Players {
+		data := analyses[p.Slot]
+		if data == nil {
+			continue
+		}
+		b.addBuildOrder(p.Slot, data.BuildOrder)
+		b.addSupplyBlocks(p.Slot, data.SupplyAnalysis)
+		b.addHarassment(p.Slot, data.HarassmentAnalysis)
+	}
+
+	if replay.Events != nil {
+		b.addUnitEvents(replay.Events.TrackerEvents)
+		b.addChat(replay.Events.MessageEvents)
+	}
+	b.addEnd(replay)
+
+	sort.SliceStable(b.entries, func(i, j int) bool {
+		return b.entries[i].Time < b.entries[j].Time
+	})
+	story.Entries = append(story.Entries, b.entries...)
+	return story
+}
+
+// addBuildOrder übernimmt Gebäude, erste Einheiten sowie abgebrochene und zerstörte Gebäude
+// Expansionen, Tech und Upgrades kommen aus den Events des ganzen Spiels
+func (b *storyBuilder) addBuildOrder(slot int, items []models.BuildOrderItem) {
+	firstUnits := make(map[string]bool)
+	for _, item := range items {
+		name := item.UnitOrBuilding
+		key := strings.ToLower(strings.ReplaceAll(name, " ", ""))
+
+		var details []string
+		if item.IsProxy {
+			details = append(details, b.sa.t("story.detail.proxy"))
+		}
+		if item.Pattern != "" {
+			details = append(details, b.sa.t("story.pattern."+item.Pattern))
+		}
+		detail := strings.Join(details, ", ")
+
+		switch {
+		case item.Action == "Upgrade":
+			continue
+		case item.Status == "cancelled":
+			b.add(lostOrStart(item), "build", slot, ImportanceDetail, "story.build.cancelled", i18n.Params{"name": name}, detail)
+		case item.Status == "destroyed":
+			b.add(lostOrStart(item), "build", slot, ImportanceNotable, "story.build.destroyed", i18n.Params{"name": name}, detail)
+		case townHalls[key] || techBuildings[key]:
+			continue
+		case item.Action == "Build":
+			importance := ImportanceDetail
+			if item.IsProxy {
+				importance = ImportanceNotable
+			}
+			b.add(item.Time, "build", slot, importance, "story.build.title", i18n.Params{"name": name}, detail)
+		default:
+			// Einheiten: nur die erste ihres Typs, ohne Worker und Overlords
+			if item.Action == "Train Worker" || strings.Contains(key, "overlord") || firstUnits[key] {
+				continue
+			}
+			firstUnits[key] = true
+			b.add(item.Time, "unit", slot, ImportanceDetail, "story.unit.title", i18n.Params{"name": name}, "")
+		}
+	}
+}
+
+// lostOrStart gibt den Zeitpunkt des Abbruchs bzw. der Zerstörung zurück, sonst den Baubeginn
+func lostOrStart(item models.BuildOrderItem) float64 {
+	if item.LostTime > 0 {
+		return item.LostTime
+	}
+	return item.Time
+}
+
+// addSupplyBlocks übernimmt die Supply Blocks eines Spielers
+func (b *storyBuilder) addSupplyBlocks(slot int, analysis *models.SupplyAnalysis) {
+	if analysis == nil {
+		return
+	}
+	for _, block := range analysis.Blocks {
+		importance := ImportanceDetail
+		if block.Severity == "high" {
+			importance = ImportanceNotable
+		}
+		detail := ""
+		if block.Cause != "" {
+			detail = b.sa.t("story.supply_block.cause." + block.Cause)
+		}
+		b.add(block.StartTime, "supply_block", slot, importance, "story.supply_block.title", i18n.Params{
+			"duration": fmt.Sprintf("%.0f", block.Duration),
+			"supply":   block.SupplyUsed,
+			"max":      block.SupplyMax,
+		}, detail)
+	}
+}
+
+// addHarassment übernimmt die Harass-Vorfälle an den Basen eines Spielers
+func (b *storyBuilder) addHarassment(slot int, analysis *models.HarassmentAnalysis) {
+	if analysis == nil {
+		return
+	}
+	for _, inc := range analysis.Incidents {
+		importance := ImportanceDetail
+		if inc.WorkersLost >= 6 {
+			importance = ImportanceKey
+		} else if inc.WorkersLost >= 3 {
+			importance = ImportanceNotable
+		}
+
+		attackers := b.sa.t("story.harassment.unknown_attacker")
+		if len(inc.AttackerTypes) > 0 {
+			attackers = strings.Join(inc.AttackerTypes, "/")
+		}
+		params := i18n.Params{
+			"attackers": attackers,
+			"base":      b.sa.t("story.base." + inc.Base),
+			"reaction":  fmt.Sprintf("%.0f", inc.ReactionTime),
+		}
+		detail := b.sa.t("story.harassment.no_reaction", params)
+		if inc.Reacted {
+			detail = b.sa.t("story.harassment.reaction", params)
+		}
+		b.add(inc.Start, "harassment", slot, importance, "story.harassment.title", i18n.Params{"workers": inc.WorkersLost}, detail)
+	}
+}
+
+// storyUnit ist eine Einheit bzw. ein Gebäude während der Auswertung der Events
+type storyUnit struct {
+	owner    int
+	unitType string
+	done     bool
+	initTime float64
+}
+
+// engagement ist ein laufender Kampf während der Auswertung
+type engagement struct {
+	start, end float64
+	losses     map[int]int // Spieler-Slot -> verlorene Armee-Einheiten
+}
+
+// addUnitEvents erkennt Expansionen, Tech-Meilensteine, Upgrades und Kämpfe aus den Tracker-Events
+func (b *storyBuilder) addUnitEvents(events []parser.TrackerEvent) {
+	units := make(map[int]*storyUnit) // Unit-Tag -> Einheit
+	bases := make(map[int]int)        // Spieler-Slot -> fertige bzw. gestartete Basen
+	tech := make(map[int]map[string]bool)
+	var fight *engagement
+
+	reachTech := func(slot int, unitType string, t float64) {
+		key := strings.ToLower(unitType)
+		if !techBuildings[key] {
+			return
+		}
+		if tech[slot] == nil {
+			tech[slot] = make(map[string]bool)
+		}
+		if tech[slot][key] {
+			return
+		}
+		tech[slot][key] = true
+		b.add(t, "tech", slot, ImportanceNotable, "story.tech.title", i18n.Params{"name": formatName(unitType)}, "")
+	}
+
+	for _, evt := range events {
+		t := parser.LoopsToRealSeconds(evt.Loop)
+		tag := parser.GetUnitTag(evt.Data)
+
+		switch evt.EventType {
+		case "UnitBorn":
+			owner := parser.GetInt(evt.Data, "controlPlayerId")
+			if _, ok := b.names[owner]; !ok {
+				continue
+			}
+			unitType := parser.GetString(evt.Data, "unitTypeName")
+			units[tag] = &storyUnit{owner: owner, unitType: unitType, done: true, initTime: t}
+			if townHalls[strings.ToLower(unitType)] {
+				bases[owner]++ // Startbasis
+			}
+
+		case "UnitInit":
+			owner := parser.GetInt(evt.Data, "controlPlayerId")
+			if _, ok := b.names[owner]; !ok {
+				continue
+			}
+			unitType := parser.GetString(evt.Data, "unitTypeName")
+			units[tag] = &storyUnit{owner: owner, unitType: unitType, initTime: t}
+			reachTech(owner, unitType, t)
+
+		case "UnitDone":
+			u, ok := units[tag]
+			if !ok {
+				continue
+			}
+			u.done = true
+			// Expansionen zählen erst fertig, abgebrochene stehen als Abbruch in der Build Order
+			if townHalls[strings.ToLower(u.unitType)] {
+				bases[u.owner]++
+				b.add(u.initTime, "expansion", u.owner, ImportanceNotable, "story.expansion.title", i18n.Params{"count": bases[u.owner]}, "")
+			}
+
+		case "UnitTypeChange":
+			u, ok := units[tag]
+			if !ok {
+				continue
+			}
+			u.unitType = parser.GetString(evt.Data, "unitTypeName")
+			// Lair, Hive und Greater Spire entstehen per Typwechsel
+			reachTech(u.owner, u.unitType, t)
+
+		case "Upgrade":
+			if _, ok := b.names[evt.PlayerID]; !ok || evt.Loop == 0 {
+				continue
+			}
+			name := parser.GetString(evt.Data, "upgradeTypeName")
+			if name == "" || parser.IsCosmeticUpgrade(name) {
+				continue
+			}
+			importance := ImportanceDetail
+			if isKeyUpgrade(name) {
+				importance = ImportanceNotable
+			}
+			b.add(t, "upgrade", evt.PlayerID, importance, "story.upgrade.title", i18n.Params{"name": formatUpgradeName(name)}, "")
+
+		case "UnitDied":
+			u, ok := units[tag]
+			if !ok {
+				continue
+			}
+			delete(units, tag)
+
+			killer := parser.GetInt(evt.Data, "killerPlayerId")
+			if killer == 0 || killer == u.owner || !parser.IsArmyUnit(u.unitType) {
+				continue
+			}
+			if fight == nil || t-fight.end > engagementGap {
+				b.addEngagement(fight)
+				fight = &engagement{start: t, losses: make(map[int]int)}
+			}
+			fight.end = t
+			fight.losses[u.owner]++
+			if _, ok := fight.losses[killer]; !ok {
+				fight.losses[killer] = 0
+			}
+		}
+	}
+	b.addEngagement(fight)
+}
+
+// addEngagement fügt einen Kampf mit genug Verlusten hinzu
+// Verliert eine von zwei Seiten (Teams nach Ergebnis) höchstens halb so viel, gewinnt sie den Kampf;
+// ein einzelner Gewinner ist der Akteur
+func (b *storyBuilder) addEngagement(fight *engagement) {
+	if fight == nil {
+		return
+	}
+
+	var slots []int
+	total := 0
+	for slot, lost := range fight.losses {
+		slots = append(slots, slot)
+		total += lost
+	}
+	if total < engagementMinUnits {
+		return
+	}
+	sort.Ints(slots)
+
+	var parts []string
+	sideLosses := make(map[string]int)
+	sideSlots := make(map[string][]int)
+	var sides []string
+	for _, slot := range slots {
+		parts = append(parts, b.sa.t("story.engagement.loss", i18n.Params{"name": b.names[slot], "count": fight.losses[slot]}))
+		side := b.side(slot)
+		if _, ok := sideSlots[side]; !ok {
+			sides = append(sides, side)
+		}
+		sideLosses[side] += fight.losses[slot]
+		sideSlots[side] = append(sideSlots[side], slot)
+	}
+
+	importance := ImportanceDetail
+	if total >= 25 {
+		importance = ImportanceKey
+	} else if total >= 12 {
+		importance = ImportanceNotable
+	}
+
+	var winners []int
+	if len(sides) == 2 {
+		a, c := sides[0], sides[1]
+		if sideLosses[c] >= 2*sideLosses[a] {
+			winners = sideSlots[a]
+		} else if sideLosses[a] >= 2*sideLosses[c] {
+			winners = sideSlots[c]
+		}
+	}
+	detail := b.sa.t("story.engagement.even")
+	actor := 0
+	if len(winners) > 0 {
+		var names []string
+		for _, slot := range winners {
+			names = append(names, b.names[slot])
+		}
+		detail = b.sa.t("story.engagement.won", i18n.Params{"name": strings.Join(names, ", ")})
+		if len(winners) == 1 {
+			actor = winners[0]
+		}
+	}
+
+	b.add(fight.start, "engagement", actor, importance, "story.engagement.title", i18n.Params{
+		"losses": strings.Join(parts, ", "),
+		"total":  total,
+	}, detail)
+}
+
+// side gibt die Seite eines Spielers zurück: bei eindeutigem Ergebnis das Team, sonst der Spieler selbst
+func (b *storyBuilder) side(slot int) string {
+	if result := b.results[slot]; result == "Win" || result == "Loss" {
+		return result
+	}
+	return fmt.Sprintf("slot%d", slot)
+}
+
+// addChat übernimmt Chat-Nachrichten der Spieler (Beobachter werden ausgelassen)
+func (b *storyBuilder) addChat(messages []parser.MessageEvent) {
+	for _, msg := range messages {
+		if _, ok := b.names[msg.PlayerID]; !ok {
+			continue
+		}
+		b.add(parser.LoopsToRealSeconds(msg.Loop), "chat", msg.PlayerID, ImportanceDetail, "story.chat.title", i18n.Params{"message": msg.Message}, "")
+	}
+}
+
+// addEnd fügt das Verlassen der Spieler und das Spielende hinzu
+func (b *storyBuilder) addEnd(replay *parser.ParsedReplay) {
+	var winners []string
+	for _, p := range replay.Players {
+		if p.LeftAt > 0 && p.LeftAt < replay.Duration {
+			b.add(float64(p.LeftAt), "leave", p.Slot, ImportanceKey, "story.leave.title", nil, "")
+		}
+		if p.Result == "Win" {
+			winners = append(winners, p.Name)
+		}
+	}
+
+	end := float64(replay.Duration)
+	if len(winners) == 0 {
+		b.add(end, "game_end", 0, ImportanceKey, "story.game_end.undecided", nil, "")
+		return
+	}
+	b.add(end, "game_end", 0, ImportanceKey, "story.game_end.title", i18n.Params{"winners": strings.Join(winners, ", ")}, "")
+}
+
+// townHalls sind Hauptgebäude, deren Bau als Expansion zählt (Typnamen in Kleinbuchstaben)
+var townHalls = map[string]bool{
+	"commandcenter": true, "nexus": true, "hatchery": true,
+}
+
+// techBuildings sind Tech-Meilensteine (Typnamen in Kleinbuchstaben)
+var techBuildings = map[string]bool{
+	// Terran
+	"factory": true, "starport": true, "armory": true, "fusioncore": true, "ghostacademy": true,
+	// Protoss
+	"cyberneticscore": true, "twilightcouncil": true, "roboticsfacility": true, "roboticsbay": true,
+	"stargate": true, "fleetbeacon": true, "templararchive": true, "darkshrine": true,
+	// Zerg
+	"spawningpool": true, "roachwarren": true, "banelingnest": true, "lair": true,
+	"hydraliskden": true, "lurkerdenmp": true, "lurkerden": true, "spire": true,
+	"infestationpit": true, "hive": true, "ultraliskcavern": true, "greaterspire": true,
+	"nydusnetwork": true,
+}
+
+// keyUpgrades sind Upgrades, die das Spiel spürbar verändern (Teilstrings in Kleinbuchstaben)
+var keyUpgrades = []string{
+	"stimpack", "shieldwall", "punishergrenades", "warpgateresearch", "blinktech", "charge",
+	"zerglingmovementspeed", "glialreconstitution", "weaponslevel",
+}
+
+// isKeyUpgrade prüft ob ein Upgrade als wichtig gilt
+func isKeyUpgrade(name string) bool {
+	lowerName := strings.ToLower(name)
+	for _, k := range keyUpgrades {
+		if strings.Contains(lowerName, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatName formatiert einen Einheiten- oder Gebäudenamen lesbar
+func formatName(name string) string {
+	name = strings.TrimPrefix(name, "Terran")
+	name = strings.TrimPrefix(name, "Protoss")
+	name = strings.TrimPrefix(name, "Zerg")
+
+	var result strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			result.WriteRune(' ')
+		}
+		result.WriteRune(r)
+	}
+	return strings.TrimSpace(result.String())
+}
+
+// formatUpgradeName formatiert einen Upgrade-Namen lesbar
+func formatUpgradeName(name string) string {
+	for _, race := range []string{"Terran", "Protoss", "Zerg"} {
+		name = strings.ReplaceAll(name, race, "")
+	}
+	return formatName(name)
+}
@@ -0,0 +1,397 @@
+package strategic
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+)
+
+// KnowledgeBaseVersion ist die unterstützte Version des Wissensbasis-Formats
+const KnowledgeBaseVersion = 1
+
+//go:embed matchups.json
+var defaultKnowledgeJSON []byte
+
+// defaultKnowledge ist die eingebaute Wissensbasis, einmalig beim Start gelesen
+var defaultKnowledge = mustParseKnowledge(defaultKnowledgeJSON)
+
+// matchupPattern erlaubt "PvZ", "TvT" usw. sowie "PvR" für Random-Gegner
+var matchupPattern = regexp.MustCompile(`^[PTZ]v[PTZR]$`)
+
+func mustParseKnowledge(data []byte) *models.MatchupKnowledgeBase {
+	kb, err := parseKnowledge(data)
+	if err != nil {
+		panic(fmt.Sprintf("Standard-Wissensbasis nicht lesbar: %v", err))
+	}
+	return kb
+}
+
+// DefaultKnowledgeBase gibt die eingebaute Matchup-Wissensbasis zurück
+func DefaultKnowledgeBase() *models.MatchupKnowledgeBase {
+	return defaultKnowledge
+}
+
+// AnalyzerVersion ist die Version der strategischen Analyse
+// Erhöhen, wenn sich Erkennung oder Bewertung ändern, damit gespeicherte Analysen neu erstellt werden
+const AnalyzerVersion = 1
+
+// Version kennzeichnet, womit eine strategische Analyse erstellt wurde: Analyse-Version der
+// Spieldaten, Version des StrategicAnalyzers und Inhalt der Wissensbasis (nil = eingebaute).
+// Gespeicherte Analysen mit anderer Version sind veraltet.
+func Version(kb *models.MatchupKnowledgeBase) string {
+	if kb == nil {
+		kb = defaultKnowledge
+	}
+	data, _ := json.Marshal(kb)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%d.%d.%s", models.AnalysisVersion, AnalyzerVersion, hex.EncodeToString(sum[:6]))
+}
+
+// LoadKnowledgeBase liest eine Matchup-Wissensbasis aus einer JSON-Datei und prüft sie
+func LoadKnowledgeBase(path string) (*models.MatchupKnowledgeBase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("konnte Wissensbasis nicht lesen: %w", err)
+	}
+	return parseKnowledge(data)
+}
+
+// parseKnowledge liest und prüft eine Wissensbasis
+func parseKnowledge(data []byte) (*models.MatchupKnowledgeBase, error) {
+	var kb models.MatchupKnowledgeBase
+	if err := json.Unmarshal(data, &kb); err != nil {
+		return nil, fmt.Errorf("ungültiges Wissensbasis-Format: %w", err)
+	}
+	if kb.Version != KnowledgeBaseVersion {
+		return nil, fmt.Errorf("nicht unterstützte Wissensbasis-Version %d (erwartet %d)", kb.Version, KnowledgeBaseVersion)
+	}
+
+	for _, entry := range kb.Matchups {
+		if entry.Matchup != "default" && !matchupPattern.MatchString(entry.Matchup) {
+			return nil, fmt.Errorf("ungültiges Matchup %q", entry.Matchup)
+		}
+		if err := checkVersionRange(entry.MinVersion, entry.MaxVersion); err != nil {
+			return nil, fmt.Errorf("Matchup %s: %w", entry.Matchup, err)
+		}
+	}
+
+	for _, def := range kb.Strategies {
+		if def.ID == "" {
+			return nil, fmt.Errorf("Strategie ohne ID")
+		}
+		if raceCode(def.Race) == "X" {
+			return nil, fmt.Errorf("Strategie %s: ungültige Rasse %q", def.ID, def.Race)
+		}
+		if def.Against != "" && raceCode(def.Against) == "X" {
+			return nil, fmt.Errorf("Strategie %s: ungültige Rasse %q", def.ID, def.Against)
+		}
+		if len(def.Signals) == 0 {
+			return nil, fmt.Errorf("Strategie %s: keine Signale", def.ID)
+		}
+		for _, sig := range def.Signals {
+			if sig.Unit == "" {
+				return nil, fmt.Errorf("Strategie %s: Signal ohne Einheit", def.ID)
+			}
+		}
+		if def.Name[i18n.DefaultLanguage] == "" {
+			return nil, fmt.Errorf("Strategie %s: Name in %s fehlt", def.ID, i18n.DefaultLanguage)
+		}
+		if err := checkVersionRange(def.MinVersion, def.MaxVersion); err != nil {
+			return nil, fmt.Errorf("Strategie %s: %w", def.ID, err)
+		}
+	}
+	return &kb, nil
+}
+
+// MergeKnowledgeBase ergänzt die eingebaute Wissensbasis um konfigurierte Einträge
+// Matchups mit gleichem Versionsbereich und Strategien mit gleicher ID ersetzen den
+// eingebauten Eintrag, alle anderen werden angehängt
+func MergeKnowledgeBase(custom *models.MatchupKnowledgeBase) *models.MatchupKnowledgeBase {
+	merged := &models.MatchupKnowledgeBase{
+		Version:    KnowledgeBaseVersion,
+		Matchups:   append([]models.MatchupEntry(nil), defaultKnowledge.Matchups...),
+		Strategies: append([]models.StrategyDefinition(nil), defaultKnowledge.Strategies...),
+	}
+	if custom == nil {
+		return merged
+	}
+
+	matchupIndex := make(map[string]int)
+	for i, entry := range merged.Matchups {
+		matchupIndex[entry.Matchup+"|"+entry.MinVersion+"|"+entry.MaxVersion] = i
+	}
+	for _, entry := range custom.Matchups {
+		key := entry.Matchup + "|" + entry.MinVersion + "|" + entry.MaxVersion
+		if i, ok := matchupIndex[key]; ok {
+			merged.Matchups[i] = entry
+			continue
+		}
+		matchupIndex[key] = len(merged.Matchups)
+		merged.Matchups = append(merged.Matchups, entry)
+	}
+
+	strategyIndex := make(map[string]int)
+	for i, def := range merged.Strategies {
+		strategyIndex[def.ID] = i
+	}
+	for _, def := range custom.Strategies {
+		if i, ok := strategyIndex[def.ID]; ok {
+			merged.Strategies[i] = def
+			continue
+		}
+		strategyIndex[def.ID] = len(merged.Strategies)
+		merged.Strategies = append(merged.Strategies, def)
+	}
+	return merged
+}
+
+// findMatchup gibt die passenden Einträge für ein Matchup und die Spielversion zurück,
+// der genaueste zuerst: Einträge mit Versionsbereich vor allgemeinen, höhere Mindestversion zuerst
+func (sa *StrategicAnalyzer) findMatchup(matchup string) []*models.MatchupEntry {
+	var entries []*models.MatchupEntry
+	for i := range sa.knowledge.Matchups {
+		entry := &sa.knowledge.Matchups[i]
+		if entry.Matchup == matchup && sa.versionMatches(entry.MinVersion, entry.MaxVersion) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return moreSpecific(entries[i], entries[j])
+	})
+	return entries
+}
+
+// phaseTips nimmt die Tipps einer Phase aus dem genauesten Eintrag, der sie enthält
+// Patch-Einträge müssen so nur die Phasen enthalten, die sich geändert haben
+func (sa *StrategicAnalyzer) phaseTips(entries []*models.MatchupEntry, phase func(*models.MatchupEntry) map[string][]string) []string {
+	for _, entry := range entries {
+		if tips := sa.localizedList(phase(entry)); len(tips) > 0 {
+			return tips
+		}
+	}
+	return nil
+}
+
+// moreSpecific prüft ob ein Eintrag genauer auf die Spielversion zugeschnitten ist
+func moreSpecific(a, b *models.MatchupEntry) bool {
+	aRanged := a.MinVersion != "" || a.MaxVersion != ""
+	bRanged := b.MinVersion != "" || b.MaxVersion != ""
+	if aRanged != bRanged {
+		return aRanged
+	}
+	return compareVersions(a.MinVersion, b.MinVersion) > 0
+}
+
+// versionMatches prüft ob die Spielversion im Bereich liegt
+// Ohne bekannte Spielversion gelten nur Einträge ohne Versionsbereich
+func (sa *StrategicAnalyzer) versionMatches(min, max string) bool {
+	if min == "" && max == "" {
+		return true
+	}
+	if sa.gameVersion == "" {
+		return false
+	}
+	if min != "" && compareVersions(sa.gameVersion, min) < 0 {
+		return false
+	}
+	// Max ist inklusive: 5.0 umfasst auch 5.0.11.90136
+	if max != "" && compareVersions(truncateVersion(sa.gameVersion, max), max) > 0 {
+		return false
+	}
+	return true
+}
+
+// DetectBuild erkennt den eigenen Build eines Spielers an seinem Build Order (nil = kein bekannter Build)
+func (sa *StrategicAnalyzer) DetectBuild(race, opponentRace string, buildOrder []models.BuildOrderItem) *models.DetectedStrategy {
+	return sa.detectStrategy(race, opponentRace, buildOrder)
+}
+
+// strategyName gibt den übersetzten Namen einer Strategie zurück (die ID, wenn sie unbekannt ist)
+func (sa *StrategicAnalyzer) strategyName(id string) string {
+	for _, def := range sa.knowledge.Strategies {
+		if def.ID != id {
+			continue
+		}
+		if name := def.Name[sa.lang]; name != "" {
+			return name
+		}
+		if name := def.Name[i18n.DefaultLanguage]; name != "" {
+			return name
+		}
+	}
+	return id
+}
+
+// detectStrategy erkennt die Strategie des Gegners an seinem Build Order
+func (sa *StrategicAnalyzer) detectStrategy(opponentRace, ownRace string, buildOrder []models.BuildOrderItem) *models.DetectedStrategy {
+	for _, def := range sa.knowledge.Strategies {
+		if raceCode(def.Race) != raceCode(opponentRace) {
+			continue
+		}
+		if def.Against != "" && raceCode(def.Against) != raceCode(ownRace) {
+			continue
+		}
+		if !sa.versionMatches(def.MinVersion, def.MaxVersion) {
+			continue
+		}
+
+		detectedAt, ok := matchSignals(def.Signals, buildOrder)
+		if !ok {
+			continue
+		}
+		name := def.Name[sa.lang]
+		if name == "" {
+			name = def.Name[i18n.DefaultLanguage]
+		}
+		return &models.DetectedStrategy{
+			ID:   def.ID,
+			Name: name,
+			Time: detectedAt,
+			Tips: sa.localizedList(def.Tips),
+		}
+	}
+	return nil
+}
+
+// matchSignals prüft alle Signale und gibt den Zeitpunkt zurück, ab dem die Strategie erkennbar war
+func matchSignals(signals []models.StrategySignal, buildOrder []models.BuildOrderItem) (float64, bool) {
+	detectedAt := 0.0
+	for _, sig := range signals {
+		minCount := sig.MinCount
+		if minCount <= 0 {
+			minCount = 1
+		}
+
+		count := 0
+		reachedAt := 0.0
+		for _, item := range buildOrder {
+			if sig.Before > 0 && item.Time > sig.Before {
+				break
+			}
+			if item.Status == "cancelled" || (sig.Proxy && !item.IsProxy) {
+				continue
+			}
+			if normalizeUnit(item.UnitOrBuilding) != normalizeUnit(sig.Unit) {
+				continue
+			}
+			count++
+			if count == minCount {
+				reachedAt = item.Time
+			}
+		}
+
+		if sig.Absent {
+			if count >= minCount {
+				return 0, false
+			}
+			continue
+		}
+		if count < minCount {
+			return 0, false
+		}
+		if reachedAt > detectedAt {
+			detectedAt = reachedAt
+		}
+	}
+	return detectedAt, true
+}
+
+// normalizeUnit vergleicht Einheiten unabhängig von Schreibweise ("Spawning Pool" = "SpawningPool")
+func normalizeUnit(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", ""))
+}
+
+// localizedList gibt die Texte in der Sprache der Analyse zurück, sonst in der Standardsprache
+func (sa *StrategicAnalyzer) localizedList(texts map[string][]string) []string {
+	list, ok := texts[sa.lang]
+	if !ok {
+		list = texts[i18n.DefaultLanguage]
+	}
+	return append([]string(nil), list...)
+}
+
+// patchLabel beschreibt den Versionsbereich eines Eintrags, z.B. "5.0.11 – 5.0.12"
+func patchLabel(min, max string) string {
+	switch {
+	case min != "" && max != "":
+		return min + " – " + max
+	case min != "":
+		return "≥ " + min
+	case max != "":
+		return "≤ " + max
+	}
+	return ""
+}
+
+// checkVersionRange prüft die Versionsangaben eines Eintrags
+func checkVersionRange(min, max string) error {
+	for _, v := range []string{min, max} {
+		if v == "" {
+			continue
+		}
+		if _, err := parseVersion(v); err != nil {
+			return err
+		}
+	}
+	if min != "" && max != "" && compareVersions(min, max) > 0 {
+		return fmt.Errorf("Mindestversion %s liegt über Höchstversion %s", min, max)
+	}
+	return nil
+}
+
+// parseVersion zerlegt eine Spielversion wie "5.0.11.90136" in ihre Bestandteile
+func parseVersion(v string) ([]int, error) {
+	parts := strings.Split(v, ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("ungültige Version %q", v)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
+
+// compareVersions vergleicht zwei Versionen komponentenweise (-1, 0, 1)
+// Fehlende Komponenten zählen als 0, ungültige Versionen als kleinste Version
+func compareVersions(a, b string) int {
+	av, _ := parseVersion(a)
+	bv, _ := parseVersion(b)
+	for i := 0; i < len(av) || i < len(bv); i++ {
+		var x, y int
+		if i < len(av) {
+			x = av[i]
+		}
+		if i < len(bv) {
+			y = bv[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// truncateVersion kürzt eine Version auf die Anzahl Komponenten der Referenz
+func truncateVersion(v, ref string) string {
+	parts := strings.Split(v, ".")
+	n := len(strings.Split(ref, "."))
+	if len(parts) > n {
+		parts = parts[:n]
+	}
+	return strings.Join(parts, ".")
+}
@@ -2,55 +2,118 @@ package strategic
 
 import (
 	"fmt"
+	"math"
+	"sc2-analytics/internal/analyzer/winprob"
+	"sc2-analytics/internal/i18n"
 	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
 	"strings"
 )
 
 // StrategicAnalyzer erstellt strategische Spielanalysen
-type StrategicAnalyzer struct{}
+type StrategicAnalyzer struct {
+	lang        string
+	knowledge   *models.MatchupKnowledgeBase
+	gameVersion string
+	reference   *models.ReferenceComparison
+}
+
+// NewStrategicAnalyzer erstellt einen neuen StrategicAnalyzer, der Texte in der angegebenen Sprache erzeugt
+func NewStrategicAnalyzer(lang string) *StrategicAnalyzer {
+	if lang == "" {
+		lang = i18n.DefaultLanguage
+	}
+	return &StrategicAnalyzer{lang: lang, knowledge: defaultKnowledge}
+}
+
+// SetKnowledgeBase setzt die Matchup-Wissensbasis (nil = eingebaute Wissensbasis)
+func (sa *StrategicAnalyzer) SetKnowledgeBase(kb *models.MatchupKnowledgeBase) {
+	if kb == nil {
+		kb = defaultKnowledge
+	}
+	sa.knowledge = kb
+}
+
+// SetGameVersion setzt die Spielversion des Replays für patch-abhängige Tipps
+func (sa *StrategicAnalyzer) SetGameVersion(version string) {
+	sa.gameVersion = version
+}
+
+// SetReference setzt den Vergleich mit dem passendsten Referenz-Replay für die Matchup-Tipps (nil = keiner)
+func (sa *StrategicAnalyzer) SetReference(ref *models.ReferenceComparison) {
+	sa.reference = ref
+}
 
-// NewStrategicAnalyzer erstellt einen neuen StrategicAnalyzer
-func NewStrategicAnalyzer() *StrategicAnalyzer {
-	return &StrategicAnalyzer{}
+// t übersetzt einen Katalog-Schlüssel in die Sprache der Analyse
+func (sa *StrategicAnalyzer) t(key string, params ...i18n.Params) string {
+	return i18n.T(sa.lang, key, params...)
 }
 
-// Analyze erstellt eine vollständige strategische Analyse
+// Analyze erstellt eine vollständige strategische Analyse aus Sicht von player
+// Enthält das Replay kein Ergebnis, wird es aus Leave-Events und Spielstand geschätzt
 func (sa *StrategicAnalyzer) Analyze(
-	loserName, winnerName string,
-	loserRace, winnerRace string,
-	loserAnalysis, winnerAnalysis *models.AnalysisData,
+	player, opponent *models.GamePlayer,
+	playerAnalysis, opponentAnalysis *models.AnalysisData,
 ) *models.StrategicAnalysis {
-	if loserAnalysis == nil || winnerAnalysis == nil {
+	if player == nil || opponent == nil || playerAnalysis == nil || opponentAnalysis == nil {
 		return nil
 	}
 
-	matchup := strings.ToUpper(string(loserRace[0])) + "v" + strings.ToUpper(string(winnerRace[0]))
-
 	analysis := &models.StrategicAnalysis{
-		Winner:     winnerName,
-		Loser:      loserName,
-		WinnerRace: winnerRace,
-		LoserRace:  loserRace,
-		Matchup:    matchup,
+		Player:       player.Name,
+		Opponent:     opponent.Name,
+		PlayerRace:   player.Race,
+		OpponentRace: opponent.Race,
+		Result:       player.Result,
+		Matchup:      Matchup(player.Race, opponent.Race),
+	}
+
+	// Spiele ohne Ergebnis (Leaver, Replays ohne Ergebnis)
+	if analysis.Result != "Win" && analysis.Result != "Loss" {
+		analysis.ResultInference = sa.InferResult(player, opponent, playerAnalysis)
+		analysis.Result = analysis.ResultInference.Result
+	}
+	switch analysis.Result {
+	case "Win":
+		analysis.Winner, analysis.WinnerRace = player.Name, player.Race
+		analysis.Loser, analysis.LoserRace = opponent.Name, opponent.Race
+	case "Loss":
+		analysis.Winner, analysis.WinnerRace = opponent.Name, opponent.Race
+		analysis.Loser, analysis.LoserRace = player.Name, player.Race
 	}
 
 	// Metriken vergleichen
-	analysis.MetricsComparison = sa.compareMetrics(loserAnalysis, winnerAnalysis)
+	analysis.MetricsComparison = sa.compareMetrics(playerAnalysis, opponentAnalysis)
 
 	// Supply Blocks extrahieren
-	analysis.SupplyBlocks = sa.extractSupplyBlocks(loserAnalysis)
+	analysis.SupplyBlocks = sa.extractSupplyBlocks(playerAnalysis)
 
 	// Kritische Momente finden
-	analysis.CriticalMoments = sa.findCriticalMoments(loserAnalysis, winnerAnalysis)
+	analysis.CriticalMoments = sa.findCriticalMoments(playerAnalysis, opponentAnalysis)
+
+	// Vom Gegner zerstörte Proxy-Gebäude
+	analysis.ProxyLosses = sa.findProxyLosses(player.Name, opponent.Name, playerAnalysis, opponentAnalysis, analysis.Result)
 
-	// Probleme identifizieren
-	analysis.Problems = sa.identifyProblems(loserAnalysis, winnerAnalysis)
+	// Probleme identifizieren: bei einem Sieg sind es Fehler, die der Gegner nicht bestraft hat
+	problems := sa.identifyProblems(playerAnalysis, opponentAnalysis)
+	if analysis.Result == "Win" {
+		analysis.UnpunishedMistakes = problems
+	} else {
+		analysis.Problems = problems
+	}
+
+	// Stärken und knappe Momente
+	analysis.Strengths = sa.findStrengths(playerAnalysis, opponentAnalysis, analysis.CriticalMoments)
+	analysis.CloseCalls = sa.findCloseCalls(playerAnalysis.WinProbability, analysis.CriticalMoments, analysis.Result)
 
 	// Matchup-Tipps
-	analysis.MatchupTips = sa.getMatchupTips(loserRace, winnerRace)
+	analysis.MatchupTips = sa.getMatchupTips(player, opponent, opponentAnalysis)
 
 	// Verbesserungsschritte
-	analysis.ImprovementSteps = sa.generateImprovementSteps(analysis.Problems)
+	analysis.ImprovementSteps = sa.generateImprovementSteps(problems)
+
+	// Verlauf der Siegwahrscheinlichkeit: wann das Spiel gewonnen bzw. verloren wurde
+	analysis.WinProbability = playerAnalysis.WinProbability
 
 	// Zusammenfassung
 	analysis.Summary = sa.generateSummary(analysis)
@@ -58,65 +121,106 @@ func (sa *StrategicAnalyzer) Analyze(
 	return analysis
 }
 
+// InferResult schätzt das Ergebnis aus Sicht von player, wenn das Replay keines enthält
+// Wer das Spiel zuerst verlassen hat, verliert; sonst entscheidet die Siegwahrscheinlichkeit am Spielende
+func (sa *StrategicAnalyzer) InferResult(player, opponent *models.GamePlayer, playerAnalysis *models.AnalysisData) *models.ResultInference {
+	inference := &models.ResultInference{Result: "Undecided", Reason: "unknown"}
+
+	switch {
+	case leftFirst(player, opponent):
+		inference.Result, inference.Reason, inference.Confidence = "Loss", "player_left", 0.8
+		inference.Description = sa.t("strategic.inference.player_left", i18n.Params{"time": parser.FormatTime(float64(player.LeftAt))})
+		return inference
+	case leftFirst(opponent, player):
+		inference.Result, inference.Reason, inference.Confidence = "Win", "opponent_left", 0.8
+		inference.Description = sa.t("strategic.inference.opponent_left", i18n.Params{"time": parser.FormatTime(float64(opponent.LeftAt))})
+		return inference
+	}
+
+	// Spielstand am Ende: Armee, Wirtschaft, Basen und Verluste
+	wp := playerAnalysis.WinProbability
+	if wp != nil && len(wp.Timeline) > 0 && wp.FinalProbability != 0.5 {
+		inference.Reason = "resources"
+		inference.Result = "Loss"
+		if wp.FinalProbability > 0.5 {
+			inference.Result = "Win"
+		}
+		inference.Confidence = math.Round(math.Abs(wp.FinalProbability-0.5)*200) / 100
+		inference.Description = sa.t("strategic.inference.resources", i18n.Params{
+			"percent": fmt.Sprintf("%.0f", wp.FinalProbability*100),
+			"result":  sa.t("strategic.result." + strings.ToLower(inference.Result)),
+		})
+		return inference
+	}
+
+	inference.Description = sa.t("strategic.inference.unknown")
+	return inference
+}
+
+// leftFirst prüft ob a das Spiel vor b verlassen hat
+func leftFirst(a, b *models.GamePlayer) bool {
+	return a.LeftAt > 0 && (b.LeftAt == 0 || a.LeftAt < b.LeftAt)
+}
+
 // compareMetrics vergleicht Metriken zwischen Spielern
-func (sa *StrategicAnalyzer) compareMetrics(loser, winner *models.AnalysisData) []models.MetricComparison {
+func (sa *StrategicAnalyzer) compareMetrics(player, opponent *models.AnalysisData) []models.MetricComparison {
 	var comparisons []models.MetricComparison
 
 	// APM
-	if loser.APMAnalysis != nil && winner.APMAnalysis != nil {
+	if player.APMAnalysis != nil && opponent.APMAnalysis != nil {
 		comparisons = append(comparisons, models.MetricComparison{
-			Metric:      "APM (Durchschnitt)",
-			PlayerValue: loser.APMAnalysis.AverageAPM,
-			EnemyValue:  winner.APMAnalysis.AverageAPM,
-			IsWorse:     loser.APMAnalysis.AverageAPM < winner.APMAnalysis.AverageAPM*0.8,
+			Metric:      sa.t("strategic.metric.apm"),
+			PlayerValue: player.APMAnalysis.AverageAPM,
+			EnemyValue:  opponent.APMAnalysis.AverageAPM,
+			IsWorse:     player.APMAnalysis.AverageAPM < opponent.APMAnalysis.AverageAPM*0.8,
 		})
 		comparisons = append(comparisons, models.MetricComparison{
-			Metric:      "EAPM (Effektiv)",
-			PlayerValue: loser.APMAnalysis.EAPM,
-			EnemyValue:  winner.APMAnalysis.EAPM,
-			IsWorse:     loser.APMAnalysis.EAPM < winner.APMAnalysis.EAPM*0.8,
+			Metric:      sa.t("strategic.metric.eapm"),
+			PlayerValue: player.APMAnalysis.EAPM,
+			EnemyValue:  opponent.APMAnalysis.EAPM,
+			IsWorse:     player.APMAnalysis.EAPM < opponent.APMAnalysis.EAPM*0.8,
 		})
 	}
 
 	// Spending Quotient
-	if loser.SpendingAnalysis != nil && winner.SpendingAnalysis != nil {
+	if player.SpendingAnalysis != nil && opponent.SpendingAnalysis != nil {
 		comparisons = append(comparisons, models.MetricComparison{
-			Metric:      "Spending Quotient",
-			PlayerValue: loser.SpendingAnalysis.SpendingQuotient,
-			EnemyValue:  winner.SpendingAnalysis.SpendingQuotient,
-			IsWorse:     loser.SpendingAnalysis.SpendingQuotient < winner.SpendingAnalysis.SpendingQuotient-20,
+			Metric:      sa.t("strategic.metric.spending_quotient"),
+			PlayerValue: player.SpendingAnalysis.SpendingQuotient,
+			EnemyValue:  opponent.SpendingAnalysis.SpendingQuotient,
+			IsWorse:     player.SpendingAnalysis.SpendingQuotient < opponent.SpendingAnalysis.SpendingQuotient-20,
 		})
 		comparisons = append(comparisons, models.MetricComparison{
-			Metric:      "Ø Ungenutzte Mineralien",
-			PlayerValue: loser.SpendingAnalysis.AverageUnspent.Minerals,
-			EnemyValue:  winner.SpendingAnalysis.AverageUnspent.Minerals,
-			IsWorse:     loser.SpendingAnalysis.AverageUnspent.Minerals > winner.SpendingAnalysis.AverageUnspent.Minerals*1.5,
+			Metric:      sa.t("strategic.metric.unspent_minerals"),
+			PlayerValue: player.SpendingAnalysis.AverageUnspent.Minerals,
+			EnemyValue:  opponent.SpendingAnalysis.AverageUnspent.Minerals,
+			IsWorse:     player.SpendingAnalysis.AverageUnspent.Minerals > opponent.SpendingAnalysis.AverageUnspent.Minerals*1.5,
 		})
 	}
 
 	// Supply Block
-	if loser.SupplyAnalysis != nil && winner.SupplyAnalysis != nil {
+	if player.SupplyAnalysis != nil && opponent.SupplyAnalysis != nil {
 		comparisons = append(comparisons, models.MetricComparison{
-			Metric:      "Supply Block Zeit",
-			PlayerValue: loser.SupplyAnalysis.BlockPercentage,
-			EnemyValue:  winner.SupplyAnalysis.BlockPercentage,
-			IsWorse:     loser.SupplyAnalysis.BlockPercentage > winner.SupplyAnalysis.BlockPercentage*1.5,
+			Metric:      sa.t("strategic.metric.supply_block_time"),
+			PlayerValue: player.SupplyAnalysis.BlockPercentage,
+			EnemyValue:  opponent.SupplyAnalysis.BlockPercentage,
+			IsWorse:     player.SupplyAnalysis.BlockPercentage > opponent.SupplyAnalysis.BlockPercentage*1.5,
 		})
 		comparisons = append(comparisons, models.MetricComparison{
-			Metric:      "Anzahl Supply Blocks",
-			PlayerValue: float64(len(loser.SupplyAnalysis.Blocks)),
-			EnemyValue:  float64(len(winner.SupplyAnalysis.Blocks)),
-			IsWorse:     len(loser.SupplyAnalysis.Blocks) > len(winner.SupplyAnalysis.Blocks)+2,
+			Metric:      sa.t("strategic.metric.supply_block_count"),
+			PlayerValue: float64(len(player.SupplyAnalysis.Blocks)),
+			EnemyValue:  float64(len(opponent.SupplyAnalysis.Blocks)),
+			IsWorse:     len(player.SupplyAnalysis.Blocks) > len(opponent.SupplyAnalysis.Blocks)+2,
 		})
 	}
 
 	// Army
-	if loser.ArmyAnalysis != nil && winner.ArmyAnalysis != nil {
+	if player.ArmyAnalysis != nil && opponent.ArmyAnalysis != nil {
 		comparisons = append(comparisons, models.MetricComparison{
-			Metric:      "Peak Armeewert",
-			PlayerValue: float64(loser.ArmyAnalysis.PeakArmyValue),
-			EnemyValue:  float64(winner.ArmyAnalysis.PeakArmyValue),
-			IsWorse:     loser.ArmyAnalysis.PeakArmyValue < winner.ArmyAnalysis.PeakArmyValue/2,
+			Metric:      sa.t("strategic.metric.peak_army"),
+			PlayerValue: float64(player.ArmyAnalysis.PeakArmyValue),
+			EnemyValue:  float64(opponent.ArmyAnalysis.PeakArmyValue),
+			IsWorse:     player.ArmyAnalysis.PeakArmyValue < opponent.ArmyAnalysis.PeakArmyValue/2,
 		})
 	}
 
@@ -136,6 +240,7 @@ func (sa *StrategicAnalyzer) extractSupplyBlocks(analysis *models.AnalysisData)
 			Time:     b.StartTime,
 			Duration: b.Duration,
 			Severity: b.Severity,
+			Cause:    b.Cause,
 		})
 	}
 
@@ -143,70 +248,70 @@ func (sa *StrategicAnalyzer) extractSupplyBlocks(analysis *models.AnalysisData)
 }
 
 // findCriticalMoments findet kritische Kampfmomente
-func (sa *StrategicAnalyzer) findCriticalMoments(loser, winner *models.AnalysisData) []models.CriticalMoment {
+func (sa *StrategicAnalyzer) findCriticalMoments(player, opponent *models.AnalysisData) []models.CriticalMoment {
 	var moments []models.CriticalMoment
 
-	if loser.ArmyAnalysis == nil || winner.ArmyAnalysis == nil {
+	if player.ArmyAnalysis == nil || opponent.ArmyAnalysis == nil {
 		return moments
 	}
 
-	loserTimeline := loser.ArmyAnalysis.ArmyTimeline
-	winnerTimeline := winner.ArmyAnalysis.ArmyTimeline
+	playerTimeline := player.ArmyAnalysis.ArmyTimeline
+	opponentTimeline := opponent.ArmyAnalysis.ArmyTimeline
 
-	// Erstelle eine Map für schnellen Zugriff auf Winner-Werte nach Zeit
-	winnerValues := make(map[float64]int)
-	for _, point := range winnerTimeline {
-		winnerValues[point.Time] = point.UnitCount
+	// Erstelle eine Map für schnellen Zugriff auf Gegner-Werte nach Zeit
+	opponentValues := make(map[float64]int)
+	for _, point := range opponentTimeline {
+		opponentValues[point.Time] = point.UnitCount
 	}
 
-	var lastLoserCount, lastWinnerCount int
-	for i, point := range loserTimeline {
+	var lastPlayerCount, lastOpponentCount int
+	for i, point := range playerTimeline {
 		if i == 0 {
-			lastLoserCount = point.UnitCount
-			if wc, ok := winnerValues[point.Time]; ok {
-				lastWinnerCount = wc
+			lastPlayerCount = point.UnitCount
+			if wc, ok := opponentValues[point.Time]; ok {
+				lastOpponentCount = wc
 			}
 			continue
 		}
 
-		loserLoss := lastLoserCount - point.UnitCount
-		winnerLoss := 0
-		if wc, ok := winnerValues[point.Time]; ok {
-			winnerLoss = lastWinnerCount - wc
-			lastWinnerCount = wc
+		playerLoss := lastPlayerCount - point.UnitCount
+		opponentLoss := 0
+		if wc, ok := opponentValues[point.Time]; ok {
+			opponentLoss = lastOpponentCount - wc
+			lastOpponentCount = wc
 		}
 
 		// Signifikante Verluste
-		if loserLoss > 2 || winnerLoss > 2 {
+		if playerLoss > 2 || opponentLoss > 2 {
 			assessment := ""
 			isPositive := false
 
-			if loserLoss > 0 && winnerLoss == 0 {
-				assessment = "Einseitige Verluste"
-			} else if loserLoss > winnerLoss*2 {
-				assessment = "Schlechter Trade"
-			} else if loserLoss < winnerLoss {
-				assessment = "Guter Trade!"
+			if playerLoss > 0 && opponentLoss == 0 {
+				assessment = sa.t("strategic.moment.one_sided")
+			} else if playerLoss > opponentLoss*2 {
+				assessment = sa.t("strategic.moment.bad_trade")
+			} else if playerLoss < opponentLoss {
+				assessment = sa.t("strategic.moment.good_trade")
 				isPositive = true
-			} else if loserLoss > winnerLoss {
-				assessment = "Leichter Nachteil"
+			} else if playerLoss > opponentLoss {
+				assessment = sa.t("strategic.moment.slight_disadvantage")
 			} else {
-				assessment = "Vorteil für dich"
+				assessment = sa.t("strategic.moment.advantage")
 				isPositive = true
 			}
 
-			if loserLoss > 0 || winnerLoss > 0 {
+			if playerLoss > 0 || opponentLoss > 0 {
 				moments = append(moments, models.CriticalMoment{
 					Time:       point.Time,
-					PlayerLoss: loserLoss,
-					EnemyLoss:  winnerLoss,
+					PlayerLoss: playerLoss,
+					EnemyLoss:  opponentLoss,
 					Assessment: assessment,
 					IsPositive: isPositive,
 				})
 			}
 		}
 
-		lastLoserCount = point.UnitCount
+		lastPlayerCount = point.UnitCount
 	}
 
 	// Limitiere auf die wichtigsten 10 Momente
@@ -217,46 +322,123 @@ func (sa *StrategicAnalyzer) findCriticalMoments(loser, winner *models.AnalysisD
 	return moments
 }
 
+// findProxyLosses sammelt Proxy-Gebäude, die vor Fertigstellung zerstört wurden
+func (sa *StrategicAnalyzer) findProxyLosses(playerName, opponentName string, player, opponent *models.AnalysisData, result string) []models.ProxyLoss {
+	var losses []models.ProxyLoss
+
+	collect := func(name string, data *models.AnalysisData, isPlayer, isLoser bool) {
+		for _, item := range data.BuildOrder {
+			if item.IsProxy && item.Status == "destroyed" {
+				losses = append(losses, models.ProxyLoss{
+					Player:        name,
+					Building:      item.UnitOrBuilding,
+					StartTime:     item.Time,
+					LostTime:      item.LostTime,
+					IsLoserProxy:  isLoser,
+					IsPlayerProxy: isPlayer,
+				})
+			}
+		}
+	}
+
+	collect(playerName, player, true, result == "Loss")
+	collect(opponentName, opponent, false, result == "Win")
+
+	return losses
+}
+
 // identifyProblems identifiziert die Hauptprobleme
-func (sa *StrategicAnalyzer) identifyProblems(loser, winner *models.AnalysisData) []models.IdentifiedProblem {
+func (sa *StrategicAnalyzer) identifyProblems(player, opponent *models.AnalysisData) []models.IdentifiedProblem {
 	var problems []models.IdentifiedProblem
 
 	// Supply Blocks
-	if loser.SupplyAnalysis != nil && loser.SupplyAnalysis.BlockPercentage > 10 {
+	if player.SupplyAnalysis != nil && player.SupplyAnalysis.BlockPercentage > 10 {
 		problems = append(problems, models.IdentifiedProblem{
-			Title:       fmt.Sprintf("Supply Blocks (%.1f%% der Zeit)", loser.SupplyAnalysis.BlockPercentage),
-			Description: "Du warst zu oft supply-blocked und konntest keine Einheiten produzieren.",
+			Key:         "supply_blocks",
+			Title:       sa.t("strategic.problem.supply_blocks.title", i18n.Params{"percent": fmt.Sprintf("%.1f", player.SupplyAnalysis.BlockPercentage)}),
+			Description: sa.t("strategic.problem.supply_blocks.description"),
 			Priority:    "high",
 		})
 	}
 
 	// Spending
-	if loser.SpendingAnalysis != nil && loser.SpendingAnalysis.SpendingQuotient < 50 {
+	if player.SpendingAnalysis != nil && player.SpendingAnalysis.SpendingQuotient < 50 {
+		problems = append(problems, models.IdentifiedProblem{
+			Key:         "low_spending",
+			Title:       sa.t("strategic.problem.low_spending.title", i18n.Params{"sq": int(player.SpendingAnalysis.SpendingQuotient)}),
+			Description: sa.t("strategic.problem.low_spending.description"),
+			Priority:    "high",
+		})
+	}
+
+	// Proxy vom Gegner entdeckt und zerstört
+	var lostProxies []string
+	for _, item := range player.BuildOrder {
+		if item.IsProxy && item.Status == "destroyed" {
+			lostProxies = append(lostProxies, item.UnitOrBuilding)
+		}
+	}
+	if len(lostProxies) > 0 {
 		problems = append(problems, models.IdentifiedProblem{
-			Title:       fmt.Sprintf("Niedriger Spending Quotient (%d)", int(loser.SpendingAnalysis.SpendingQuotient)),
-			Description: "Du hast zu viele Ressourcen angesammelt ohne sie auszugeben.",
+			Key:         "proxy_destroyed",
+			Title:       sa.t("strategic.problem.proxy_destroyed.title", i18n.Params{"count": len(lostProxies)}),
+			Description: sa.t("strategic.problem.proxy_destroyed.description", i18n.Params{"buildings": strings.Join(lostProxies, ", ")}),
 			Priority:    "high",
 		})
 	}
 
+	// Worker-Verluste durch Harass
+	if player.HarassmentAnalysis != nil && player.HarassmentAnalysis.TotalWorkersLost >= 6 {
+		worst := player.HarassmentAnalysis.Incidents[0]
+		for _, inc := range player.HarassmentAnalysis.Incidents {
+			if inc.WorkersLost > worst.WorkersLost {
+				worst = inc
+			}
+		}
+		attacker := sa.t("strategic.problem.harass_workers.attacker")
+		if len(worst.AttackerTypes) > 0 {
+			attacker = strings.Join(worst.AttackerTypes, "/")
+		}
+		problems = append(problems, models.IdentifiedProblem{
+			Key:   "harass_workers",
+			Title: sa.t("strategic.problem.harass_workers.title", i18n.Params{"count": player.HarassmentAnalysis.TotalWorkersLost}),
+			Description: sa.t("strategic.problem.harass_workers.description", i18n.Params{
+				"count":    player.HarassmentAnalysis.TotalWorkersLost,
+				"worst":    worst.WorkersLost,
+				"time":     parser.FormatTime(worst.Start),
+				"attacker": attacker,
+				"mining":   fmt.Sprintf("%.0f", player.HarassmentAnalysis.TotalMiningTimeLost),
+			}),
+			Priority: "high",
+		})
+	}
+
 	// APM
-	if loser.APMAnalysis != nil && winner.APMAnalysis != nil {
-		if loser.APMAnalysis.AverageAPM < winner.APMAnalysis.AverageAPM*0.7 {
+	if player.APMAnalysis != nil && opponent.APMAnalysis != nil {
+		if player.APMAnalysis.AverageAPM < opponent.APMAnalysis.AverageAPM*0.7 {
 			problems = append(problems, models.IdentifiedProblem{
-				Title:       "Deutlich niedrigere APM als Gegner",
-				Description: fmt.Sprintf("Deine APM (%.0f) war deutlich niedriger als die des Gegners (%.0f).", loser.APMAnalysis.AverageAPM, winner.APMAnalysis.AverageAPM),
-				Priority:    "medium",
+				Key:   "low_apm",
+				Title: sa.t("strategic.problem.low_apm.title"),
+				Description: sa.t("strategic.problem.low_apm.description", i18n.Params{
+					"apm":       fmt.Sprintf("%.0f", player.APMAnalysis.AverageAPM),
+					"enemy_apm": fmt.Sprintf("%.0f", opponent.APMAnalysis.AverageAPM),
+				}),
+				Priority: "medium",
 			})
 		}
 	}
 
 	// Army Value
-	if loser.ArmyAnalysis != nil && winner.ArmyAnalysis != nil {
-		if loser.ArmyAnalysis.PeakArmyValue < winner.ArmyAnalysis.PeakArmyValue/2 {
+	if player.ArmyAnalysis != nil && opponent.ArmyAnalysis != nil {
+		if player.ArmyAnalysis.PeakArmyValue < opponent.ArmyAnalysis.PeakArmyValue/2 {
 			problems = append(problems, models.IdentifiedProblem{
-				Title:       "Zu wenig Armee produziert",
-				Description: fmt.Sprintf("Dein Peak-Armeewert (%d) war deutlich niedriger als der des Gegners (%d).", loser.ArmyAnalysis.PeakArmyValue, winner.ArmyAnalysis.PeakArmyValue),
-				Priority:    "high",
+				Key:   "low_army",
+				Title: sa.t("strategic.problem.low_army.title"),
+				Description: sa.t("strategic.problem.low_army.description", i18n.Params{
+					"army":       player.ArmyAnalysis.PeakArmyValue,
+					"enemy_army": opponent.ArmyAnalysis.PeakArmyValue,
+				}),
+				Priority: "high",
 			})
 		}
 	}
@@ -264,239 +446,347 @@ func (sa *StrategicAnalyzer) identifyProblems(loser, winner *models.AnalysisData
 	return problems
 }
 
-// getMatchupTips gibt matchup-spezifische Tipps zurück
-func (sa *StrategicAnalyzer) getMatchupTips(loserRace, winnerRace string) *models.MatchupTips {
-	loserRaceLower := strings.ToLower(loserRace)
-	winnerRaceLower := strings.ToLower(winnerRace)
+// findStrengths sammelt, was der Spieler besser gemacht hat als sein Gegner
+func (sa *StrategicAnalyzer) findStrengths(player, opponent *models.AnalysisData, moments []models.CriticalMoment) []models.StrategicInsight {
+	var strengths []models.StrategicInsight
 
-	tips := &models.MatchupTips{}
+	add := func(key string, params i18n.Params) {
+		strengths = append(strengths, models.StrategicInsight{
+			Key:         key,
+			Title:       sa.t("strategic.strength."+key+".title", params),
+			Description: sa.t("strategic.strength."+key+".description", params),
+		})
+	}
 
-	switch loserRaceLower + "v" + winnerRaceLower {
-	case "protossvzerg":
-		tips.Opening = []string{
-			"Standard: Gate → Nexus → Cyber → Stargate/Robo",
-			"Scout mit Adept Shade oder erstem Stalker",
-			"Früh Wall-Off gegen Zerglings",
-		}
-		tips.MidGame = []string{
-			"Gegen Roach/Ravager: Immortals + Chargelots",
-			"Gegen Hydras: Storm ist ESSENTIELL",
-			"Gegen Mutas: Phoenix oder schnell Archons",
-		}
-		tips.Timing = []string{
-			"2-Base All-in mit Immortal/Archon ~7:30",
-			"Oder 8-Gate Chargelot Timing ~6:00",
-			"Greife VOR Hive-Tech an!",
-		}
-		tips.LateGame = []string{
-			"Carrier/Tempest + Storm + Archons",
-			"Braucht gute Upgrades (3/3)",
-			"Vermeide große Kämpfe ohne Storm",
-		}
+	// Spending
+	if player.SpendingAnalysis != nil && opponent.SpendingAnalysis != nil &&
+		player.SpendingAnalysis.SpendingQuotient >= opponent.SpendingAnalysis.SpendingQuotient+15 {
+		add("spending", i18n.Params{
+			"sq":       int(player.SpendingAnalysis.SpendingQuotient),
+			"enemy_sq": int(opponent.SpendingAnalysis.SpendingQuotient),
+		})
+	}
 
-	case "zergvprotoss":
-		tips.Opening = []string{
-			"Hatch first ist standard gegen Protoss",
-			"Speedlings für frühen Druck möglich",
-			"Overlord Scout bei 3:00-3:30",
-		}
-		tips.MidGame = []string{
-			"Roach/Ravager gegen Immortal-Archon",
-			"Lurkers gegen Ground-Armies",
-			"Mutas wenn Protoss wenig Anti-Air hat",
-		}
-		tips.Timing = []string{
-			"Roach/Ravager Timing bei ~5:00",
-			"Ling/Bane All-in gegen greedy Builds",
-		}
-		tips.LateGame = []string{
-			"Broodlord/Corruptor/Viper Deathball",
-			"Infestors gegen Carrier",
-			"Immer auf Flächeneffekte achten",
-		}
+	// Supply (erst ab 5 Minuten Spielzeit aussagekräftig)
+	if sup := player.SupplyAnalysis; sup != nil && len(sup.SupplyTimeline) > 0 &&
+		sup.SupplyTimeline[len(sup.SupplyTimeline)-1].Time >= 300 && sup.BlockPercentage < 3 {
+		add("supply", i18n.Params{"percent": fmt.Sprintf("%.1f", sup.BlockPercentage)})
+	}
 
-	case "terranvzerg":
-		tips.Opening = []string{
-			"Reaper Scout + CC first üblich",
-			"Hellion Harass zum Dronen-Killen",
-			"Wall-Off gegen Zerglings",
-		}
-		tips.MidGame = []string{
-			"Marine/Tank gegen Roach/Hydra",
-			"Liberators gegen Mutas",
-			"Hellbats gegen Ling/Bane",
-		}
-		tips.Timing = []string{
-			"2-1-1 Push mit Medivacs ~5:30",
-			"Oder 3CC Macro-Spiel",
-		}
-		tips.LateGame = []string{
-			"Ghost/Lib/Thor Komposition",
-			"Vikings + Thors gegen Broodlords",
-		}
+	// APM
+	if player.APMAnalysis != nil && opponent.APMAnalysis != nil &&
+		player.APMAnalysis.AverageAPM > opponent.APMAnalysis.AverageAPM*1.3 {
+		add("apm", i18n.Params{
+			"apm":       fmt.Sprintf("%.0f", player.APMAnalysis.AverageAPM),
+			"enemy_apm": fmt.Sprintf("%.0f", opponent.APMAnalysis.AverageAPM),
+		})
+	}
 
-	case "zergvterran":
-		tips.Opening = []string{
-			"3 Hatch vor Pool oft gut",
-			"Ling/Bane gegen Hellions",
-			"Scout für Banshees/Liberators",
-		}
-		tips.MidGame = []string{
-			"Ling/Bane/Muta klassisch",
-			"Oder Roach/Ravager/Hydra",
-		}
-		tips.Timing = []string{
-			"2-Base Ling/Bane Timing möglich",
-			"Roach/Ravager All-in bei ~4:30",
-		}
-		tips.LateGame = []string{
-			"Ultras + Vipers gegen Mech",
-			"Infestors gegen Bio",
-		}
+	// Army Value
+	if player.ArmyAnalysis != nil && opponent.ArmyAnalysis != nil &&
+		player.ArmyAnalysis.PeakArmyValue > 0 && float64(player.ArmyAnalysis.PeakArmyValue) >= float64(opponent.ArmyAnalysis.PeakArmyValue)*1.5 {
+		add("army", i18n.Params{
+			"army":       player.ArmyAnalysis.PeakArmyValue,
+			"enemy_army": opponent.ArmyAnalysis.PeakArmyValue,
+		})
+	}
 
-	case "protossvterran":
-		tips.Opening = []string{
-			"Gate Expand Standard",
-			"Robo oder Stargate Tech",
-			"Scout für Proxy-Barracks",
-		}
-		tips.MidGame = []string{
-			"Chargelot/Archon/Immortal core",
-			"Colossus oder Disruptors hinzufügen",
-			"Storm gegen Bio",
-		}
-		tips.Timing = []string{
-			"Blink-Stalker Timing ~5:00",
-			"Chargelot All-in gegen Mech",
-		}
-		tips.LateGame = []string{
-			"Carrier/Tempest bei gutem Eco",
-			"Feedback gegen Ghosts",
+	// Gute Trades
+	goodTrades := 0
+	for _, m := range moments {
+		if m.IsPositive {
+			goodTrades++
 		}
+	}
+	if goodTrades >= 2 {
+		add("trades", i18n.Params{"count": goodTrades})
+	}
 
-	case "terranvprotoss":
-		tips.Opening = []string{
-			"Reaper Expand üblich",
-			"Factory für Cyclone/Tank",
-			"Scout für DTs/Oracles",
-		}
-		tips.MidGame = []string{
-			"Bio + Medivac + Ghosts",
-			"Widow Mines gegen Chargelots",
-			"Liberators für Zonen-Control",
-		}
-		tips.Timing = []string{
-			"Stim-Timing ~5:30",
-			"Liberator Harass",
-		}
-		tips.LateGame = []string{
-			"Ghosts sind ESSENTIELL",
-			"Vikings gegen Carriers",
-		}
+	// Harass gegen die Worker des Gegners
+	if opponent.HarassmentAnalysis != nil && opponent.HarassmentAnalysis.TotalWorkersLost >= 6 {
+		add("harass", i18n.Params{"count": opponent.HarassmentAnalysis.TotalWorkersLost})
+	}
 
-	// Spiegel-Matchups
-	default:
-		tips.Opening = []string{
-			"Nutze Standard-Openings für deine Rasse",
-			"Scout früh um Cheese zu erkennen",
+	return strengths
+}
+
+// findCloseCalls sammelt Momente, in denen das Spiel hätte kippen können:
+// bei einem Sieg der tiefste Punkt der Siegchance und teure Kämpfe, bei einer Niederlage der größte Vorsprung
+func (sa *StrategicAnalyzer) findCloseCalls(wp *models.WinProbability, moments []models.CriticalMoment, result string) []models.StrategicInsight {
+	var calls []models.StrategicInsight
+
+	if wp != nil && len(wp.Timeline) > 0 {
+		lowest, highest := wp.Timeline[0], wp.Timeline[0]
+		for _, point := range wp.Timeline {
+			if point.Probability < lowest.Probability {
+				lowest = point
+			}
+			if point.Probability > highest.Probability {
+				highest = point
+			}
 		}
-		tips.MidGame = []string{
-			"Konzentriere dich auf gute Macro",
-			"Baue Einheiten kontinuierlich",
+		if result != "Loss" && lowest.Probability < 0.35 {
+			calls = append(calls, sa.closeCall("comeback", lowest.Time, i18n.Params{"percent": fmt.Sprintf("%.0f", lowest.Probability*100)}))
 		}
-		tips.Timing = []string{
-			"Greife an wenn du einen Vorteil hast",
-			"Timing-Attacks bei Tech-Switches",
+		if result != "Win" && highest.Probability > 0.65 {
+			calls = append(calls, sa.closeCall("lead_lost", highest.Time, i18n.Params{"percent": fmt.Sprintf("%.0f", highest.Probability*100)}))
 		}
-		tips.LateGame = []string{
-			"Upgrades sind entscheidend",
-			"Kontrolliere die Map",
+	}
+
+	// Teure Kämpfe, die der Gegner nicht ausnutzen konnte
+	if result == "Win" {
+		for _, m := range moments {
+			if !m.IsPositive && m.PlayerLoss > m.EnemyLoss*2 {
+				calls = append(calls, sa.closeCall("bad_trade", m.Time, i18n.Params{"loss": m.PlayerLoss, "enemy_loss": m.EnemyLoss}))
+			}
+			if len(calls) >= 4 {
+				break
+			}
 		}
 	}
 
+	return calls
+}
+
+// closeCall erstellt einen knappen Moment mit Zeitangabe
+func (sa *StrategicAnalyzer) closeCall(key string, time float64, params i18n.Params) models.StrategicInsight {
+	params["time"] = parser.FormatTime(time)
+	return models.StrategicInsight{
+		Key:         key,
+		Time:        time,
+		Title:       sa.t("strategic.close_call."+key+".title", params),
+		Description: sa.t("strategic.close_call."+key+".description", params),
+	}
+}
+
+// getMatchupTips gibt Tipps aus der Wissensbasis für das Matchup, die Spielversion
+// und die erkannte Strategie des Gegners zurück
+func (sa *StrategicAnalyzer) getMatchupTips(player, opponent *models.GamePlayer, opponentAnalysis *models.AnalysisData) *models.MatchupTips {
+	own := raceCode(player.Race)
+	matchup := Matchup(player.Race, opponent.Race)
+
+	tips := &models.MatchupTips{Matchup: matchup}
+	entries := sa.findMatchup(matchup)
+	if len(entries) == 0 {
+		entries = sa.findMatchup("default")
+	}
+	if len(entries) > 0 {
+		tips.Patch = patchLabel(entries[0].MinVersion, entries[0].MaxVersion)
+	}
+	tips.Opening = sa.phaseTips(entries, func(e *models.MatchupEntry) map[string][]string { return e.Opening })
+	tips.MidGame = sa.phaseTips(entries, func(e *models.MatchupEntry) map[string][]string { return e.MidGame })
+	tips.Timing = sa.phaseTips(entries, func(e *models.MatchupEntry) map[string][]string { return e.Timing })
+	tips.LateGame = sa.phaseTips(entries, func(e *models.MatchupEntry) map[string][]string { return e.LateGame })
+
+	// Random-Gegner: Rasse ist erst nach dem Scouten bekannt
+	if opponent.RandomRace {
+		random := sa.findMatchup(own + "vR")
+		tips.VsRandom = append(tips.VsRandom, sa.phaseTips(random, func(e *models.MatchupEntry) map[string][]string { return e.Opening })...)
+		tips.VsRandom = append(tips.VsRandom, sa.phaseTips(random, func(e *models.MatchupEntry) map[string][]string { return e.MidGame })...)
+		tips.VsRandom = append(tips.VsRandom, sa.phaseTips(random, func(e *models.MatchupEntry) map[string][]string { return e.Timing })...)
+		tips.VsRandom = append(tips.VsRandom, sa.phaseTips(random, func(e *models.MatchupEntry) map[string][]string { return e.LateGame })...)
+	}
+
+	tips.OpponentStrategy = sa.detectStrategy(opponent.Race, player.Race, opponentAnalysis.BuildOrder)
+	tips.Reference = sa.ReferenceTips()
 	return tips
 }
 
+// ReferenceTips beschreibt die Rückstände gegenüber dem Referenz-Replay (siehe SetReference) als Tipps
+func (sa *StrategicAnalyzer) ReferenceTips() *models.ReferenceTips {
+	if sa.reference == nil {
+		return nil
+	}
+	ref := sa.reference.Reference
+	tips := &models.ReferenceTips{
+		ReferenceID: ref.ID,
+		ReplayID:    ref.ReplayID,
+		Player:      ref.Player,
+		Tips:        []string{},
+	}
+	if ref.Build != "" {
+		tips.Build = sa.strategyName(ref.Build)
+	}
+
+	for _, gap := range sa.reference.Gaps {
+		tips.Tips = append(tips.Tips, sa.t("strategic.reference.gap."+gap.Metric, i18n.Params{
+			"player":    ref.Player,
+			"start":     parser.FormatTime(gap.Start),
+			"end":       parser.FormatTime(gap.End),
+			"time":      parser.FormatTime(gap.PeakTime),
+			"value":     gap.Value,
+			"reference": gap.ReferenceValue,
+			"deficit":   gap.Deficit,
+			"percent":   gap.Percent,
+		}))
+	}
+	if len(tips.Tips) == 0 {
+		tips.Tips = append(tips.Tips, sa.t("strategic.reference.on_par", i18n.Params{"player": ref.Player}))
+	}
+	return tips
+}
+
+// Matchup gibt das Matchup aus Sicht einer Rasse zurück, z.B. "PvZ"
+func Matchup(race, opponentRace string) string {
+	return raceCode(race) + "v" + raceCode(opponentRace)
+}
+
+// raceCode gibt den Buchstaben einer Rasse für Matchups zurück ("X" wenn unbekannt)
+func raceCode(race string) string {
+	switch strings.ToLower(race) {
+	case "terran":
+		return "T"
+	case "zerg":
+		return "Z"
+	case "protoss":
+		return "P"
+	}
+	return "X"
+}
+
+// stepCategories ordnet Problemen die Kategorie ihres Verbesserungsschritts zu
+var stepCategories = map[string]string{
+	"supply_blocks": "MACRO",
+	"low_spending":  "MACRO",
+	"low_apm":       "MECHANICS",
+	"low_army":      "PRODUCTION",
+}
+
 // generateImprovementSteps erstellt konkrete Verbesserungsschritte
 func (sa *StrategicAnalyzer) generateImprovementSteps(problems []models.IdentifiedProblem) []models.ImprovementStep {
 	var steps []models.ImprovementStep
 
 	for _, p := range problems {
-		switch {
-		case strings.Contains(p.Title, "Supply Block"):
-			steps = append(steps, models.ImprovementStep{
-				Category:    "MACRO",
-				Title:       "Pylons/Depots/Overlords früher bauen",
-				Description: "Baue Supply-Gebäude BEVOR du Supply brauchst. Regel: Bei 75% Supply, baue das nächste Supply-Gebäude.",
-			})
-		case strings.Contains(p.Title, "Spending"):
-			steps = append(steps, models.ImprovementStep{
-				Category:    "MACRO",
-				Title:       "Ressourcen schneller ausgeben",
-				Description: "Füge mehr Produktionsgebäude hinzu oder starte Upgrades. Ressourcen auf der Bank gewinnen keine Spiele.",
-			})
-		case strings.Contains(p.Title, "APM"):
-			steps = append(steps, models.ImprovementStep{
-				Category:    "MECHANICS",
-				Title:       "Hotkeys und Kamera-Shortcuts üben",
-				Description: "Nutze Control-Groups für Armee und Produktionsgebäude. Übe schnelle Camera-Location Hotkeys.",
-			})
-		case strings.Contains(p.Title, "Armee"):
-			steps = append(steps, models.ImprovementStep{
-				Category:    "PRODUCTION",
-				Title:       "Kontinuierlich Einheiten produzieren",
-				Description: "Halte deine Produktionsgebäude aktiv. Füge mehr Barracks/Gates/Hatcheries hinzu wenn du Ressourcen ansammelst.",
-			})
+		if category, ok := stepCategories[p.Key]; ok {
+			steps = append(steps, sa.improvementStep(p.Key, category))
 		}
 	}
 
 	// Allgemeine Tipps hinzufügen
-	steps = append(steps, models.ImprovementStep{
-		Category:    "BUILD ORDER",
-		Title:       "Übe einen Standard-Build",
-		Description: "Wähle einen Build und übe ihn im Custom Game bis du ihn blind ausführen kannst. Nutze spawningtool.com für Referenz-Builds.",
-	})
-
-	steps = append(steps, models.ImprovementStep{
-		Category:    "SCOUTING",
-		Title:       "Regelmäßig scouten",
-		Description: "Scout bei 3:30-4:00 für Tech-Gebäude. Reagiere auf das was du siehst statt blind zu spielen.",
-	})
+	steps = append(steps, sa.improvementStep("build_order", "BUILD ORDER"))
+	steps = append(steps, sa.improvementStep("scouting", "SCOUTING"))
 
 	return steps
 }
 
-// generateSummary erstellt eine Zusammenfassung
+// improvementStep erstellt einen Verbesserungsschritt aus dem Katalog
+func (sa *StrategicAnalyzer) improvementStep(key, category string) models.ImprovementStep {
+	return models.ImprovementStep{
+		Key:         key,
+		Category:    category,
+		Title:       sa.t("strategic.step." + key + ".title"),
+		Description: sa.t("strategic.step." + key + ".description"),
+	}
+}
+
+// summaryReasons sind die Probleme, die als Hauptgrund in der Zusammenfassung erscheinen
+var summaryReasons = map[string]bool{
+	"supply_blocks":   true,
+	"low_spending":    true,
+	"low_apm":         true,
+	"low_army":        true,
+	"harass_workers":  true,
+	"proxy_destroyed": true,
+}
+
+// generateSummary erstellt eine Zusammenfassung aus Sicht des Spielers
 func (sa *StrategicAnalyzer) generateSummary(analysis *models.StrategicAnalysis) string {
-	var mainReasons []string
-
-	for _, p := range analysis.Problems {
-		if p.Priority == "high" {
-			switch {
-			case strings.Contains(p.Title, "Supply"):
-				mainReasons = append(mainReasons, "Zu viele Supply Blocks → weniger Einheiten produziert")
-			case strings.Contains(p.Title, "Spending"):
-				mainReasons = append(mainReasons, "Ressourcen nicht ausgegeben → schwächere Armee")
-			case strings.Contains(p.Title, "APM"):
-				mainReasons = append(mainReasons, "Niedrigere APM → langsamere Reaktionen")
-			case strings.Contains(p.Title, "Armee"):
-				mainReasons = append(mainReasons, "Zu wenig Armee produziert → konnte nicht verteidigen")
+	races := i18n.Params{"race": analysis.PlayerRace, "enemy_race": analysis.OpponentRace}
+
+	var summary string
+	if analysis.ResultInference != nil {
+		summary = analysis.ResultInference.Description + "\n\n"
+	}
+
+	switch analysis.Result {
+	case "Win":
+		summary += sa.t("strategic.summary.won", races)
+		if len(analysis.Strengths) > 0 {
+			summary += "\n\n" + sa.t("strategic.summary.strengths") + "\n"
+			for _, s := range analysis.Strengths {
+				summary += fmt.Sprintf("• %s\n", s.Title)
+			}
+		}
+		if len(analysis.UnpunishedMistakes) > 0 {
+			summary += "\n" + sa.t("strategic.summary.unpunished") + "\n"
+			for _, p := range analysis.UnpunishedMistakes {
+				summary += fmt.Sprintf("• %s\n", p.Title)
 			}
 		}
-	}
 
-	if len(mainReasons) == 0 {
-		for _, p := range analysis.Problems {
-			if p.Priority == "medium" {
-				mainReasons = append(mainReasons, p.Title)
+	case "Loss":
+		summary += sa.t("strategic.summary.lost", races)
+		summary += "\n\n" + sa.t("strategic.summary.reasons") + "\n"
+		for _, reason := range sa.mainReasons(analysis.Problems) {
+			summary += fmt.Sprintf("• %s\n", reason)
+		}
+
+	default:
+		summary += sa.t("strategic.summary.undecided", races)
+		if reasons := sa.mainReasons(analysis.Problems); len(reasons) > 0 {
+			summary += "\n\n" + sa.t("strategic.summary.weaknesses") + "\n"
+			for _, reason := range reasons {
+				summary += fmt.Sprintf("• %s\n", reason)
 			}
 		}
+		return summary
 	}
 
-	summary := fmt.Sprintf("Du hast als %s gegen %s verloren.\n\nDie HAUPTGRÜNDE waren wahrscheinlich:\n", analysis.LoserRace, analysis.WinnerRace)
-	for _, reason := range mainReasons {
-		summary += fmt.Sprintf("• %s\n", reason)
+	if when := sa.describeTurningPoint(analysis.WinProbability, analysis.Result); when != "" {
+		summary += "\n" + when + "\n"
 	}
 
 	return summary
 }
+
+// mainReasons gibt die wichtigsten Probleme als kurze Gründe zurück
+func (sa *StrategicAnalyzer) mainReasons(problems []models.IdentifiedProblem) []string {
+	var reasons []string
+
+	for _, p := range problems {
+		if p.Priority == "high" && summaryReasons[p.Key] {
+			reasons = append(reasons, sa.t("strategic.reason."+p.Key))
+		}
+	}
+
+	if len(reasons) == 0 {
+		for _, p := range problems {
+			if p.Priority == "medium" {
+				reasons = append(reasons, p.Title)
+			}
+		}
+	}
+
+	return reasons
+}
+
+// describeTurningPoint beschreibt, wann das Spiel gekippt ist: bei einer Niederlage der größte
+// Einbruch der Siegchance, bei einem Sieg der größte Anstieg
+func (sa *StrategicAnalyzer) describeTurningPoint(wp *models.WinProbability, result string) string {
+	if wp == nil {
+		return ""
+	}
+	won := result == "Win"
+	var biggest *models.TurningPoint
+	for i := range wp.TurningPoints {
+		tp := &wp.TurningPoints[i]
+		if won && tp.Change > 0 && (biggest == nil || tp.Change > biggest.Change) {
+			biggest = tp
+		}
+		if !won && tp.Change < 0 && (biggest == nil || tp.Change < biggest.Change) {
+			biggest = tp
+		}
+	}
+	if biggest == nil {
+		return sa.t("strategic.summary.decided_at", i18n.Params{"time": parser.FormatTime(wp.DecidedAt)})
+	}
+	key := "strategic.summary.turning_point"
+	if won {
+		key = "strategic.summary.turning_point_won"
+	}
+	return sa.t(key, i18n.Params{
+		"start":       parser.FormatTime(biggest.Start),
+		"end":         parser.FormatTime(biggest.End),
+		"description": winprob.DescribeTurningPoint(*biggest, sa.lang),
+	})
+}
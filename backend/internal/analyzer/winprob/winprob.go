@@ -0,0 +1,378 @@
+package winprob
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+	"sc2-analytics/internal/parser"
+)
+
+const (
+	// turningWindow ist das Zeitfenster (Sekunden), in dem ein Umschwung gemessen wird
+	turningWindow = 60.0
+	// turningThreshold ist die Mindeständerung der Wahrscheinlichkeit für einen Wendepunkt
+	turningThreshold = 0.2
+	// maxTurningPoints begrenzt die Anzahl gemeldeter Wendepunkte
+	maxTurningPoints = 5
+)
+
+// featureNames sind die Modell-Features in Koeffizienten-Reihenfolge
+var featureNames = []string{"army_lead", "lost_diff", "worker_diff", "base_diff", "supply_diff"}
+
+// Features sind die skalierten Differenzen Spieler minus Gegner zu einem Zeitpunkt
+type Features [5]float64
+
+// Sample ist ein Feature-Vektor mit Zeitpunkt und den Rohwerten
+type Sample struct {
+	Time     float64
+	Features Features
+	Raw      models.WinProbabilityPoint
+}
+
+// DefaultModel gibt handgesetzte Koeffizienten zurück, solange kein Modell gefittet wurde
+func DefaultModel() models.WinProbabilityModel {
+	return models.WinProbabilityModel{
+		ArmyLead:   0.8,
+		LostDiff:   0.5,
+		WorkerDiff: 0.4,
+		BaseDiff:   0.3,
+		SupplyDiff: 0.3,
+	}
+}
+
+// coefficients gibt die Koeffizienten eines Modells in Feature-Reihenfolge zurück
+func coefficients(m models.WinProbabilityModel) Features {
+	return Features{m.ArmyLead, m.LostDiff, m.WorkerDiff, m.BaseDiff, m.SupplyDiff}
+}
+
+// Predict berechnet die Siegwahrscheinlichkeit für einen Feature-Vektor
+func Predict(m models.WinProbabilityModel, f Features) float64 {
+	z := m.Intercept
+	coef := coefficients(m)
+	for i := range f {
+		z += coef[i] * f[i]
+	}
+	return 1 / (1 + math.Exp(-z))
+}
+
+// LoadModel liest ein gefittetes Modell aus einer JSON-Datei
+func LoadModel(path string) (models.WinProbabilityModel, error) {
+	var m models.WinProbabilityModel
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, fmt.Errorf("konnte Modell-Datei nicht lesen: %w", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("ungültige Modell-Datei: %w", err)
+	}
+	return m, nil
+}
+
+// SaveModel schreibt ein Modell als JSON-Datei
+func SaveModel(path string, m models.WinProbabilityModel) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// playerState ist der Stand eines Spielers aus PlayerStats und Hauptgebäuden
+type playerState struct {
+	army, lost, workers, supply int
+	bases                       map[int]bool // Unit-Tag -> fertig
+}
+
+// countBases zählt die fertigen Hauptgebäude
+func (ps *playerState) countBases() int {
+	count := 0
+	for _, done := range ps.bases {
+		if done {
+			count++
+		}
+	}
+	return count
+}
+
+// ExtractSamples erstellt bei jedem PlayerStats-Tick einen Feature-Vektor aus Sicht von playerID
+func ExtractSamples(events *parser.ParsedEvents, playerID, opponentID int, gameDuration float64) []Sample {
+	if events == nil {
+		return nil
+	}
+
+	states := map[int]*playerState{
+		playerID:   {bases: make(map[int]bool)},
+		opponentID: {bases: make(map[int]bool)},
+	}
+	var samples []Sample
+	seen := make(map[int]bool) // Spieler mit Stats im aktuellen Loop
+	currentLoop := -1
+
+	flush := func() {
+		if !seen[playerID] || !seen[opponentID] {
+			return
+		}
+		timeSeconds := parser.LoopsToRealSeconds(currentLoop)
+		if gameDuration > 0 && timeSeconds > gameDuration {
+			return
+		}
+		own, opp := states[playerID], states[opponentID]
+		raw := models.WinProbabilityPoint{
+			Time:       timeSeconds,
+			ArmyLead:   own.army - opp.army,
+			LostDiff:   opp.lost - own.lost,
+			WorkerDiff: own.workers - opp.workers,
+			BaseDiff:   own.countBases() - opp.countBases(),
+			SupplyDiff: own.supply - opp.supply,
+		}
+		samples = append(samples, Sample{
+			Time: timeSeconds,
+			Features: Features{
+				float64(raw.ArmyLead) / 1000,
+				float64(raw.LostDiff) / 1000,
+				float64(raw.WorkerDiff) / 10,
+				float64(raw.BaseDiff),
+				float64(raw.SupplyDiff) / 10,
+			},
+			Raw: raw,
+		})
+	}
+
+	for _, evt := range events.TrackerEvents {
+		switch evt.EventType {
+		case "PlayerStats":
+			state, ok := states[evt.PlayerID]
+			if !ok {
+				continue
+			}
+			// Beide Spieler erhalten ihre Stats im selben Loop
+			if evt.Loop != currentLoop {
+				flush()
+				currentLoop = evt.Loop
+				seen = make(map[int]bool)
+			}
+			seen[evt.PlayerID] = true
+			stats, _ := evt.Data["stats"].(map[string]interface{})
+			state.army = parser.GetInt(stats, "scoreValueMineralsUsedActiveForces") + parser.GetInt(stats, "scoreValueVespeneUsedActiveForces")
+			state.lost = 0
+			for _, key := range []string{
+				"scoreValueMineralsLostArmy", "scoreValueMineralsLostEconomy", "scoreValueMineralsLostTechnology",
+				"scoreValueVespeneLostArmy", "scoreValueVespeneLostEconomy", "scoreValueVespeneLostTechnology",
+			} {
+				state.lost += parser.GetInt(stats, key)
+			}
+			state.workers = parser.GetInt(stats, "scoreValueWorkersActiveCount")
+			state.supply = parser.GetInt(stats, "scoreValueFoodUsed") / 4096
+
+		case "UnitBorn", "UnitInit":
+			state, ok := states[parser.GetInt(evt.Data, "controlPlayerId")]
+			if ok && parser.IsTownHall(parser.GetString(evt.Data, "unitTypeName")) {
+				// Start-Hauptgebäude entstehen fertig (UnitBorn), Expansionen über UnitInit/UnitDone
+				state.bases[parser.GetUnitTag(evt.Data)] = evt.EventType == "UnitBorn"
+			}
+
+		case "UnitDone":
+			tag := parser.GetUnitTag(evt.Data)
+			for _, state := range states {
+				if _, ok := state.bases[tag]; ok {
+					state.bases[tag] = true
+				}
+			}
+
+		case "UnitDied":
+			tag := parser.GetUnitTag(evt.Data)
+			for _, state := range states {
+				delete(state.bases, tag)
+			}
+		}
+	}
+	flush()
+
+	return samples
+}
+
+// WinProbabilityAnalyzer schätzt den Verlauf der Siegwahrscheinlichkeit
+type WinProbabilityAnalyzer struct {
+	model  models.WinProbabilityModel
+	fitted bool
+}
+
+// NewWinProbabilityAnalyzer erstellt einen neuen WinProbabilityAnalyzer mit Standard-Koeffizienten
+func NewWinProbabilityAnalyzer() *WinProbabilityAnalyzer {
+	return &WinProbabilityAnalyzer{model: DefaultModel()}
+}
+
+// SetModel setzt ein offline gefittetes Modell
+func (wa *WinProbabilityAnalyzer) SetModel(m models.WinProbabilityModel) {
+	wa.model = m
+	wa.fitted = true
+}
+
+// Analyze berechnet den Wahrscheinlichkeitsverlauf und die Wendepunkte aus Sicht von playerID
+func (wa *WinProbabilityAnalyzer) Analyze(events *parser.ParsedEvents, playerID, opponentID int, gameDuration float64) *models.WinProbability {
+	samples := ExtractSamples(events, playerID, opponentID, gameDuration)
+	if len(samples) == 0 {
+		return nil
+	}
+
+	analysis := &models.WinProbability{
+		Timeline:      make([]models.WinProbabilityPoint, 0, len(samples)),
+		TurningPoints: []models.TurningPoint{},
+		ModelFitted:   wa.fitted,
+	}
+	for _, s := range samples {
+		point := s.Raw
+		point.Probability = Predict(wa.model, s.Features)
+		analysis.Timeline = append(analysis.Timeline, point)
+	}
+	analysis.FinalProbability = analysis.Timeline[len(analysis.Timeline)-1].Probability
+
+	// Entscheidung: Beginn der letzten Phase auf der Seite des Endstands
+	winning := analysis.FinalProbability >= 0.5
+	analysis.DecidedAt = analysis.Timeline[0].Time
+	for i := len(analysis.Timeline) - 1; i >= 0; i-- {
+		if (analysis.Timeline[i].Probability >= 0.5) != winning {
+			if i+1 < len(analysis.Timeline) {
+				analysis.DecidedAt = analysis.Timeline[i+1].Time
+			}
+			break
+		}
+	}
+
+	analysis.TurningPoints = wa.findTurningPoints(samples, analysis.Timeline)
+	return analysis
+}
+
+// findTurningPoints findet Zeitfenster mit großen Wahrscheinlichkeitssprüngen
+func (wa *WinProbabilityAnalyzer) findTurningPoints(samples []Sample, timeline []models.WinProbabilityPoint) []models.TurningPoint {
+	points := []models.TurningPoint{}
+	coef := coefficients(wa.model)
+
+	for i := 0; i < len(timeline); {
+		// Größte Änderung innerhalb des Fensters ab i
+		best := -1
+		for j := i + 1; j < len(timeline) && timeline[j].Time-timeline[i].Time <= turningWindow; j++ {
+			change := math.Abs(timeline[j].Probability - timeline[i].Probability)
+			if change >= turningThreshold && (best < 0 || change > math.Abs(timeline[best].Probability-timeline[i].Probability)) {
+				best = j
+			}
+		}
+		if best < 0 {
+			i++
+			continue
+		}
+
+		change := timeline[best].Probability - timeline[i].Probability
+		// Feature mit dem größten Beitrag in Richtung der Änderung
+		factor := featureNames[0]
+		var strongest float64
+		for k := range coef {
+			delta := coef[k] * (samples[best].Features[k] - samples[i].Features[k])
+			if delta*change > 0 && math.Abs(delta) > strongest {
+				strongest = math.Abs(delta)
+				factor = featureNames[k]
+			}
+		}
+
+		tp := models.TurningPoint{
+			Start:  timeline[i].Time,
+			End:    timeline[best].Time,
+			Before: timeline[i].Probability,
+			After:  timeline[best].Probability,
+			Change: change,
+			Factor: factor,
+		}
+		tp.Description = DescribeTurningPoint(tp, i18n.DefaultLanguage)
+		points = append(points, tp)
+		i = best
+	}
+
+	// Nur die stärksten Umschwünge, chronologisch
+	if len(points) > maxTurningPoints {
+		sort.Slice(points, func(a, b int) bool {
+			return math.Abs(points[a].Change) > math.Abs(points[b].Change)
+		})
+		points = points[:maxTurningPoints]
+		sort.Slice(points, func(a, b int) bool {
+			return points[a].Start < points[b].Start
+		})
+	}
+	return points
+}
+
+// DescribeTurningPoint beschreibt einen Wendepunkt in der angegebenen Sprache
+func DescribeTurningPoint(tp models.TurningPoint, lang string) string {
+	key := "winprob.turning_point.up"
+	if tp.Change < 0 {
+		key = "winprob.turning_point.down"
+	}
+	return i18n.T(lang, key, i18n.Params{
+		"before": fmt.Sprintf("%.0f", tp.Before*100),
+		"after":  fmt.Sprintf("%.0f", tp.After*100),
+		"factor": i18n.T(lang, "winprob.factor."+tp.Factor),
+	})
+}
+
+// LabeledSample ist ein Trainingsbeispiel; Won gibt an, ob der Spieler gewonnen hat
+type LabeledSample struct {
+	Features Features
+	Won      bool
+}
+
+// Fit passt die Koeffizienten per logistischer Regression (Gradientenabstieg mit L2) an
+func Fit(samples []LabeledSample, iterations int, learningRate float64) models.WinProbabilityModel {
+	const l2 = 0.001
+	var weights Features
+	var intercept float64
+	n := float64(len(samples))
+	if n == 0 {
+		return DefaultModel()
+	}
+
+	for iter := 0; iter < iterations; iter++ {
+		var grad Features
+		var gradIntercept float64
+		for _, s := range samples {
+			z := intercept
+			for k := range weights {
+				z += weights[k] * s.Features[k]
+			}
+			errTerm := 1 / (1 + math.Exp(-z))
+			if s.Won {
+				errTerm -= 1
+			}
+			gradIntercept += errTerm
+			for k := range grad {
+				grad[k] += errTerm * s.Features[k]
+			}
+		}
+		intercept -= learningRate * gradIntercept / n
+		for k := range weights {
+			weights[k] -= learningRate * (grad[k]/n + l2*weights[k])
+		}
+	}
+
+	m := models.WinProbabilityModel{
+		Intercept:  intercept,
+		ArmyLead:   weights[0],
+		LostDiff:   weights[1],
+		WorkerDiff: weights[2],
+		BaseDiff:   weights[3],
+		SupplyDiff: weights[4],
+		Samples:    len(samples),
+	}
+
+	correct := 0
+	for _, s := range samples {
+		if (Predict(m, s.Features) >= 0.5) == s.Won {
+			correct++
+		}
+	}
+	m.Accuracy = float64(correct) / n * 100
+	return m
+}
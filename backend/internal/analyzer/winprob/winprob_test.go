@@ -0,0 +1,80 @@
+package winprob
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// syntheticSamples erzeugt Beispiele, deren Ausgang einem bekannten logistischen Modell folgt
+func syntheticSamples(n int, intercept float64, coef Features) []LabeledSample {
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]LabeledSample, n)
+	for i := range samples {
+		var f Features
+		z := intercept
+		for k := range f {
+			f[k] = rng.Float64()*4 - 2
+			z += coef[k] * f[k]
+		}
+		samples[i] = LabeledSample{Features: f, Won: rng.Float64() < 1/(1+math.Exp(-z))}
+	}
+	return samples
+}
+
+// logLoss ist die mittlere Kreuzentropie eines Koeffizientensatzes
+func logLoss(intercept float64, coef Features, samples []LabeledSample) float64 {
+	loss := 0.0
+	for _, s := range samples {
+		z := intercept
+		for k := range coef {
+			z += coef[k] * s.Features[k]
+		}
+		p := 1 / (1 + math.Exp(-z))
+		if s.Won {
+			loss -= math.Log(p)
+		} else {
+			loss -= math.Log(1 - p)
+		}
+	}
+	return loss / float64(len(samples))
+}
+
+func TestFitConverges(t *testing.T) {
+	truth := Features{1.2, 0.6, -0.4, 0.3, 0}
+	samples := syntheticSamples(5000, 0.2, truth)
+
+	short := Fit(samples, 50, 0.1)
+	long := Fit(samples, 3000, 0.1)
+	longer := Fit(samples, 6000, 0.1)
+
+	if long.Samples != len(samples) {
+		t.Errorf("Samples = %d, want %d", long.Samples, len(samples))
+	}
+	if lossShort, lossLong := logLoss(short.Intercept, coefficients(short), samples), logLoss(long.Intercept, coefficients(long), samples); lossLong >= lossShort {
+		t.Errorf("log loss did not decrease: %.4f after 50 iterations, %.4f after 3000", lossShort, lossLong)
+	}
+
+	// Konvergiert: weitere Iterationen ändern die Koeffizienten kaum noch
+	got, more := coefficients(long), coefficients(longer)
+	for k := range got {
+		if math.Abs(got[k]-more[k]) > 0.02 {
+			t.Errorf("coefficient %d still moving: %.3f after 3000, %.3f after 6000 iterations", k, got[k], more[k])
+		}
+		if math.Abs(got[k]-truth[k]) > 0.15 {
+			t.Errorf("coefficient %d = %.3f, want %.3f ± 0.15", k, got[k], truth[k])
+		}
+	}
+	if math.Abs(long.Intercept-0.2) > 0.15 {
+		t.Errorf("Intercept = %.3f, want 0.2 ± 0.15", long.Intercept)
+	}
+	if long.Accuracy < 70 {
+		t.Errorf("Accuracy = %.1f%%, want >= 70%%", long.Accuracy)
+	}
+}
+
+func TestFitWithoutSamples(t *testing.T) {
+	if got := Fit(nil, 100, 0.1); got != DefaultModel() {
+		t.Errorf("Fit(nil) = %+v, want DefaultModel()", got)
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
+	"sc2-analytics/internal/i18n"
 	"sc2-analytics/internal/models"
 	"sc2-analytics/internal/repository"
 )
@@ -121,56 +122,56 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	// Rate Limiting
 	clientIP := getClientIP(r)
 	if !authRateLimiter.isAllowed(clientIP) {
-		respondError(w, http.StatusTooManyRequests, "Zu viele Anfragen. Bitte warte eine Minute.")
+		respondError(w, r, http.StatusTooManyRequests, "error.rate_limited")
 		return
 	}
 
 	var req models.RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Anfrage: "+err.Error())
+		respondError(w, r, http.StatusBadRequest, "error.invalid_request_detail", i18n.Params{"detail": err.Error()})
 		return
 	}
 
 	// Validierung
 	if req.Email == "" || req.Password == "" || req.SC2PlayerName == "" {
-		respondError(w, http.StatusBadRequest, "Email, Passwort und SC2 Spielername sind erforderlich")
+		respondError(w, r, http.StatusBadRequest, "error.register_fields_required")
 		return
 	}
 
 	if len(req.Password) < 8 {
-		respondError(w, http.StatusBadRequest, "Passwort muss mindestens 8 Zeichen haben")
+		respondError(w, r, http.StatusBadRequest, "error.password_too_short")
 		return
 	}
 
 	// Prüfe ob Email bereits existiert
 	existingUser, err := h.repo.GetUserByEmail(req.Email)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler: "+err.Error())
+		respondError(w, r, http.StatusInternalServerError, "error.database_detail", i18n.Params{"detail": err.Error()})
 		return
 	}
 	if existingUser != nil {
-		respondError(w, http.StatusConflict, "Diese Email ist bereits registriert")
+		respondError(w, r, http.StatusConflict, "error.email_taken")
 		return
 	}
 
 	// Hash das Passwort
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Hashen des Passworts")
+		respondError(w, r, http.StatusInternalServerError, "error.password_hash")
 		return
 	}
 
 	// Erstelle den Benutzer
 	user, err := h.repo.CreateUser(req.Email, string(hashedPassword), req.SC2PlayerName)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Erstellen des Benutzers: "+err.Error())
+		respondError(w, r, http.StatusInternalServerError, "error.create_user_detail", i18n.Params{"detail": err.Error()})
 		return
 	}
 
 	// Generiere JWT Token
 	token, err := generateToken(user)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Generieren des Tokens")
+		respondError(w, r, http.StatusInternalServerError, "error.token_generation")
 		return
 	}
 
@@ -185,35 +186,35 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	// Rate Limiting
 	clientIP := getClientIP(r)
 	if !authRateLimiter.isAllowed(clientIP) {
-		respondError(w, http.StatusTooManyRequests, "Zu viele Anfragen. Bitte warte eine Minute.")
+		respondError(w, r, http.StatusTooManyRequests, "error.rate_limited")
 		return
 	}
 
 	var req models.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Anfrage: "+err.Error())
+		respondError(w, r, http.StatusBadRequest, "error.invalid_request_detail", i18n.Params{"detail": err.Error()})
 		return
 	}
 
 	if req.Email == "" || req.Password == "" {
-		respondError(w, http.StatusBadRequest, "Email und Passwort sind erforderlich")
+		respondError(w, r, http.StatusBadRequest, "error.login_fields_required")
 		return
 	}
 
 	// Finde den Benutzer
 	user, err := h.repo.GetUserByEmail(req.Email)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Ungültige Anmeldedaten")
+		respondError(w, r, http.StatusUnauthorized, "error.invalid_credentials")
 		return
 	}
 
 	// Prüfe das Passwort
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		respondError(w, http.StatusUnauthorized, "Ungültige Anmeldedaten")
+		respondError(w, r, http.StatusUnauthorized, "error.invalid_credentials")
 		return
 	}
 
@@ -223,7 +224,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	// Generiere JWT Token
 	token, err := generateToken(user)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Generieren des Tokens")
+		respondError(w, r, http.StatusInternalServerError, "error.token_generation")
 		return
 	}
 
@@ -237,18 +238,53 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, user.ToPublic())
 }
 
+// UpdateMe ändert Einstellungen des aktuellen Benutzers (derzeit die Sprache)
+func (h *AuthHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+		return
+	}
+
+	var req models.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, "error.invalid_request_detail", i18n.Params{"detail": err.Error()})
+		return
+	}
+
+	if req.Language != nil {
+		// Leere Sprache = automatisch über Accept-Language
+		language := ""
+		if *req.Language != "" {
+			language = i18n.Normalize(*req.Language)
+			if language == "" {
+				respondError(w, r, http.StatusBadRequest, "error.unsupported_language",
+					i18n.Params{"languages": strings.Join(i18n.Languages(), ", ")})
+				return
+			}
+		}
+		if err := h.repo.UpdateUserLanguage(user.ID, language); err != nil {
+			respondError(w, r, http.StatusInternalServerError, "error.database")
+			return
+		}
+		user.Language = language
+	}
+
+	respondJSON(w, http.StatusOK, user.ToPublic())
+}
+
 // Logout invalidiert den Token (nur client-seitig)
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	// Bei JWT-basierter Authentifizierung kann der Server den Token nicht invalidieren
 	// Der Client muss den Token einfach löschen
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Erfolgreich abgemeldet"})
+	respondJSON(w, http.StatusOK, map[string]string{"message": i18n.T(requestLanguage(r), "message.logged_out")})
 }
 
 // AuthMiddleware prüft JWT Tokens
@@ -257,14 +293,14 @@ func AuthMiddleware(repo *repository.Repository) func(http.Handler) http.Handler
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				respondError(w, http.StatusUnauthorized, "Authorization Header fehlt")
+				respondError(w, r, http.StatusUnauthorized, "error.missing_auth_header")
 				return
 			}
 
 			// Bearer Token extrahieren
 			parts := strings.Split(authHeader, " ")
 			if len(parts) != 2 || parts[0] != "Bearer" {
-				respondError(w, http.StatusUnauthorized, "Ungültiges Token-Format")
+				respondError(w, r, http.StatusUnauthorized, "error.invalid_token_format")
 				return
 			}
 
@@ -280,14 +316,14 @@ func AuthMiddleware(repo *repository.Repository) func(http.Handler) http.Handler
 			})
 
 			if err != nil || !token.Valid {
-				respondError(w, http.StatusUnauthorized, "Ungültiger Token")
+				respondError(w, r, http.StatusUnauthorized, "error.invalid_token")
 				return
 			}
 
 			// Benutzer aus DB laden
 			user, err := repo.GetUserByID(claims.UserID)
 			if err != nil || user == nil {
-				respondError(w, http.StatusUnauthorized, "Benutzer nicht gefunden")
+				respondError(w, r, http.StatusUnauthorized, "error.user_not_found")
 				return
 			}
 
@@ -336,6 +372,24 @@ func OptionalAuthMiddleware(repo *repository.Repository) func(http.Handler) http
 	}
 }
 
+// RequireRole lässt nur Benutzer mit einer der Rollen durch; muss nach AuthMiddleware laufen
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := GetUserFromContext(r.Context())
+			if user == nil {
+				respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+				return
+			}
+			if !user.HasRole(roles...) {
+				respondError(w, r, http.StatusForbidden, "error.role_required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // GetUserFromContext extrahiert den Benutzer aus dem Context
 func GetUserFromContext(ctx context.Context) *models.User {
 	user, ok := ctx.Value(userContextKey).(*models.User)
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -9,11 +10,16 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"sc2-analytics/internal/analyzer"
+	"sc2-analytics/internal/analyzer/compare"
+	"sc2-analytics/internal/analyzer/gamestate"
+	"sc2-analytics/internal/analyzer/story"
 	"sc2-analytics/internal/analyzer/strategic"
+	"sc2-analytics/internal/i18n"
 	"sc2-analytics/internal/models"
 	"sc2-analytics/internal/parser"
 	"sc2-analytics/internal/repository"
@@ -25,6 +31,9 @@ type Handler struct {
 	parser     *parser.Parser
 	analyzer   *analyzer.Analyzer
 	uploadDir  string
+	matchups   *models.MatchupKnowledgeBase
+	// strategicVersion kennzeichnet gespeicherte strategische Analysen (siehe strategic.Version)
+	strategicVersion string
 }
 
 // NewHandler erstellt einen neuen Handler
@@ -34,9 +43,37 @@ func NewHandler(repo *repository.Repository, uploadDir string) *Handler {
 		parser:    parser.New(),
 		analyzer:  analyzer.New(),
 		uploadDir: uploadDir,
+		// ohne SetMatchupKnowledge gilt die eingebaute Wissensbasis
+		strategicVersion: strategic.Version(nil),
 	}
 }
 
+// SetAPMResolution setzt die Auflösung der APM-Timeline für neue Analysen
+func (h *Handler) SetAPMResolution(seconds float64) {
+	h.analyzer.SetAPMResolution(seconds)
+}
+
+// SetBenchmarkTargets setzt konfigurierte Benchmark-Zielwerte für neue Analysen
+func (h *Handler) SetBenchmarkTargets(targets []models.BenchmarkTarget) {
+	h.analyzer.SetBenchmarkTargets(targets)
+}
+
+// SetWinProbabilityModel setzt ein gefittetes Siegwahrscheinlichkeits-Modell für neue Analysen
+func (h *Handler) SetWinProbabilityModel(m models.WinProbabilityModel) {
+	h.analyzer.SetWinProbabilityModel(m)
+}
+
+// SetMatchupKnowledge setzt die Matchup-Wissensbasis für strategische Analysen
+func (h *Handler) SetMatchupKnowledge(kb *models.MatchupKnowledgeBase) {
+	h.matchups = kb
+	h.strategicVersion = strategic.Version(kb)
+}
+
+// SetSuggestionRules setzt konfigurierte Vorschlagsregeln für neue Analysen
+func (h *Handler) SetSuggestionRules(set models.SuggestionRuleSet) error {
+	return h.analyzer.SetSuggestionRules(set)
+}
+
 // Response-Hilfsfunktionen
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -44,8 +81,23 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
-func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, map[string]string{"error": message})
+// respondError antwortet mit einer übersetzten Fehlermeldung und ihrem Schlüssel als "code"
+func respondError(w http.ResponseWriter, r *http.Request, status int, key string, params ...i18n.Params) {
+	respondJSON(w, status, map[string]string{
+		"error": i18n.T(requestLanguage(r), key, params...),
+		"code":  strings.TrimPrefix(key, "error."),
+	})
+}
+
+// requestLanguage bestimmt die Sprache einer Anfrage: gespeicherte Einstellung des
+// Benutzers, sonst Accept-Language Header, sonst Standardsprache
+func requestLanguage(r *http.Request) string {
+	if user := GetUserFromContext(r.Context()); user != nil {
+		if lang := i18n.Normalize(user.Language); lang != "" {
+			return lang
+		}
+	}
+	return i18n.Negotiate(r.Header.Get("Accept-Language"))
 }
 
 // UploadReplay behandelt POST /api/v1/replays/upload
@@ -55,34 +107,34 @@ func (h *Handler) UploadReplay(w http.ResponseWriter, r *http.Request) {
 
 	file, header, err := r.FormFile("replay")
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Keine Replay-Datei gefunden")
+		respondError(w, r, http.StatusBadRequest, "error.no_replay_file")
 		return
 	}
 	defer file.Close()
 
 	// Prüfe Dateierweiterung
 	if filepath.Ext(header.Filename) != ".SC2Replay" {
-		respondError(w, http.StatusBadRequest, "Nur .SC2Replay Dateien erlaubt")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_file_type")
 		return
 	}
 
 	// Erstelle temporäre Datei
 	tempDir := filepath.Join(h.uploadDir, "temp")
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte Upload-Verzeichnis nicht erstellen")
+		respondError(w, r, http.StatusInternalServerError, "error.upload_dir")
 		return
 	}
 
 	tempFile, err := os.CreateTemp(tempDir, "replay-*.SC2Replay")
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte temporäre Datei nicht erstellen")
+		respondError(w, r, http.StatusInternalServerError, "error.temp_file")
 		return
 	}
 	defer os.Remove(tempFile.Name())
 
 	// Kopiere Upload in temporäre Datei
 	if _, err := io.Copy(tempFile, file); err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte Datei nicht speichern")
+		respondError(w, r, http.StatusInternalServerError, "error.save_file")
 		return
 	}
 	tempFile.Close()
@@ -90,19 +142,19 @@ func (h *Handler) UploadReplay(w http.ResponseWriter, r *http.Request) {
 	// Parse Replay
 	parsedReplay, err := h.parser.ParseFile(tempFile.Name())
 	if err != nil {
-		respondError(w, http.StatusBadRequest, fmt.Sprintf("Konnte Replay nicht parsen: %v", err))
+		respondError(w, r, http.StatusBadRequest, "error.replay_parse", i18n.Params{"detail": err.Error()})
 		return
 	}
 
 	// Prüfe ob Replay bereits existiert
 	existing, err := h.repo.GetReplayByHash(parsedReplay.Hash)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if existing != nil {
 		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"message":   "Replay bereits vorhanden",
+			"message":   i18n.T(requestLanguage(r), "message.replay_exists"),
 			"replay_id": existing.ID,
 			"replay":    existing,
 		})
@@ -112,12 +164,12 @@ func (h *Handler) UploadReplay(w http.ResponseWriter, r *http.Request) {
 	// Speichere Replay permanent
 	finalPath := filepath.Join(h.uploadDir, "replays", parsedReplay.Hash+".SC2Replay")
 	if err := os.MkdirAll(filepath.Dir(finalPath), 0755); err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte Replay-Verzeichnis nicht erstellen")
+		respondError(w, r, http.StatusInternalServerError, "error.replay_dir")
 		return
 	}
 
 	if err := copyFile(tempFile.Name(), finalPath); err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte Replay nicht speichern")
+		respondError(w, r, http.StatusInternalServerError, "error.save_replay")
 		return
 	}
 
@@ -132,7 +184,7 @@ func (h *Handler) UploadReplay(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.repo.CreateReplay(replay); err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte Replay nicht in DB speichern")
+		respondError(w, r, http.StatusInternalServerError, "error.save_replay_db")
 		return
 	}
 
@@ -158,6 +210,8 @@ func (h *Handler) UploadReplay(w http.ResponseWriter, r *http.Request) {
 			APM:              apm,
 			SpendingQuotient: sq,
 			IsHuman:          p.IsHuman,
+			RandomRace:       p.RandomRace,
+			LeftAt:           p.LeftAt,
 		}
 
 		if err := h.repo.CreateGamePlayer(&gp); err != nil {
@@ -179,6 +233,9 @@ func (h *Handler) UploadReplay(w http.ResponseWriter, r *http.Request) {
 				log.Printf("Konnte Analyse nicht speichern für Replay %d, Player %d: %v", replay.ID, analysis.PlayerID, err)
 			}
 		}
+		// Strategische Analysen aus Sicht jedes Spielers mit dem Replay speichern
+		h.storeStrategicAnalyses(replay)
+		h.storeGameSignatures(replay)
 	}
 
 	// Prüfe ob Benutzer authentifiziert ist
@@ -186,7 +243,7 @@ func (h *Handler) UploadReplay(w http.ResponseWriter, r *http.Request) {
 	needsPlayerSelection := user != nil
 
 	respondJSON(w, http.StatusCreated, map[string]interface{}{
-		"message":               "Replay erfolgreich hochgeladen",
+		"message":               i18n.T(requestLanguage(r), "message.replay_uploaded"),
 		"replay_id":             replay.ID,
 		"replay":                replay,
 		"needs_player_selection": needsPlayerSelection,
@@ -198,42 +255,42 @@ func (h *Handler) UploadReplay(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) DeleteReplay(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Replay-ID")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
 		return
 	}
 
 	// Prüfe ob das Replay dem Benutzer gehört
 	owns, err := h.repo.UserOwnsReplay(user.ID, id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if !owns {
-		respondError(w, http.StatusForbidden, "Kein Zugriff auf dieses Replay")
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
 		return
 	}
 
 	// Lade Replay um Hash für Dateilöschung zu bekommen
 	replay, err := h.repo.GetReplayByID(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if replay == nil {
-		respondError(w, http.StatusNotFound, "Replay nicht gefunden")
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
 		return
 	}
 
 	// Lösche aus Datenbank (CASCADE löscht auch game_players, analyses, user_replays)
 	if err := h.repo.DeleteReplay(id); err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Löschen: "+err.Error())
+		respondError(w, r, http.StatusInternalServerError, "error.delete_detail", i18n.Params{"detail": err.Error()})
 		return
 	}
 
@@ -241,7 +298,7 @@ func (h *Handler) DeleteReplay(w http.ResponseWriter, r *http.Request) {
 	filePath := filepath.Join(h.uploadDir, "replays", replay.Hash+".SC2Replay")
 	os.Remove(filePath) // Fehler ignorieren, Datei könnte bereits gelöscht sein
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Replay gelöscht"})
+	respondJSON(w, http.StatusOK, map[string]string{"message": i18n.T(requestLanguage(r), "message.replay_deleted")})
 }
 
 // ClaimReplay behandelt POST /api/v1/replays/:id/claim
@@ -249,7 +306,7 @@ func (h *Handler) DeleteReplay(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ClaimReplay(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
@@ -257,7 +314,7 @@ func (h *Handler) ClaimReplay(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	replayID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Replay-ID")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
 		return
 	}
 
@@ -266,23 +323,23 @@ func (h *Handler) ClaimReplay(w http.ResponseWriter, r *http.Request) {
 		PlayerID int64 `json:"player_id"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Anfrage")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_request")
 		return
 	}
 
 	if req.PlayerID == 0 {
-		respondError(w, http.StatusBadRequest, "player_id ist erforderlich")
+		respondError(w, r, http.StatusBadRequest, "error.player_id_required")
 		return
 	}
 
 	// Lade Replay
 	replay, err := h.repo.GetReplayByID(replayID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if replay == nil {
-		respondError(w, http.StatusNotFound, "Replay nicht gefunden")
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
 		return
 	}
 
@@ -295,18 +352,19 @@ func (h *Handler) ClaimReplay(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	if selectedPlayer == nil {
-		respondError(w, http.StatusBadRequest, "Spieler nicht in diesem Replay")
+		respondError(w, r, http.StatusBadRequest, "error.player_not_in_replay")
 		return
 	}
 
 	// Verknüpfe Replay mit Benutzer (inkl. player_id)
 	if err := h.repo.LinkReplayToUser(user.ID, replayID, req.PlayerID); err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Verknüpfen")
+		respondError(w, r, http.StatusInternalServerError, "error.link_replay")
 		return
 	}
 
-	// Hole Supply Block Prozent aus der Analyse
+	// Hole Supply Block Prozent und Zusatz-Metriken aus der Analyse
 	var supplyBlockPct float64
+	var metrics models.MetricValues
 	analysis, err := h.repo.GetAnalysis(replayID, req.PlayerID)
 	if err == nil && analysis != nil {
 		var data models.AnalysisData
@@ -314,17 +372,18 @@ func (h *Handler) ClaimReplay(w http.ResponseWriter, r *http.Request) {
 			if data.SupplyAnalysis != nil {
 				supplyBlockPct = data.SupplyAnalysis.BlockPercentage
 			}
+			metrics = data.GoalMetrics()
 		}
 	}
 
 	// Aktualisiere den täglichen Fortschritt
-	if err := h.repo.UpdateProgressFromReplay(user.ID, replay, selectedPlayer, supplyBlockPct); err != nil {
+	if err := h.repo.UpdateProgressFromReplay(user.ID, replay, selectedPlayer, supplyBlockPct, metrics); err != nil {
 		// Nicht kritisch, logge nur
 		fmt.Printf("Fehler beim Aktualisieren des Fortschritts: %v\n", err)
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"message":    "Replay erfolgreich zugeordnet",
+		"message":    i18n.T(requestLanguage(r), "message.replay_claimed"),
 		"replay_id":  replayID,
 		"player_id":  req.PlayerID,
 		"player_name": selectedPlayer.Name,
@@ -336,7 +395,7 @@ func (h *Handler) ClaimReplay(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ListReplays(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
@@ -359,7 +418,7 @@ func (h *Handler) ListReplays(w http.ResponseWriter, r *http.Request) {
 	// Nur Replays des Benutzers laden
 	replays, err := h.repo.GetUserReplays(user.ID, limit, offset)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte Replays nicht laden")
+		respondError(w, r, http.StatusInternalServerError, "error.load_replays")
 		return
 	}
 
@@ -381,35 +440,35 @@ func (h *Handler) ListReplays(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetReplay(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Replay-ID")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
 		return
 	}
 
 	// Prüfe ob das Replay dem Benutzer gehört
 	owns, err := h.repo.UserOwnsReplay(user.ID, id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if !owns {
-		respondError(w, http.StatusForbidden, "Kein Zugriff auf dieses Replay")
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
 		return
 	}
 
 	replay, err := h.repo.GetReplayByID(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if replay == nil {
-		respondError(w, http.StatusNotFound, "Replay nicht gefunden")
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
 		return
 	}
 
@@ -421,50 +480,52 @@ func (h *Handler) GetReplay(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetReplayAnalysis(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Replay-ID")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
 		return
 	}
 
 	// Prüfe ob das Replay dem Benutzer gehört
 	owns, err := h.repo.UserOwnsReplay(user.ID, id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if !owns {
-		respondError(w, http.StatusForbidden, "Kein Zugriff auf dieses Replay")
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
 		return
 	}
 
 	replay, err := h.repo.GetReplayByID(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if replay == nil {
-		respondError(w, http.StatusNotFound, "Replay nicht gefunden")
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
 		return
 	}
 
 	// Lade Analysen
 	analyses, err := h.repo.GetAnalysesByReplayID(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte Analysen nicht laden")
+		respondError(w, r, http.StatusInternalServerError, "error.load_analyses")
 		return
 	}
 
 	// Parse JSON-Daten
+	lang := requestLanguage(r)
 	playerAnalyses := make(map[int64]interface{})
 	for _, a := range analyses {
 		var data models.AnalysisData
 		if err := json.Unmarshal(a.Data, &data); err == nil {
+			h.analyzer.Localize(&data, lang)
 			playerAnalyses[a.PlayerID] = data
 		}
 	}
@@ -475,18 +536,36 @@ func (h *Handler) GetReplayAnalysis(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ListLanguages behandelt GET /api/v1/languages
+// Gibt die verfügbaren Sprachen und die für diese Anfrage gewählte Sprache zurück
+func (h *Handler) ListLanguages(w http.ResponseWriter, r *http.Request) {
+	var languages []models.Language
+	for _, code := range i18n.Languages() {
+		languages = append(languages, models.Language{
+			Code: code,
+			Name: i18n.T(code, "language.name"),
+		})
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"languages": languages,
+		"default":   i18n.DefaultLanguage,
+		"current":   requestLanguage(r),
+	})
+}
+
 // GetTrends behandelt GET /api/v1/stats/trends
 func (h *Handler) GetTrends(w http.ResponseWriter, r *http.Request) {
 	// Player-ID aus Query
 	playerIDStr := r.URL.Query().Get("player_id")
 	if playerIDStr == "" {
-		respondError(w, http.StatusBadRequest, "player_id Parameter erforderlich")
+		respondError(w, r, http.StatusBadRequest, "error.player_id_param_required")
 		return
 	}
 
 	playerID, err := strconv.ParseInt(playerIDStr, 10, 64)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige player_id")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_player_id")
 		return
 	}
 
@@ -501,7 +580,7 @@ func (h *Handler) GetTrends(w http.ResponseWriter, r *http.Request) {
 	// Lade alle Metriken
 	metrics, err := h.repo.GetAllPlayerMetrics(playerID, limit)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte Trends nicht laden")
+		respondError(w, r, http.StatusInternalServerError, "error.load_trends")
 		return
 	}
 
@@ -527,6 +606,27 @@ func calculateTrends(metrics []map[string]interface{}) map[string]models.TrendDa
 	trends["apm"] = calculateSingleTrend(metrics, "apm")
 	trends["spending_quotient"] = calculateSingleTrend(metrics, "spending_quotient")
 
+	// Phasen-Trends, z.B. "sq_mid"
+	for _, metric := range []string{"apm", "sq", "supply_block"} {
+		for _, phase := range []string{models.PhaseEarly, models.PhaseMid, models.PhaseLate} {
+			key := models.PhaseMetricName(metric, phase)
+			trend := calculateSingleTrend(metrics, key)
+			if trend.Trend == "insufficient_data" {
+				continue
+			}
+			// Bei Supply Blocks ist ein sinkender Wert eine Verbesserung
+			if metric == "supply_block" {
+				switch trend.Trend {
+				case "improving":
+					trend.Trend = "declining"
+				case "declining":
+					trend.Trend = "improving"
+				}
+			}
+			trends[key] = trend
+		}
+	}
+
 	return trends
 }
 
@@ -574,87 +674,108 @@ func calculateSingleTrend(metrics []map[string]interface{}, key string) models.T
 func (h *Handler) GetStrategicAnalysis(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Replay-ID")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
 		return
 	}
 
 	// Prüfe ob das Replay dem Benutzer gehört
 	owns, err := h.repo.UserOwnsReplay(user.ID, id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if !owns {
-		respondError(w, http.StatusForbidden, "Kein Zugriff auf dieses Replay")
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
 		return
 	}
 
 	// Lade Replay mit Spielern
 	replay, err := h.repo.GetReplayByID(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Datenbankfehler")
+		respondError(w, r, http.StatusInternalServerError, "error.database")
 		return
 	}
 	if replay == nil {
-		respondError(w, http.StatusNotFound, "Replay nicht gefunden")
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
 		return
 	}
 
 	// Lade Analysen
 	analyses, err := h.repo.GetAnalysesByReplayID(id)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Konnte Analysen nicht laden")
+		respondError(w, r, http.StatusInternalServerError, "error.load_analyses")
 		return
 	}
 
-	// Parse JSON-Daten und finde Winner/Loser
-	var winnerAnalysisData, loserAnalysisData models.AnalysisData
-	var winnerAnalysis, loserAnalysis *models.AnalysisData
-	var winnerPlayer, loserPlayer *models.GamePlayer
-
-	for i := range replay.GamePlayers {
-		gp := &replay.GamePlayers[i]
-		for _, a := range analyses {
-			if a.PlayerID == gp.PlayerID {
-				var data models.AnalysisData
-				if err := json.Unmarshal(a.Data, &data); err == nil {
-					if gp.Result == "Win" {
-						winnerAnalysisData = data
-						winnerAnalysis = &winnerAnalysisData
-						winnerPlayer = gp
-					} else if gp.Result == "Loss" {
-						loserAnalysisData = data
-						loserAnalysis = &loserAnalysisData
-						loserPlayer = gp
-					}
-				}
-				break
-			}
+	// Perspektive: ?player_id=..., sonst der Spieler, mit dem der Benutzer das Replay verknüpft hat
+	perspectiveID, err := h.repo.GetUserReplayPlayerID(user.ID, id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	explicit := false
+	if pid := r.URL.Query().Get("player_id"); pid != "" {
+		perspectiveID, err = strconv.ParseInt(pid, 10, 64)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, "error.invalid_player_id")
+			return
 		}
+		explicit = true
 	}
 
-	if winnerPlayer == nil || loserPlayer == nil {
-		respondError(w, http.StatusBadRequest, "Kein eindeutiger Winner/Loser gefunden")
+	analysisData := parseAnalysisData(analyses)
+	player, opponent := strategicPlayers(replay.GamePlayers, analysisData, perspectiveID)
+	if explicit && (player == nil || player.PlayerID != perspectiveID) {
+		respondError(w, r, http.StatusBadRequest, "error.player_not_in_replay")
+		return
+	}
+	if player == nil || opponent == nil {
+		respondError(w, r, http.StatusBadRequest, "error.no_opponent")
 		return
 	}
 
-	// Erstelle strategische Analyse
-	sa := strategic.NewStrategicAnalyzer()
-	strategicAnalysis := sa.Analyze(
-		loserPlayer.Name, winnerPlayer.Name,
-		loserPlayer.Race, winnerPlayer.Race,
-		loserAnalysis, winnerAnalysis,
-	)
+	// Gespeicherte Analyse aus Sicht des Spielers in der angefragten Sprache laden,
+	// fehlende erstellen und speichern. Die Standardsprache liegt in strategic_analyses
+	// (Grundlage der wiederkehrenden Probleme), andere Sprachen in den Übersetzungen.
+	lang := requestLanguage(r)
+	var strategicAnalysis *models.StrategicAnalysis
+	if lang == i18n.DefaultLanguage {
+		strategicAnalysis, err = h.repo.GetStrategicAnalysis(id, player.PlayerID, h.strategicVersion)
+	} else {
+		strategicAnalysis, err = h.repo.GetStrategicAnalysisTranslation(id, player.PlayerID, lang, h.strategicVersion)
+	}
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if strategicAnalysis == nil {
+		strategicAnalysis = h.strategicAnalysis(replay, player, opponent, analysisData, lang)
+		if strategicAnalysis != nil && currentAnalyses(analysisData, player, opponent) {
+			if lang == i18n.DefaultLanguage {
+				err = h.repo.SaveStrategicAnalysis(id, player.PlayerID, h.strategicVersion, strategicAnalysis)
+			} else {
+				err = h.repo.SaveStrategicAnalysisTranslation(id, player.PlayerID, lang, h.strategicVersion, strategicAnalysis)
+			}
+			if err != nil {
+				log.Printf("Konnte strategische Analyse nicht speichern für Replay %d, Player %d (%s): %v", id, player.PlayerID, lang, err)
+			}
+		}
+	} else if strategicAnalysis.MatchupTips != nil {
+		// Referenz-Replays können seit dem Speichern hinzugekommen oder entfernt worden sein
+		sa := h.strategicAnalyzer(replay, lang)
+		sa.SetReference(h.referenceComparison(replay, player, opponent, analysisData))
+		strategicAnalysis.MatchupTips.Reference = sa.ReferenceTips()
+	}
 
 	if strategicAnalysis == nil {
-		respondError(w, http.StatusInternalServerError, "Konnte strategische Analyse nicht erstellen")
+		respondError(w, r, http.StatusInternalServerError, "error.strategic_analysis")
 		return
 	}
 
@@ -664,6 +785,514 @@ func (h *Handler) GetStrategicAnalysis(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// strategicAnalysis erstellt die strategische Analyse aus Sicht von player in der angegebenen Sprache
+// Die sprachabhängigen Texte in analysisData (z.B. Wendepunkte) werden dafür in lang übersetzt
+func (h *Handler) strategicAnalysis(replay *models.Replay, player, opponent *models.GamePlayer, analysisData map[int64]*models.AnalysisData, lang string) *models.StrategicAnalysis {
+	h.analyzer.Localize(analysisData[player.PlayerID], lang)
+	h.analyzer.Localize(analysisData[opponent.PlayerID], lang)
+	sa := h.strategicAnalyzer(replay, lang)
+	sa.SetReference(h.referenceComparison(replay, player, opponent, analysisData))
+	return sa.Analyze(player, opponent, analysisData[player.PlayerID], analysisData[opponent.PlayerID])
+}
+
+// strategicAnalyzer erstellt einen StrategicAnalyzer mit der Wissensbasis und der Version des Replays
+func (h *Handler) strategicAnalyzer(replay *models.Replay, lang string) *strategic.StrategicAnalyzer {
+	sa := strategic.NewStrategicAnalyzer(lang)
+	sa.SetKnowledgeBase(h.matchups)
+	sa.SetGameVersion(replay.GameVersion)
+	return sa
+}
+
+// storeStrategicAnalyses erstellt und speichert die strategischen Analysen eines Replays
+// in der Standardsprache, ohne playerIDs für alle analysierten Spieler; gibt die Anzahl gespeicherter Analysen zurück
+func (h *Handler) storeStrategicAnalyses(replay *models.Replay, playerIDs ...int64) int {
+	analyses, err := h.repo.GetAnalysesByReplayID(replay.ID)
+	if err != nil {
+		log.Printf("Konnte Analysen für Replay %d nicht laden: %v", replay.ID, err)
+		return 0
+	}
+	analysisData := parseAnalysisData(analyses)
+	if len(playerIDs) == 0 {
+		for playerID := range analysisData {
+			playerIDs = append(playerIDs, playerID)
+		}
+	}
+
+	stored := 0
+	for _, playerID := range playerIDs {
+		player, opponent := strategicPlayers(replay.GamePlayers, analysisData, playerID)
+		if player == nil || opponent == nil || player.PlayerID != playerID {
+			continue
+		}
+		if !currentAnalyses(analysisData, player, opponent) {
+			if err := h.repo.DeleteStrategicAnalysis(replay.ID, playerID); err != nil {
+				log.Printf("Konnte veraltete strategische Analyse nicht löschen für Replay %d, Player %d: %v", replay.ID, playerID, err)
+			}
+			continue
+		}
+		sa := h.strategicAnalysis(replay, player, opponent, analysisData, i18n.DefaultLanguage)
+		if sa == nil {
+			continue
+		}
+		if err := h.repo.SaveStrategicAnalysis(replay.ID, playerID, h.strategicVersion, sa); err != nil {
+			log.Printf("Konnte strategische Analyse nicht speichern für Replay %d, Player %d: %v", replay.ID, playerID, err)
+			continue
+		}
+		stored++
+	}
+	return stored
+}
+
+// BackfillStrategicAnalyses speichert fehlende und veraltete strategische Analysen für bereits
+// zugeordnete Replays, damit ältere Spiele mit der aktuellen Analyse und Wissensbasis in die
+// wiederkehrenden Schwächen einfließen
+func (h *Handler) BackfillStrategicAnalyses() (int, error) {
+	missing, err := h.repo.GetClaimedWithoutStrategicAnalysis(h.strategicVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for replayID, playerIDs := range missing {
+		replay, err := h.repo.GetReplayByID(replayID)
+		if err != nil || replay == nil {
+			continue
+		}
+		stored += h.storeStrategicAnalyses(replay, playerIDs...)
+	}
+	return stored, nil
+}
+
+// ReanalyzeOutdated analysiert Replays, deren gespeicherte Analysen älter als
+// models.AnalysisVersion sind, aus der Replay-Datei neu. Metriken der Spieler,
+// strategische Analysen, Ähnlichkeitsindex und der Fortschritt der zugeordneten
+// Benutzer werden neu berechnet, damit keine Werte verschiedener Versionen gemischt werden.
+// Gibt die Anzahl neu analysierter Replays zurück.
+func (h *Handler) ReanalyzeOutdated() (int, error) {
+	replayIDs, err := h.repo.GetOutdatedAnalysisReplays(models.AnalysisVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	type userDay struct {
+		userID int64
+		date   string
+	}
+	rebuild := make(map[userDay]time.Time)
+	reanalyzed := 0
+	for _, replayID := range replayIDs {
+		replay, err := h.repo.GetReplayByID(replayID)
+		if err != nil || replay == nil {
+			continue
+		}
+		filePath := filepath.Join(h.uploadDir, "replays", replay.Hash+".SC2Replay")
+		parsedReplay, err := h.parser.ParseFile(filePath)
+		if err != nil {
+			log.Printf("Konnte Replay %d nicht neu analysieren: %v", replayID, err)
+			continue
+		}
+
+		for i := range replay.GamePlayers {
+			gp := &replay.GamePlayers[i]
+			gp.APM, gp.SpendingQuotient = h.analyzer.GetPlayerMetrics(parsedReplay, gp.PlayerSlot, gp.Race)
+			if err := h.repo.UpdateGamePlayerMetrics(replayID, gp.PlayerID, gp.APM, gp.SpendingQuotient); err != nil {
+				log.Printf("Konnte Metriken nicht aktualisieren für Replay %d, Player %d: %v", replayID, gp.PlayerID, err)
+			}
+		}
+
+		analyses, err := h.analyzer.AnalyzeAndStore(parsedReplay, replayID, replay.GamePlayers)
+		if err != nil {
+			log.Printf("Analyse-Fehler für Replay %d: %v", replayID, err)
+			continue
+		}
+		for _, analysis := range analyses {
+			if err := h.repo.SaveAnalysis(analysis); err != nil {
+				log.Printf("Konnte Analyse nicht speichern für Replay %d, Player %d: %v", replayID, analysis.PlayerID, err)
+			}
+		}
+		h.storeStrategicAnalyses(replay)
+		h.storeGameSignatures(replay)
+
+		claims, err := h.repo.GetReplayClaims(replayID)
+		if err != nil {
+			log.Printf("Konnte Zuordnungen für Replay %d nicht laden: %v", replayID, err)
+		}
+		for userID := range claims {
+			rebuild[userDay{userID, replay.PlayedAt.Format("2006-01-02")}] = replay.PlayedAt
+		}
+		reanalyzed++
+	}
+
+	// Tagesfortschritt (und damit Ziele und Wochenberichte) der betroffenen Tage neu aufbauen
+	for day, date := range rebuild {
+		if err := h.repo.RebuildDailyProgress(day.userID, date); err != nil {
+			log.Printf("Konnte Fortschritt von Benutzer %d am %s nicht neu berechnen: %v", day.userID, day.date, err)
+		}
+	}
+	return reanalyzed, nil
+}
+
+// currentAnalyses prüft ob die Analysen beider Spieler der aktuellen Analyse-Version entsprechen
+// Strategische Analysen aus älteren Daten (Replay-Datei fehlt) werden nicht gespeichert, da sie
+// z.B. jeden SQ als zu niedrig werten und so die wiederkehrenden Probleme verfälschen würden
+func currentAnalyses(analysisData map[int64]*models.AnalysisData, player, opponent *models.GamePlayer) bool {
+	return analysisData[player.PlayerID].Version >= models.AnalysisVersion &&
+		analysisData[opponent.PlayerID].Version >= models.AnalysisVersion
+}
+
+// parseAnalysisData parst die JSON-Daten der Analysen, nach Spieler-ID
+func parseAnalysisData(analyses []models.Analysis) map[int64]*models.AnalysisData {
+	analysisData := make(map[int64]*models.AnalysisData)
+	for _, a := range analyses {
+		var data models.AnalysisData
+		if err := json.Unmarshal(a.Data, &data); err == nil {
+			analysisData[a.PlayerID] = &data
+		}
+	}
+	return analysisData
+}
+
+// strategicPlayers wählt den analysierten Spieler und seinen Gegner aus den Spielern mit Analyse
+// Ohne passende Perspektive wird der Verlierer analysiert, sonst der erste menschliche Spieler
+func strategicPlayers(players []models.GamePlayer, analyses map[int64]*models.AnalysisData, perspectiveID int64) (*models.GamePlayer, *models.GamePlayer) {
+	var candidates []*models.GamePlayer
+	for i := range players {
+		if analyses[players[i].PlayerID] != nil {
+			candidates = append(candidates, &players[i])
+		}
+	}
+
+	var player *models.GamePlayer
+	for _, pick := range []func(gp *models.GamePlayer) bool{
+		func(gp *models.GamePlayer) bool { return gp.PlayerID == perspectiveID },
+		func(gp *models.GamePlayer) bool { return gp.Result == "Loss" },
+		func(gp *models.GamePlayer) bool { return gp.IsHuman },
+	} {
+		for _, gp := range candidates {
+			if player == nil && pick(gp) {
+				player = gp
+			}
+		}
+	}
+	if player == nil {
+		return nil, nil
+	}
+
+	// Gegner: bevorzugt ein Spieler mit anderem Ergebnis (Teamspiele), sonst der erste andere
+	var opponent *models.GamePlayer
+	for _, gp := range candidates {
+		if gp == player {
+			continue
+		}
+		if opponent == nil || (opponent.Result == player.Result && gp.Result != player.Result) {
+			opponent = gp
+		}
+	}
+	return player, opponent
+}
+
+// GetReplayState behandelt GET /api/v1/replays/:id/state?t=mm:ss
+// Rekonstruiert den Spielstand beider Spieler zum angegebenen Zeitpunkt aus der gespeicherten Replay-Datei
+func (h *Handler) GetReplayState(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
+		return
+	}
+
+	t, err := gamestate.ParseTime(r.URL.Query().Get("t"))
+	if err != nil {
+		detail := err.Error()
+		var timeErr *gamestate.TimeError
+		if errors.As(err, &timeErr) {
+			detail = i18n.T(requestLanguage(r), timeErr.Key, timeErr.Params())
+		}
+		respondError(w, r, http.StatusBadRequest, "error.invalid_time", i18n.Params{"detail": detail})
+		return
+	}
+
+	// Prüfe ob das Replay dem Benutzer gehört
+	owns, err := h.repo.UserOwnsReplay(user.ID, id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if !owns {
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
+		return
+	}
+
+	replay, err := h.repo.GetReplayByID(id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if replay == nil {
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
+		return
+	}
+
+	// Parse gespeicherte Replay-Datei
+	filePath := filepath.Join(h.uploadDir, "replays", replay.Hash+".SC2Replay")
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		respondError(w, r, http.StatusNotFound, "error.replay_file_not_found")
+		return
+	}
+	parsedReplay, err := h.parser.ParseFile(filePath)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.parse_detail", i18n.Params{"detail": err.Error()})
+		return
+	}
+
+	if t > float64(parsedReplay.Duration) {
+		respondError(w, r, http.StatusBadRequest, "error.time_after_end", i18n.Params{"end": parser.FormatTime(float64(parsedReplay.Duration))})
+		return
+	}
+
+	state := gamestate.Reconstruct(parsedReplay, parser.RealSecondsToLoops(t))
+	respondJSON(w, http.StatusOK, state)
+}
+
+// GetReplayTimeline behandelt GET /api/v1/replays/:id/timeline
+// Gibt den chronologischen Spielverlauf zurück; ?min_importance=2 bzw. 3 lässt Details weg
+func (h *Handler) GetReplayTimeline(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
+		return
+	}
+
+	minImportance := story.ImportanceDetail
+	if v := r.URL.Query().Get("min_importance"); v != "" {
+		minImportance, err = strconv.Atoi(v)
+		if err != nil || minImportance < story.ImportanceDetail || minImportance > story.ImportanceKey {
+			respondError(w, r, http.StatusBadRequest, "error.invalid_importance")
+			return
+		}
+	}
+
+	// Prüfe ob das Replay dem Benutzer gehört
+	owns, err := h.repo.UserOwnsReplay(user.ID, id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if !owns {
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
+		return
+	}
+
+	replay, err := h.repo.GetReplayByID(id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if replay == nil {
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
+		return
+	}
+
+	// Parse gespeicherte Replay-Datei (Expansionen, Tech, Upgrades, Kämpfe, Chat)
+	filePath := filepath.Join(h.uploadDir, "replays", replay.Hash+".SC2Replay")
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		respondError(w, r, http.StatusNotFound, "error.replay_file_not_found")
+		return
+	}
+	parsedReplay, err := h.parser.ParseFile(filePath)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.parse_detail", i18n.Params{"detail": err.Error()})
+		return
+	}
+
+	// Gespeicherte Analysen liefern Build Order, Supply Blocks und Harass, nach Spieler-Slot
+	analyses, err := h.repo.GetAnalysesByReplayID(id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.load_analyses")
+		return
+	}
+	analysisData := parseAnalysisData(analyses)
+	slotAnalyses := make(map[int]*models.AnalysisData)
+	for _, gp := range replay.GamePlayers {
+		if data := analysisData[gp.PlayerID]; data != nil {
+			slotAnalyses[gp.PlayerSlot] = data
+		}
+	}
+
+	gameStory := story.NewStoryAnalyzer(requestLanguage(r)).Analyze(parsedReplay, slotAnalyses)
+	if minImportance > story.ImportanceDetail {
+		entries := []models.StoryEntry{}
+		for _, e := range gameStory.Entries {
+			if e.Importance >= minImportance {
+				entries = append(entries, e)
+			}
+		}
+		gameStory.Entries = entries
+	}
+
+	respondJSON(w, http.StatusOK, gameStory)
+}
+
+// GetReplayComparison behandelt GET /api/v1/compare?a=&b=&player_a=&player_b=
+// Vergleicht Spiel b mit Spiel a; ohne player_a/player_b gilt der jeweils verknüpfte Spieler
+// Spiel b darf auch ein Referenz-Replay eines anderen Benutzers sein (nur der Referenz-Spieler)
+func (h *Handler) GetReplayComparison(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get("a") == "" || query.Get("b") == "" {
+		respondError(w, r, http.StatusBadRequest, "error.compare_ids_required")
+		return
+	}
+
+	sideA, dataA, ok := h.comparisonSide(w, r, user.ID, query.Get("a"), query.Get("player_a"), "player_a", false)
+	if !ok {
+		return
+	}
+	sideB, dataB, ok := h.comparisonSide(w, r, user.ID, query.Get("b"), query.Get("player_b"), "player_b", true)
+	if !ok {
+		return
+	}
+
+	comparison := compare.NewComparisonAnalyzer().Analyze(sideA, sideB, dataA, dataB)
+	respondJSON(w, http.StatusOK, comparison)
+}
+
+// comparisonSide lädt Replay, Spieler und Analyse einer Vergleichsseite
+// Mit allowReference sind auch fremde Replays erlaubt, sofern der Spieler als Referenz markiert ist
+// Bei Fehlern ist die Antwort bereits geschrieben und ok false
+func (h *Handler) comparisonSide(w http.ResponseWriter, r *http.Request, userID int64, replayParam, playerParam, playerName string, allowReference bool) (models.ComparisonSide, *models.AnalysisData, bool) {
+	var side models.ComparisonSide
+
+	id, err := strconv.ParseInt(replayParam, 10, 64)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
+		return side, nil, false
+	}
+
+	// Prüfe ob das Replay dem Benutzer gehört
+	owns, err := h.repo.UserOwnsReplay(userID, id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return side, nil, false
+	}
+	var referencePlayers []int64
+	if !owns && allowReference {
+		referencePlayers, err = h.repo.GetReferencePlayerIDs(id)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, "error.database")
+			return side, nil, false
+		}
+	}
+	if !owns && len(referencePlayers) == 0 {
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
+		return side, nil, false
+	}
+
+	replay, err := h.repo.GetReplayByID(id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return side, nil, false
+	}
+	if replay == nil {
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
+		return side, nil, false
+	}
+
+	// Spieler: Parameter, sonst der Spieler, mit dem der Benutzer das Replay verknüpft hat
+	// bzw. bei fremden Referenz-Replays der Referenz-Spieler
+	var playerID int64
+	if playerParam != "" {
+		playerID, err = strconv.ParseInt(playerParam, 10, 64)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, "error.invalid_player_id")
+			return side, nil, false
+		}
+		if !owns && !containsID(referencePlayers, playerID) {
+			respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
+			return side, nil, false
+		}
+	} else if !owns {
+		playerID = referencePlayers[0]
+	} else {
+		playerID, err = h.repo.GetUserReplayPlayerID(userID, id)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, "error.database")
+			return side, nil, false
+		}
+		if playerID == 0 {
+			respondError(w, r, http.StatusBadRequest, "error.compare_player_required", i18n.Params{"param": playerName})
+			return side, nil, false
+		}
+	}
+
+	var player *models.GamePlayer
+	for i := range replay.GamePlayers {
+		if replay.GamePlayers[i].PlayerID == playerID {
+			player = &replay.GamePlayers[i]
+			break
+		}
+	}
+	if player == nil {
+		respondError(w, r, http.StatusBadRequest, "error.player_not_in_replay")
+		return side, nil, false
+	}
+
+	analyses, err := h.repo.GetAnalysesByReplayID(id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.load_analyses")
+		return side, nil, false
+	}
+	data := parseAnalysisData(analyses)[playerID]
+	if data == nil {
+		respondError(w, r, http.StatusNotFound, "error.no_player_analysis", i18n.Params{"player": player.Name})
+		return side, nil, false
+	}
+
+	return newComparisonSide(replay, player), data, true
+}
+
+// newComparisonSide beschreibt Replay und Spieler einer Vergleichsseite
+func newComparisonSide(replay *models.Replay, player *models.GamePlayer) models.ComparisonSide {
+	return models.ComparisonSide{
+		ReplayID: replay.ID,
+		PlayerID: player.PlayerID,
+		Name:     player.Name,
+		Race:     player.Race,
+		Result:   player.Result,
+		Map:      replay.Map,
+		Duration: replay.Duration,
+		PlayedAt: replay.PlayedAt,
+	}
+}
+
+// containsID prüft ob id in ids enthalten ist
+func containsID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
 // copyFile kopiert eine Datei
 func copyFile(src, dst string) error {
 	source, err := os.Open(src)
@@ -698,7 +1327,7 @@ func NewMentorHandler(repo *repository.Repository) *MentorHandler {
 func (h *MentorHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
@@ -708,7 +1337,7 @@ func (h *MentorHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
 	// Hole heutige Statistiken
 	todayStats, err := h.repo.GetOrCreateDailyProgress(user.ID, time.Now())
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Laden der Tagesstatistiken")
+		respondError(w, r, http.StatusInternalServerError, "error.load_daily_stats")
 		return
 	}
 
@@ -743,6 +1372,14 @@ func (h *MentorHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
 	now := time.Now()
 	weekStart := now.AddDate(0, 0, -int(now.Weekday()))
 	weeklyReport, _ := h.repo.GetWeeklyReport(user.ID, weekStart)
+	localizeWeeklyReport(weeklyReport, requestLanguage(r))
+
+	// Hole wiederkehrende Probleme der letzten Spiele
+	recurringWeaknesses, err := h.repo.GetRecurringProblems(user.ID, recurringProblemGames, 2)
+	if err != nil {
+		recurringWeaknesses = []models.RecurringProblem{}
+	}
+	localizeRecurringProblems(recurringWeaknesses, requestLanguage(r))
 
 	dashboard := models.MentorDashboard{
 		User:          user.ToPublic(),
@@ -753,6 +1390,7 @@ func (h *MentorHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
 		CurrentFocus:  currentFocus,
 		WeeklyReport:  weeklyReport,
 		ProgressTrend: progressTrend,
+		RecurringWeaknesses: recurringWeaknesses,
 	}
 
 	respondJSON(w, http.StatusOK, dashboard)
@@ -762,7 +1400,7 @@ func (h *MentorHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
 func (h *MentorHandler) GetGoals(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
@@ -771,12 +1409,12 @@ func (h *MentorHandler) GetGoals(w http.ResponseWriter, r *http.Request) {
 
 	goals, err := h.repo.GetActiveGoals(user.ID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Laden der Ziele")
+		respondError(w, r, http.StatusInternalServerError, "error.load_goals")
 		return
 	}
 
 	// Füge Zielvorlagen hinzu
-	templates := models.GetGoalTemplates()
+	templates := localizedGoalTemplates(requestLanguage(r))
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"goals":     goals,
@@ -788,25 +1426,25 @@ func (h *MentorHandler) GetGoals(w http.ResponseWriter, r *http.Request) {
 func (h *MentorHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
 	var req models.CreateGoalRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Anfrage: "+err.Error())
+		respondError(w, r, http.StatusBadRequest, "error.invalid_request_detail", i18n.Params{"detail": err.Error()})
 		return
 	}
 
 	// Validierung
 	if req.GoalType == "" || req.MetricName == "" || req.TargetValue <= 0 {
-		respondError(w, http.StatusBadRequest, "goal_type, metric_name und target_value sind erforderlich")
+		respondError(w, r, http.StatusBadRequest, "error.goal_fields_required")
 		return
 	}
 
 	// Validiere goal_type
 	if req.GoalType != "daily" && req.GoalType != "weekly" {
-		respondError(w, http.StatusBadRequest, "goal_type muss 'daily' oder 'weekly' sein")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_goal_type")
 		return
 	}
 
@@ -815,14 +1453,14 @@ func (h *MentorHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
 		"games_played": true, "apm": true, "supply_block": true,
 		"win_rate": true, "sq": true,
 	}
-	if !validMetrics[req.MetricName] {
-		respondError(w, http.StatusBadRequest, "Ungültiger metric_name")
+	if !validMetrics[req.MetricName] && !models.IsPhaseMetric(req.MetricName) && !models.IsBenchmarkMetric(req.MetricName) {
+		respondError(w, r, http.StatusBadRequest, "error.invalid_metric_name")
 		return
 	}
 
 	// Setze Standard-Comparison
 	if req.Comparison == "" {
-		if req.MetricName == "supply_block" {
+		if strings.HasPrefix(req.MetricName, "supply_block") {
 			req.Comparison = "<="
 		} else {
 			req.Comparison = ">="
@@ -855,7 +1493,7 @@ func (h *MentorHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.repo.CreateGoal(goal); err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Erstellen des Ziels: "+err.Error())
+		respondError(w, r, http.StatusInternalServerError, "error.create_goal_detail", i18n.Params{"detail": err.Error()})
 		return
 	}
 
@@ -866,31 +1504,31 @@ func (h *MentorHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
 func (h *MentorHandler) DeleteGoal(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
 	idStr := chi.URLParam(r, "id")
 	goalID, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Ziel-ID")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_goal_id")
 		return
 	}
 
 	// Setze Status auf 'deleted' (soft delete)
 	if err := h.repo.UpdateGoalStatus(goalID, "deleted"); err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Löschen des Ziels")
+		respondError(w, r, http.StatusInternalServerError, "error.delete_goal")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Ziel gelöscht"})
+	respondJSON(w, http.StatusOK, map[string]string{"message": i18n.T(requestLanguage(r), "message.goal_deleted")})
 }
 
 // GetProgress behandelt GET /api/v1/mentor/progress
 func (h *MentorHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
@@ -904,7 +1542,7 @@ func (h *MentorHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
 
 	progress, err := h.repo.GetProgressHistory(user.ID, days)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Laden des Fortschritts")
+		respondError(w, r, http.StatusInternalServerError, "error.load_progress")
 		return
 	}
 
@@ -918,7 +1556,7 @@ func (h *MentorHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
 func (h *MentorHandler) GetWeeklyReport(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
@@ -935,7 +1573,7 @@ func (h *MentorHandler) GetWeeklyReport(w http.ResponseWriter, r *http.Request)
 
 	report, err := h.repo.GetWeeklyReport(user.ID, weekStart)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Laden des Wochenberichts")
+		respondError(w, r, http.StatusInternalServerError, "error.load_weekly_report")
 		return
 	}
 
@@ -943,19 +1581,58 @@ func (h *MentorHandler) GetWeeklyReport(w http.ResponseWriter, r *http.Request)
 	if report == nil || generate {
 		report, err = h.repo.GenerateWeeklyReport(user.ID, weekStart)
 		if err != nil {
-			respondError(w, http.StatusInternalServerError, "Fehler beim Generieren des Wochenberichts: "+err.Error())
+			respondError(w, r, http.StatusInternalServerError, "error.weekly_report_detail", i18n.Params{"detail": err.Error()})
 			return
 		}
 	}
 
+	localizeWeeklyReport(report, requestLanguage(r))
 	respondJSON(w, http.StatusOK, report)
 }
 
+// localizeWeeklyReport übersetzt die gespeicherten Schlüssel von Stärken, Schwächen
+// und Fokus-Empfehlung; ältere Berichte mit Klartext bleiben unverändert
+func localizeWeeklyReport(report *models.WeeklyReport, lang string) {
+	if report == nil {
+		return
+	}
+	report.FocusSuggestion = i18n.T(lang, report.FocusSuggestion)
+	report.Strengths = localizeKeyList(report.Strengths, lang)
+	report.Weaknesses = localizeKeyList(report.Weaknesses, lang)
+	localizeRecurringProblems(report.RecurringWeaknesses, lang)
+}
+
+// recurringProblemGames ist die Anzahl letzter Spiele, in denen wiederkehrende Probleme gesucht werden
+const recurringProblemGames = 10
+
+// localizeRecurringProblems setzt die übersetzten Namen wiederkehrender Probleme
+func localizeRecurringProblems(problems []models.RecurringProblem, lang string) {
+	for i := range problems {
+		problems[i].Name = i18n.T(lang, "strategic.problem."+problems[i].Key+".name")
+	}
+}
+
+// localizeKeyList übersetzt eine als JSON gespeicherte Liste von Schlüsseln
+func localizeKeyList(raw json.RawMessage, lang string) json.RawMessage {
+	var keys []string
+	if len(raw) == 0 || json.Unmarshal(raw, &keys) != nil {
+		return raw
+	}
+	for i, key := range keys {
+		keys[i] = i18n.T(lang, key)
+	}
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return raw
+	}
+	return data
+}
+
 // SetCoachingFocus behandelt POST /api/v1/mentor/focus
 func (h *MentorHandler) SetCoachingFocus(w http.ResponseWriter, r *http.Request) {
 	user := GetUserFromContext(r.Context())
 	if user == nil {
-		respondError(w, http.StatusUnauthorized, "Nicht authentifiziert")
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
 		return
 	}
 
@@ -964,7 +1641,7 @@ func (h *MentorHandler) SetCoachingFocus(w http.ResponseWriter, r *http.Request)
 		Description string `json:"description"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Ungültige Anfrage")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_request")
 		return
 	}
 
@@ -974,21 +1651,36 @@ func (h *MentorHandler) SetCoachingFocus(w http.ResponseWriter, r *http.Request)
 		"army_control": true, "scouting": true,
 	}
 	if !validAreas[req.FocusArea] {
-		respondError(w, http.StatusBadRequest, "Ungültiger Fokusbereich")
+		respondError(w, r, http.StatusBadRequest, "error.invalid_focus_area")
 		return
 	}
 
 	focus, err := h.repo.SetCoachingFocus(user.ID, req.FocusArea, req.Description)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Fehler beim Setzen des Fokus")
+		respondError(w, r, http.StatusInternalServerError, "error.set_focus")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, focus)
 }
 
+// localizedGoalTemplates gibt die Zielvorlagen mit Namen und Beschreibung in der Sprache zurück
+func localizedGoalTemplates(lang string) []models.GoalTemplate {
+	templates := models.GetGoalTemplates()
+	for i := range templates {
+		prefix := "goal_template." + templates[i].GoalType + "." + templates[i].MetricName + "."
+		if name, ok := i18n.Lookup(lang, prefix+"name"); ok {
+			templates[i].Name = name
+		}
+		if description, ok := i18n.Lookup(lang, prefix+"description"); ok {
+			templates[i].Description = description
+		}
+	}
+	return templates
+}
+
 // GetGoalTemplates behandelt GET /api/v1/mentor/goal-templates
 func (h *MentorHandler) GetGoalTemplates(w http.ResponseWriter, r *http.Request) {
-	templates := models.GetGoalTemplates()
+	templates := localizedGoalTemplates(requestLanguage(r))
 	respondJSON(w, http.StatusOK, templates)
 }
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"sc2-analytics/internal/analyzer/opponent"
+	"sc2-analytics/internal/analyzer/strategic"
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+)
+
+// minSharedOpponentGames: Spiele aus Replays anderer Benutzer fließen erst ab dieser Anzahl
+// ins Dossier ein, damit sich aus den Kennzahlen keine einzelnen fremden Spiele ablesen lassen
+const minSharedOpponentGames = 5
+
+// GetOpponentDossier behandelt GET /api/v1/opponents/:toon_handle
+// Fasst die Spiele des Gegners in der Datenbank zusammen: eigene Bilanz gegen ihn, Rassen,
+// erkannte Eröffnungen, Spieldauer, Lieblingskarten und wann er zuletzt gesehen wurde.
+// Die Bilanz stammt nur aus den eigenen Replays; Spiele aus fremden Replays gehen nur
+// aggregiert und erst ab minSharedOpponentGames Spielen in die übrigen Kennzahlen ein.
+func (h *Handler) GetOpponentDossier(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+		return
+	}
+
+	toonHandle := chi.URLParam(r, "toon_handle")
+	player, err := h.repo.GetPlayerByToonHandle(toonHandle)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if player == nil || toonHandle == "" {
+		respondError(w, r, http.StatusNotFound, "error.opponent_not_found", i18n.Params{"toon_handle": toonHandle})
+		return
+	}
+
+	allGames, err := h.repo.GetOpponentGames(player.ID, user.ID)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	games := allGames
+	if shared := len(allGames) - countOwnGames(allGames); shared > 0 && shared < minSharedOpponentGames {
+		games = ownGames(allGames)
+	}
+
+	// Eröffnungen aus den gespeicherten Build Orders erkennen, gezählt je Matchup des Gegners
+	sa := strategic.NewStrategicAnalyzer(requestLanguage(r))
+	sa.SetKnowledgeBase(h.matchups)
+	analyzed := make(map[string]int)
+	for i := range games {
+		g := &games[i]
+		if len(g.BuildOrder) == 0 {
+			continue
+		}
+		analyzed[strategic.Matchup(g.Race, g.OpponentRace)]++
+
+		sa.SetGameVersion(g.GameVersion)
+		if detected := sa.DetectBuild(g.Race, g.OpponentRace, g.BuildOrder); detected != nil {
+			g.Opening = detected.ID
+			g.OpeningName = detected.Name
+		}
+	}
+
+	respondJSON(w, http.StatusOK, opponent.NewOpponentAnalyzer().Analyze(*player, games, analyzed))
+}
+
+// countOwnGames zählt die Spiele aus den Replays des Benutzers
+func countOwnGames(games []models.OpponentGame) int {
+	count := 0
+	for _, g := range games {
+		if g.Own {
+			count++
+		}
+	}
+	return count
+}
+
+// ownGames gibt nur die Spiele aus den Replays des Benutzers zurück
+func ownGames(games []models.OpponentGame) []models.OpponentGame {
+	result := []models.OpponentGame{}
+	for _, g := range games {
+		if g.Own {
+			result = append(result, g)
+		}
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"sc2-analytics/internal/analyzer/performance"
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+)
+
+// GetPerformanceMatrix behandelt GET /api/v1/mentor/performance
+// Win Rate, SQ, APM und Supply Block der verknüpften Spiele je Matchup und Karte mit Konfidenzintervallen
+// und signifikanten Schwachstellen; ?days= beschränkt auf die letzten Tage (Standard: alle Spiele)
+func (h *MentorHandler) GetPerformanceMatrix(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+		return
+	}
+
+	days := 0
+	if d := r.URL.Query().Get("days"); d != "" {
+		if parsed, err := strconv.Atoi(d); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+	var since time.Time
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	games, err := h.repo.GetPerformanceGames(user.ID, since)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+
+	matrix := performance.NewPerformanceAnalyzer().Analyze(games)
+	lang := requestLanguage(r)
+	localizeWeakSpots(matrix.WeakSpots, lang)
+	matrix.WeakSpotNote = i18n.T(lang, "performance.weak_spot_note", i18n.Params{
+		"tested":    matrix.TestedCells,
+		"min_games": performance.WeakSpotMinGames,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"matrix": matrix,
+		"days":   days,
+	})
+}
+
+// localizeWeakSpots setzt die Meldungen der Schwachstellen in der Sprache der Anfrage
+func localizeWeakSpots(spots []models.PerformanceWeakSpot, lang string) {
+	for i, s := range spots {
+		key := "performance.weak_spot"
+		if s.Map == "" {
+			key = "performance.weak_spot_matchup"
+		}
+		spots[i].Message = i18n.T(lang, key, i18n.Params{
+			"matchup":  s.Matchup,
+			"map":      s.Map,
+			"win_rate": s.WinRate,
+			"games":    s.Games,
+			"baseline": s.Baseline,
+		})
+	}
+}
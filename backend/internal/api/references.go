@@ -0,0 +1,277 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"sc2-analytics/internal/analyzer/compare"
+	"sc2-analytics/internal/analyzer/similarity"
+	"sc2-analytics/internal/analyzer/strategic"
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+)
+
+// ListReferences behandelt GET /api/v1/references?matchup=PvZ
+func (h *Handler) ListReferences(w http.ResponseWriter, r *http.Request) {
+	refs, err := h.repo.ListReferenceReplays(r.URL.Query().Get("matchup"))
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{"references": refs})
+}
+
+// CreateReference behandelt POST /api/v1/references (nur Admins und Coaches)
+// Markiert ein eigenes Replay als Referenz für den Spieler player_id; ohne build wird der Build erkannt
+func (h *Handler) CreateReference(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+		return
+	}
+
+	var req models.CreateReferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, "error.invalid_request_detail", i18n.Params{"detail": err.Error()})
+		return
+	}
+	if req.PlayerID == 0 {
+		respondError(w, r, http.StatusBadRequest, "error.player_id_required")
+		return
+	}
+
+	// Prüfe ob das Replay dem Benutzer gehört
+	owns, err := h.repo.UserOwnsReplay(user.ID, req.ReplayID)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if !owns {
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
+		return
+	}
+
+	replay, err := h.repo.GetReplayByID(req.ReplayID)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if replay == nil {
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
+		return
+	}
+
+	analyses, err := h.repo.GetAnalysesByReplayID(replay.ID)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.load_analyses")
+		return
+	}
+	analysisData := parseAnalysisData(analyses)
+	player, opponent := strategicPlayers(replay.GamePlayers, analysisData, req.PlayerID)
+	if player == nil || player.PlayerID != req.PlayerID {
+		respondError(w, r, http.StatusBadRequest, "error.player_not_in_replay")
+		return
+	}
+	if opponent == nil {
+		respondError(w, r, http.StatusBadRequest, "error.no_opponent")
+		return
+	}
+
+	ref := &models.ReferenceReplay{
+		ReplayID:  replay.ID,
+		PlayerID:  player.PlayerID,
+		Player:    strings.TrimSpace(req.Player),
+		Matchup:   strategic.Matchup(player.Race, opponent.Race),
+		Build:     strings.TrimSpace(req.Build),
+		CreatedBy: user.ID,
+	}
+	if ref.Player == "" {
+		ref.Player = player.Name
+	}
+	if ref.Build == "" {
+		if detected := h.strategicAnalyzer(replay, i18n.DefaultLanguage).DetectBuild(player.Race, opponent.Race, analysisData[player.PlayerID].BuildOrder); detected != nil {
+			ref.Build = detected.ID
+		}
+	}
+
+	saved, err := h.repo.SaveReferenceReplay(ref)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database_detail", i18n.Params{"detail": err.Error()})
+		return
+	}
+	respondJSON(w, http.StatusCreated, saved)
+}
+
+// DeleteReference behandelt DELETE /api/v1/references/:id (nur Admins und Coaches)
+func (h *Handler) DeleteReference(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "error.invalid_reference_id")
+		return
+	}
+
+	deleted, err := h.repo.DeleteReferenceReplay(id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if !deleted {
+		respondError(w, r, http.StatusNotFound, "error.reference_not_found")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"message": i18n.T(requestLanguage(r), "message.reference_deleted")})
+}
+
+// GetReplayReference behandelt GET /api/v1/replays/:id/reference
+// Vergleicht das Spiel (?player_id=, sonst der verknüpfte Spieler) mit dem passendsten Referenz-Replay
+func (h *Handler) GetReplayReference(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
+		return
+	}
+
+	// Prüfe ob das Replay dem Benutzer gehört
+	owns, err := h.repo.UserOwnsReplay(user.ID, id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if !owns {
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
+		return
+	}
+
+	replay, err := h.repo.GetReplayByID(id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if replay == nil {
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
+		return
+	}
+
+	analyses, err := h.repo.GetAnalysesByReplayID(id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.load_analyses")
+		return
+	}
+
+	perspectiveID, err := h.repo.GetUserReplayPlayerID(user.ID, id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	explicit := false
+	if pid := r.URL.Query().Get("player_id"); pid != "" {
+		perspectiveID, err = strconv.ParseInt(pid, 10, 64)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, "error.invalid_player_id")
+			return
+		}
+		explicit = true
+	}
+
+	analysisData := parseAnalysisData(analyses)
+	player, opponent := strategicPlayers(replay.GamePlayers, analysisData, perspectiveID)
+	if explicit && (player == nil || player.PlayerID != perspectiveID) {
+		respondError(w, r, http.StatusBadRequest, "error.player_not_in_replay")
+		return
+	}
+	if player == nil || opponent == nil {
+		respondError(w, r, http.StatusBadRequest, "error.no_opponent")
+		return
+	}
+
+	ref := h.referenceComparison(replay, player, opponent, analysisData)
+	if ref == nil {
+		respondError(w, r, http.StatusNotFound, "error.no_reference", i18n.Params{"matchup": strategic.Matchup(player.Race, opponent.Race)})
+		return
+	}
+	respondJSON(w, http.StatusOK, ref)
+}
+
+// referenceComparison vergleicht das Spiel von player mit dem passendsten Referenz-Replay seines Matchups:
+// bevorzugt mit demselben erkannten Build, dann mit der ähnlichsten Build Order (nil = keine Referenz)
+func (h *Handler) referenceComparison(replay *models.Replay, player, opponent *models.GamePlayer, analysisData map[int64]*models.AnalysisData) *models.ReferenceComparison {
+	data := analysisData[player.PlayerID]
+	if data == nil || opponent == nil {
+		return nil
+	}
+
+	refs, err := h.repo.ListReferenceReplays(strategic.Matchup(player.Race, opponent.Race))
+	if err != nil {
+		log.Printf("Konnte Referenz-Replays nicht laden: %v", err)
+		return nil
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	build := ""
+	if detected := h.strategicAnalyzer(replay, i18n.DefaultLanguage).DetectBuild(player.Race, opponent.Race, data.BuildOrder); detected != nil {
+		build = detected.ID
+	}
+
+	var best *models.ReferenceComparison
+	var bestData *models.AnalysisData
+	for _, ref := range refs {
+		// Ein Referenzspiel wird nicht mit sich selbst verglichen
+		if ref.ReplayID == replay.ID {
+			continue
+		}
+		analyses, err := h.repo.GetAnalysesByReplayID(ref.ReplayID)
+		if err != nil {
+			continue
+		}
+		refData := parseAnalysisData(analyses)[ref.PlayerID]
+		if refData == nil {
+			continue
+		}
+
+		candidate := &models.ReferenceComparison{
+			Reference:  ref,
+			Build:      build,
+			BuildMatch: build != "" && ref.Build == build,
+			Similarity: similarity.BuildSimilarity(data.BuildOrder, refData.BuildOrder),
+		}
+		if best == nil || candidate.BuildMatch && !best.BuildMatch ||
+			candidate.BuildMatch == best.BuildMatch && candidate.Similarity > best.Similarity {
+			best, bestData = candidate, refData
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	refReplay, err := h.repo.GetReplayByID(best.Reference.ReplayID)
+	if err != nil || refReplay == nil {
+		return nil
+	}
+	var refPlayer *models.GamePlayer
+	for i := range refReplay.GamePlayers {
+		if refReplay.GamePlayers[i].PlayerID == best.Reference.PlayerID {
+			refPlayer = &refReplay.GamePlayers[i]
+		}
+	}
+	if refPlayer == nil {
+		return nil
+	}
+
+	ca := compare.NewComparisonAnalyzer()
+	best.Comparison = ca.Analyze(newComparisonSide(replay, player), newComparisonSide(refReplay, refPlayer), data, bestData)
+	best.Gaps = ca.ReferenceGaps(best.Comparison)
+	return best
+}
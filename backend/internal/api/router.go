@@ -8,6 +8,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"sc2-analytics/internal/models"
 	"sc2-analytics/internal/repository"
 )
 
@@ -25,7 +26,7 @@ func NewRouter(handler *Handler, repo *repository.Repository) *chi.Mux {
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   allowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
@@ -47,6 +48,7 @@ func NewRouter(handler *Handler, repo *repository.Repository) *chi.Mux {
 			r.Group(func(r chi.Router) {
 				r.Use(AuthMiddleware(repo))
 				r.Get("/me", authHandler.Me)
+				r.Patch("/me", authHandler.UpdateMe)
 			})
 		})
 
@@ -64,11 +66,38 @@ func NewRouter(handler *Handler, repo *repository.Repository) *chi.Mux {
 				r.Get("/{id}", handler.GetReplay)
 				r.Get("/{id}/analysis", handler.GetReplayAnalysis)
 				r.Get("/{id}/strategic", handler.GetStrategicAnalysis)
+				r.Get("/{id}/state", handler.GetReplayState)
+				r.Get("/{id}/timeline", handler.GetReplayTimeline)
+				r.Get("/{id}/reference", handler.GetReplayReference)
+				r.Get("/{id}/similar", handler.GetSimilarReplays)
 				r.Delete("/{id}", handler.DeleteReplay)
 				r.Post("/{id}/claim", handler.ClaimReplay)
 			})
 		})
 
+		// Referenz-Replays: lesen alle Benutzer, verwalten nur Admins und Coaches
+		r.Route("/references", func(r chi.Router) {
+			r.Use(AuthMiddleware(repo))
+			r.Get("/", handler.ListReferences)
+			r.Group(func(r chi.Router) {
+				r.Use(RequireRole(models.RoleAdmin, models.RoleCoach))
+				r.Post("/", handler.CreateReference)
+				r.Delete("/{id}", handler.DeleteReference)
+			})
+		})
+
+		// Vergleich zweier Spiele (authentifiziert, nur eigene Replays)
+		r.Group(func(r chi.Router) {
+			r.Use(AuthMiddleware(repo))
+			r.Get("/compare", handler.GetReplayComparison)
+		})
+
+		// Gegner-Dossier aus allen gespeicherten Replays (authentifiziert)
+		r.Group(func(r chi.Router) {
+			r.Use(AuthMiddleware(repo))
+			r.Get("/opponents/{toon_handle}", handler.GetOpponentDossier)
+		})
+
 		// Stats
 		r.Route("/stats", func(r chi.Router) {
 			r.Get("/trends", handler.GetTrends)
@@ -85,8 +114,12 @@ func NewRouter(handler *Handler, repo *repository.Repository) *chi.Mux {
 			r.Get("/weekly-report", mentorHandler.GetWeeklyReport)
 			r.Post("/focus", mentorHandler.SetCoachingFocus)
 			r.Get("/goal-templates", mentorHandler.GetGoalTemplates)
+			r.Get("/performance", mentorHandler.GetPerformanceMatrix)
 		})
 
+		// Verfügbare Sprachen für generierte Texte
+		r.Get("/languages", handler.ListLanguages)
+
 		// Health Check
 		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 			respondJSON(w, 200, map[string]string{"status": "ok"})
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"sc2-analytics/internal/analyzer/similarity"
+	"sc2-analytics/internal/analyzer/strategic"
+	"sc2-analytics/internal/i18n"
+	"sc2-analytics/internal/models"
+)
+
+// GetSimilarReplays behandelt GET /api/v1/replays/:id/similar
+// Sucht unter den eigenen Spielen und den Referenz-Replays die ähnlichsten Spiele aus Sicht von
+// ?player_id= (sonst der verknüpfte Spieler); ?result=Win bzw. Loss filtert, ?limit= begrenzt (Standard 10)
+func (h *Handler) GetSimilarReplays(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		respondError(w, r, http.StatusUnauthorized, "error.unauthenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "error.invalid_replay_id")
+		return
+	}
+
+	limit := 10
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 50 {
+			limit = parsed
+		}
+	}
+	result := r.URL.Query().Get("result")
+
+	// Prüfe ob das Replay dem Benutzer gehört
+	owns, err := h.repo.UserOwnsReplay(user.ID, id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if !owns {
+		respondError(w, r, http.StatusForbidden, "error.replay_forbidden")
+		return
+	}
+
+	replay, err := h.repo.GetReplayByID(id)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if replay == nil {
+		respondError(w, r, http.StatusNotFound, "error.replay_not_found")
+		return
+	}
+
+	// Spieler: ?player_id=, sonst der Spieler, mit dem der Benutzer das Replay verknüpft hat
+	var playerID int64
+	if pid := r.URL.Query().Get("player_id"); pid != "" {
+		playerID, err = strconv.ParseInt(pid, 10, 64)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, "error.invalid_player_id")
+			return
+		}
+	} else {
+		playerID, err = h.repo.GetUserReplayPlayerID(user.ID, id)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, "error.database")
+			return
+		}
+		if playerID == 0 {
+			respondError(w, r, http.StatusBadRequest, "error.compare_player_required", i18n.Params{"param": "player_id"})
+			return
+		}
+	}
+	var player *models.GamePlayer
+	for i := range replay.GamePlayers {
+		if replay.GamePlayers[i].PlayerID == playerID {
+			player = &replay.GamePlayers[i]
+		}
+	}
+	if player == nil {
+		respondError(w, r, http.StatusBadRequest, "error.player_not_in_replay")
+		return
+	}
+
+	// Fehlt der Indexeintrag (z.B. Analyse nachträglich erstellt), wird er jetzt angelegt
+	target, err := h.repo.GetGameSignature(id, playerID)
+	if err == nil && target == nil && h.storeGameSignatures(replay, playerID) > 0 {
+		target, err = h.repo.GetGameSignature(id, playerID)
+	}
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if target == nil {
+		respondError(w, r, http.StatusNotFound, "error.no_player_analysis", i18n.Params{"player": player.Name})
+		return
+	}
+
+	candidates, err := h.repo.GetSimilarityCandidates(user.ID)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error.database")
+		return
+	}
+	if result != "" {
+		filtered := candidates[:0]
+		for _, c := range candidates {
+			if c.Result == result {
+				filtered = append(filtered, c)
+			}
+		}
+		candidates = filtered
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"game":    target,
+		"similar": similarity.NewSimilarityAnalyzer().Rank(*target, candidates, limit),
+	})
+}
+
+// storeGameSignatures nimmt die analysierten Spieler eines Replays in den Ähnlichkeitsindex auf,
+// ohne playerIDs alle; gibt die Anzahl gespeicherter Einträge zurück
+func (h *Handler) storeGameSignatures(replay *models.Replay, playerIDs ...int64) int {
+	analyses, err := h.repo.GetAnalysesByReplayID(replay.ID)
+	if err != nil {
+		log.Printf("Konnte Analysen für Replay %d nicht laden: %v", replay.ID, err)
+		return 0
+	}
+	analysisData := parseAnalysisData(analyses)
+	if len(playerIDs) == 0 {
+		for playerID := range analysisData {
+			playerIDs = append(playerIDs, playerID)
+		}
+	}
+
+	stored := 0
+	for _, playerID := range playerIDs {
+		player, opponent := strategicPlayers(replay.GamePlayers, analysisData, playerID)
+		if player == nil || player.PlayerID != playerID {
+			continue
+		}
+		sig := &models.GameSignature{
+			ReplayID: replay.ID,
+			PlayerID: playerID,
+			Matchup:  strategic.Matchup(player.Race, ""),
+			Build:    similarity.Signature(analysisData[playerID].BuildOrder),
+		}
+		if opponent != nil {
+			sig.OpponentID = opponent.PlayerID
+			sig.Matchup = strategic.Matchup(player.Race, opponent.Race)
+			sig.OpponentBuild = similarity.Signature(analysisData[opponent.PlayerID].BuildOrder)
+		}
+		if err := h.repo.SaveGameSignature(sig); err != nil {
+			log.Printf("Konnte Indexeintrag nicht speichern für Replay %d, Player %d: %v", replay.ID, playerID, err)
+			continue
+		}
+		stored++
+	}
+	return stored
+}
+
+// BackfillGameSignatures nimmt bereits analysierte Spiele in den Ähnlichkeitsindex auf
+func (h *Handler) BackfillGameSignatures() (int, error) {
+	missing, err := h.repo.GetAnalysesWithoutSignature()
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for replayID, playerIDs := range missing {
+		replay, err := h.repo.GetReplayByID(replayID)
+		if err != nil || replay == nil {
+			continue
+		}
+		stored += h.storeGameSignatures(replay, playerIDs...)
+	}
+	return stored, nil
+}
@@ -0,0 +1,220 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage ist die Sprache, in der Analysen gespeichert werden und auf die
+// bei fehlenden Übersetzungen zurückgegriffen wird
+const DefaultLanguage = "de"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// Params sind die Platzhalter-Werte einer Nachricht, z.B. {"count": 3} für "{count}"
+type Params map[string]interface{}
+
+// catalogue enthält die Texte einer Sprache; Werte sind Strings oder String-Listen
+type catalogue map[string]interface{}
+
+var (
+	mu         sync.RWMutex
+	catalogues = make(map[string]catalogue)
+)
+
+func init() {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("Übersetzungen nicht lesbar: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := localeFS.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("Übersetzung %s nicht lesbar: %v", entry.Name(), err))
+		}
+		if err := addCatalogue(languageFromFile(entry.Name()), data); err != nil {
+			panic(err.Error())
+		}
+	}
+}
+
+// LoadDir lädt zusätzliche Übersetzungen aus einem Verzeichnis (<sprache>.json)
+// Schlüssel vorhandener Sprachen werden überschrieben, neue Sprachen ergänzt
+func LoadDir(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("konnte Übersetzungen nicht suchen: %w", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return 0, fmt.Errorf("konnte Übersetzung nicht lesen: %w", err)
+		}
+		if err := addCatalogue(languageFromFile(filepath.Base(file)), data); err != nil {
+			return 0, err
+		}
+	}
+	return len(files), nil
+}
+
+// languageFromFile leitet den Sprachcode aus dem Dateinamen ab ("en.json" → "en")
+func languageFromFile(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))
+}
+
+// addCatalogue prüft einen Katalog und führt ihn mit der bestehenden Sprache zusammen
+func addCatalogue(lang string, data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("ungültige Übersetzung %s: %w", lang, err)
+	}
+
+	cat := make(catalogue, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			cat[key] = v
+		case []interface{}:
+			list := make([]string, 0, len(v))
+			for _, elem := range v {
+				s, ok := elem.(string)
+				if !ok {
+					return fmt.Errorf("Übersetzung %s: %s enthält keinen Text", lang, key)
+				}
+				list = append(list, s)
+			}
+			cat[key] = list
+		default:
+			return fmt.Errorf("Übersetzung %s: %s muss Text oder Liste sein", lang, key)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	existing, ok := catalogues[lang]
+	if !ok {
+		catalogues[lang] = cat
+		return nil
+	}
+	for key, value := range cat {
+		existing[key] = value
+	}
+	return nil
+}
+
+// Languages gibt alle verfügbaren Sprachen zurück, die Standardsprache zuerst
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	langs := make([]string, 0, len(catalogues))
+	for lang := range catalogues {
+		if lang != DefaultLanguage {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return append([]string{DefaultLanguage}, langs...)
+}
+
+// Normalize gibt die unterstützte Sprache zu einem Sprach-Tag zurück ("en-US" → "en")
+// oder "" wenn die Sprache nicht verfügbar ist
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return ""
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	if _, ok := catalogues[tag]; ok {
+		return tag
+	}
+	if i := strings.IndexAny(tag, "-_"); i > 0 {
+		if _, ok := catalogues[tag[:i]]; ok {
+			return tag[:i]
+		}
+	}
+	return ""
+}
+
+// IsSupported prüft ob für die Sprache ein Katalog vorhanden ist
+func IsSupported(lang string) bool {
+	return Normalize(lang) != ""
+}
+
+// Negotiate wählt anhand eines Accept-Language Headers die beste verfügbare Sprache
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := DefaultLanguage, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		lang := Normalize(fields[0])
+		if lang == "" || q <= bestQ {
+			continue
+		}
+		best, bestQ = lang, q
+	}
+	return best
+}
+
+// Lookup gibt den Text eines Schlüssels zurück, ohne Platzhalter zu ersetzen
+// Fehlt der Schlüssel in der Sprache, wird die Standardsprache verwendet
+func Lookup(lang, key string) (string, bool) {
+	s, ok := lookup(lang, key).(string)
+	return s, ok
+}
+
+// T übersetzt einen Schlüssel und ersetzt Platzhalter wie {count}
+// Unbekannte Schlüssel werden unverändert zurückgegeben, damit bereits
+// gespeicherte Klartexte weiterhin angezeigt werden
+func T(lang, key string, params ...Params) string {
+	text, ok := Lookup(lang, key)
+	if !ok {
+		text = key
+	}
+	for _, p := range params {
+		for name, value := range p {
+			text = strings.ReplaceAll(text, "{"+name+"}", fmt.Sprint(value))
+		}
+	}
+	return text
+}
+
+// List gibt eine Textliste in der Sprache zurück
+func List(lang, key string) []string {
+	list, _ := lookup(lang, key).([]string)
+	return append([]string(nil), list...)
+}
+
+// lookup sucht einen Schlüssel in der Sprache und danach in der Standardsprache
+func lookup(lang, key string) interface{} {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if cat, ok := catalogues[lang]; ok {
+		if v, ok := cat[key]; ok {
+			return v
+		}
+	}
+	if v, ok := catalogues[DefaultLanguage][key]; ok {
+		return v
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"time"
 )
 
@@ -37,6 +39,9 @@ type GamePlayer struct {
 	APM         float64 `json:"apm"`
 	SpendingQuotient float64 `json:"spending_quotient"`
 	IsHuman     bool    `json:"is_human"`
+	ToonHandle  string  `json:"toon_handle,omitempty"` // Battle.net-Kennung des Spielers (Gegner-Dossier)
+	RandomRace  bool    `json:"random_race,omitempty"` // in der Lobby Random gewählt
+	LeftAt      int     `json:"left_at,omitempty"`     // Sekunden, zu denen der Spieler das Spiel verlassen hat (0 = unbekannt)
 }
 
 // Analysis enthält die vollständige Analyse eines Spielers in einem Replay
@@ -48,24 +53,201 @@ type Analysis struct {
 	CreatedAt time.Time       `json:"created_at"`
 }
 
+// AnalysisVersion ist die Version der gespeicherten Analysen
+// Erhöhen, wenn sich gespeicherte Werte ändern (z.B. die SQ-Skala); ältere Analysen
+// werden beim Start aus der Replay-Datei neu berechnet
+const AnalysisVersion = 5
+
 // AnalysisData ist die strukturierte Analyse
 type AnalysisData struct {
+	Version            int                 `json:"version"` // AnalysisVersion beim Analysieren, 0 = vor Einführung
 	SupplyAnalysis     *SupplyAnalysis     `json:"supply_analysis"`
 	SpendingAnalysis   *SpendingAnalysis   `json:"spending_analysis"`
 	APMAnalysis        *APMAnalysis        `json:"apm_analysis"`
 	BuildOrder         []BuildOrderItem    `json:"build_order"`
 	InjectAnalysis     *InjectAnalysis     `json:"inject_analysis,omitempty"`
+	LarvaAnalysis      *LarvaAnalysis      `json:"larva_analysis,omitempty"`
+	CreepAnalysis      *CreepAnalysis      `json:"creep_analysis,omitempty"`
 	ProductionAnalysis *ProductionAnalysis `json:"production_analysis,omitempty"`
 	ArmyAnalysis       *ArmyAnalysis       `json:"army_analysis,omitempty"`
+	HotkeyAnalysis     *HotkeyAnalysis     `json:"hotkey_analysis,omitempty"`
+	AttentionAnalysis  *AttentionAnalysis  `json:"attention_analysis,omitempty"`
+	ScoutingAnalysis   *ScoutingAnalysis   `json:"scouting_analysis,omitempty"`
+	HarassmentAnalysis *HarassmentAnalysis `json:"harassment_analysis,omitempty"`
+	BenchmarkAnalysis  *BenchmarkAnalysis  `json:"benchmark_analysis,omitempty"`
+	WinProbability     *WinProbability     `json:"win_probability,omitempty"`
 	Suggestions        []Suggestion        `json:"suggestions"`
 }
 
+// Grenzen der festen Spielphasen in Sekunden
+const (
+	EarlyGameEnd = 360.0 // bis 6:00
+	MidGameEnd   = 720.0 // bis 12:00
+)
+
+// Namen der Spielphasen
+const (
+	PhaseEarly = "early"
+	PhaseMid   = "mid"
+	PhaseLate  = "late"
+)
+
+// GamePhase beschreibt den Zeitraum einer Spielphase
+type GamePhase struct {
+	Name  string  `json:"name"` // early, mid, late
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// Duration gibt die Dauer der Phase in Sekunden zurück
+func (gp GamePhase) Duration() float64 {
+	return gp.End - gp.Start
+}
+
+// Contains prüft, ob ein Zeitpunkt in der Phase liegt
+func (gp GamePhase) Contains(t float64) bool {
+	return t >= gp.Start && t < gp.End
+}
+
+// GamePhases teilt ein Spiel in feste Phasen auf; nicht erreichte Phasen fehlen
+func GamePhases(gameDuration float64) []GamePhase {
+	bands := []GamePhase{
+		{Name: PhaseEarly, Start: 0, End: EarlyGameEnd},
+		{Name: PhaseMid, Start: EarlyGameEnd, End: MidGameEnd},
+		{Name: PhaseLate, Start: MidGameEnd, End: math.MaxFloat64},
+	}
+	var phases []GamePhase
+	for _, phase := range bands {
+		if phase.Start >= gameDuration {
+			break
+		}
+		phase.End = math.Min(phase.End, gameDuration)
+		phases = append(phases, phase)
+	}
+	return phases
+}
+
+// PhaseAt gibt den Namen der Spielphase zu einem Zeitpunkt zurück
+func PhaseAt(t float64) string {
+	switch {
+	case t < EarlyGameEnd:
+		return PhaseEarly
+	case t < MidGameEnd:
+		return PhaseMid
+	default:
+		return PhaseLate
+	}
+}
+
+// MetricValues enthält zusätzliche Metriken pro Spiel, die als Ziel und Trend nutzbar sind,
+// z.B. Phasenwerte ("sq_mid", "supply_block_early") oder Benchmarks ("workers_at_6m")
+type MetricValues map[string]float64
+
+// PhaseMetricName bildet den Schlüssel einer Phasen-Metrik, z.B. ("sq", "mid") -> "sq_mid"
+func PhaseMetricName(metric, phase string) string {
+	return metric + "_" + phase
+}
+
+// IsPhaseMetric prüft, ob ein Metrikname eine gültige Phasen-Metrik ist
+func IsPhaseMetric(name string) bool {
+	for _, metric := range []string{"apm", "sq", "supply_block"} {
+		for _, phase := range []string{PhaseEarly, PhaseMid, PhaseLate} {
+			if name == PhaseMetricName(metric, phase) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PhaseMetrics sammelt die Phasenwerte der Einzelanalysen unter einheitlichen Schlüsseln
+func (ad *AnalysisData) PhaseMetrics() MetricValues {
+	metrics := MetricValues{}
+	if ad.APMAnalysis != nil {
+		for _, p := range ad.APMAnalysis.Phases {
+			metrics[PhaseMetricName("apm", p.Phase)] = p.APM
+		}
+	}
+	if ad.SpendingAnalysis != nil {
+		for _, p := range ad.SpendingAnalysis.Phases {
+			metrics[PhaseMetricName("sq", p.Phase)] = p.SpendingQuotient
+		}
+	}
+	if ad.SupplyAnalysis != nil {
+		for _, p := range ad.SupplyAnalysis.Phases {
+			metrics[PhaseMetricName("supply_block", p.Phase)] = p.BlockPercentage
+		}
+	}
+	return metrics
+}
+
+// GoalMetrics fasst alle Zusatz-Metriken eines Spiels zusammen (Phasen und Benchmarks)
+func (ad *AnalysisData) GoalMetrics() MetricValues {
+	metrics := ad.PhaseMetrics()
+	for key, value := range ad.BenchmarkMetrics() {
+		metrics[key] = value
+	}
+	return metrics
+}
+
+// BenchmarkTimes sind die festen Benchmark-Zeitpunkte in Sekunden (3:00 bis 10:00)
+var BenchmarkTimes = []float64{180, 240, 300, 360, 480, 600}
+
+// BenchmarkMetrics sind die Werte eines Benchmark-Snapshots
+var BenchmarkMetrics = []string{"supply", "workers", "bases", "army_value", "upgrades", "income"}
+
+// BenchmarkMetricName bildet den Schlüssel eines Benchmarks, z.B. ("workers", 360) -> "workers_at_6m"
+func BenchmarkMetricName(metric string, time float64) string {
+	return fmt.Sprintf("%s_at_%dm", metric, int(time)/60)
+}
+
+// IsBenchmarkMetric prüft, ob ein Metrikname ein gültiger Benchmark ist
+func IsBenchmarkMetric(name string) bool {
+	for _, metric := range BenchmarkMetrics {
+		for _, t := range BenchmarkTimes {
+			if name == BenchmarkMetricName(metric, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// BenchmarkMetrics gibt die Werte der erreichten Benchmark-Snapshots zurück
+func (ad *AnalysisData) BenchmarkMetrics() MetricValues {
+	metrics := MetricValues{}
+	if ad.BenchmarkAnalysis == nil {
+		return metrics
+	}
+	for _, snapshot := range ad.BenchmarkAnalysis.Snapshots {
+		if !snapshot.Reached {
+			continue
+		}
+		for metric, value := range snapshot.Values.ByMetric() {
+			metrics[BenchmarkMetricName(metric, snapshot.Time)] = value
+		}
+	}
+	return metrics
+}
+
 // SupplyAnalysis enthält Supply Block Informationen
 type SupplyAnalysis struct {
 	TotalBlockTime    float64       `json:"total_block_time"` // Sekunden
 	BlockPercentage   float64       `json:"block_percentage"`
+	MaxedOutTime      float64       `json:"maxed_out_time"` // Sekunden bei 200/200 (kein Block)
 	Blocks            []SupplyBlock `json:"blocks"`
 	SupplyTimeline    []SupplyPoint `json:"supply_timeline"`
+	Phases            []SupplyPhase `json:"phases,omitempty"`
+}
+
+// SupplyPhase fasst die Supply Blocks einer Spielphase zusammen
+type SupplyPhase struct {
+	Phase           string  `json:"phase"` // early, mid, late
+	Start           float64 `json:"start"`
+	End             float64 `json:"end"`
+	BlockTime       float64 `json:"block_time"` // Sekunden
+	BlockPercentage float64 `json:"block_percentage"`
+	Blocks          int     `json:"blocks"`
 }
 
 // SupplyBlock repräsentiert einen einzelnen Supply Block
@@ -76,6 +258,12 @@ type SupplyBlock struct {
 	Severity   string  `json:"severity"` // low, medium, high
 	SupplyUsed int     `json:"supply_used"`
 	SupplyMax  int     `json:"supply_max"`
+	// Ursache: no_supply_started, supply_started_late, supply_provider_killed
+	Cause               string `json:"cause"`
+	ProviderInProgress  bool   `json:"provider_in_progress"`  // Supply-Gebäude war bei Blockbeginn schon im Bau
+	ProductionWaiting   bool   `json:"production_waiting"`    // Ressourcen für Produktion waren vorhanden
+	EstimatedSupplyLost int    `json:"estimated_supply_lost"` // geschätzte nicht produzierte Supply
+	ResourcesFloated    int    `json:"resources_floated"`     // während des Blocks angesammelte Ressourcen
 }
 
 // SupplyPoint für Timeline-Darstellung
@@ -84,6 +272,7 @@ type SupplyPoint struct {
 	SupplyUsed int     `json:"supply_used"`
 	SupplyMax  int     `json:"supply_max"`
 	IsBlocked  bool    `json:"is_blocked"`
+	IsMaxed    bool    `json:"is_maxed,omitempty"`
 }
 
 // SpendingAnalysis enthält Ressourcen-Spending Informationen
@@ -91,8 +280,38 @@ type SpendingAnalysis struct {
 	SpendingQuotient    float64         `json:"spending_quotient"`
 	Rating              string          `json:"rating"` // poor, average, good, excellent
 	AverageUnspent      ResourceValue   `json:"average_unspent"`
-	AverageIncome       ResourceValue   `json:"average_income"`
+	AverageIncome       ResourceValue   `json:"average_income"` // Sammelrate pro Minute
 	ResourceTimeline    []ResourcePoint `json:"resource_timeline"`
+	SQTimeline          []SQPoint       `json:"sq_timeline"` // gleitender SQ
+	FloatPeriods        []FloatPeriod   `json:"float_periods"`
+	FloatTime           float64         `json:"float_time"` // Sekunden mit zu vielen ungenutzten Ressourcen
+	Phases              []SpendingPhase `json:"phases,omitempty"`
+}
+
+// SQPoint ist ein Punkt der gleitenden SQ-Timeline
+type SQPoint struct {
+	Time float64 `json:"time"`
+	SQ   float64 `json:"sq"`
+}
+
+// FloatPeriod ist ein Zeitraum mit zu vielen ungenutzten Ressourcen
+type FloatPeriod struct {
+	Start        float64 `json:"start"`
+	End          float64 `json:"end"`
+	Duration     float64 `json:"duration"`
+	PeakMinerals int     `json:"peak_minerals"`
+	PeakGas      int     `json:"peak_gas"`
+}
+
+// SpendingPhase enthält den Spending Quotient einer Spielphase
+type SpendingPhase struct {
+	Phase            string        `json:"phase"` // early, mid, late
+	Start            float64       `json:"start"`
+	End              float64       `json:"end"`
+	SpendingQuotient float64       `json:"spending_quotient"`
+	Rating           string        `json:"rating"`
+	AverageUnspent   ResourceValue `json:"average_unspent"`
+	AverageIncome    float64       `json:"average_income"` // Mineralien + Gas pro Minute
 }
 
 // ResourceValue für Mineralien und Gas
@@ -111,24 +330,62 @@ type ResourcePoint struct {
 
 // APMAnalysis enthält APM-bezogene Metriken
 type APMAnalysis struct {
-	AverageAPM  float64    `json:"average_apm"`
-	PeakAPM     float64    `json:"peak_apm"`
-	EAPM        float64    `json:"eapm"` // Effective APM
-	APMTimeline []APMPoint `json:"apm_timeline"`
+	AverageAPM  float64      `json:"average_apm"`
+	PeakAPM     float64      `json:"peak_apm"`
+	EAPM        float64      `json:"eapm"` // Effective APM
+	Resolution  float64      `json:"resolution"` // Fenstergröße der Timeline in Sekunden
+	Breakdown   APMBreakdown `json:"breakdown"`  // Aktionen pro Minute nach Kategorie
+	APMTimeline []APMPoint   `json:"apm_timeline"`
+	PAC         *PACAnalysis `json:"pac,omitempty"`
+	Phases      []APMPhase   `json:"phases,omitempty"`
+}
+
+// APMPhase enthält APM und EAPM einer Spielphase
+type APMPhase struct {
+	Phase string  `json:"phase"` // early, mid, late
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	APM   float64 `json:"apm"`
+	EAPM  float64 `json:"eapm"`
+}
+
+// APMBreakdown enthält Aktionen pro Minute nach Kategorie
+type APMBreakdown struct {
+	Commands            float64 `json:"commands"`
+	Selections          float64 `json:"selections"`
+	ControlGroupRecalls float64 `json:"control_group_recalls"`
+	ControlGroupSets    float64 `json:"control_group_sets"`
+	CameraMoves         float64 `json:"camera_moves"` // zählt nicht zur APM
 }
 
 // APMPoint für Timeline
 type APMPoint struct {
-	Time float64 `json:"time"`
-	APM  float64 `json:"apm"`
+	Time      float64      `json:"time"`
+	APM       float64      `json:"apm"`
+	Breakdown APMBreakdown `json:"breakdown"`
+}
+
+// PACAnalysis enthält Perception-Action-Cycle Metriken
+type PACAnalysis struct {
+	Fixations               int     `json:"fixations"`                 // Kamera-Fixationen mit mindestens einer Aktion
+	PACPerMinute            float64 `json:"pac_per_minute"`
+	ActionsPerPAC           float64 `json:"actions_per_pac"`
+	AverageFixationDuration float64 `json:"average_fixation_duration"` // Sekunden
+	AverageReactionLatency  float64 `json:"average_reaction_latency"`  // Sekunden bis zur ersten Aktion
 }
 
 // BuildOrderItem ist ein einzelner Build Order Eintrag
 type BuildOrderItem struct {
-	Time        float64 `json:"time"`
-	Supply      int     `json:"supply"`
-	Action      string  `json:"action"`
-	UnitOrBuilding string `json:"unit_or_building"`
+	Time           float64 `json:"time"`
+	Supply         int     `json:"supply"`
+	Action         string  `json:"action"`
+	UnitOrBuilding string  `json:"unit_or_building"`
+	Status         string  `json:"status"`                   // completed, in_progress, cancelled, destroyed
+	CompletedTime  float64 `json:"completed_time,omitempty"` // Fertigstellung (Sekunden)
+	LostTime       float64 `json:"lost_time,omitempty"`      // Abbruch bzw. Zerstörung vor Fertigstellung
+	KilledBy       int     `json:"killed_by,omitempty"`      // Spieler-Slot des Zerstörers
+	Pattern        string  `json:"pattern,omitempty"`        // extractor_trick, fake_expansion
+	IsProxy        bool    `json:"is_proxy,omitempty"`       // weit entfernt von eigenen Basen gebaut
 }
 
 // InjectAnalysis für Zerg
@@ -146,6 +403,154 @@ type InjectPoint struct {
 	Injected   bool    `json:"injected"`
 }
 
+// LarvaAnalysis für Zerg Larven- und Queen-Nutzung
+type LarvaAnalysis struct {
+	LarvaSpawned    int             `json:"larva_spawned"`
+	LarvaUsed       int             `json:"larva_used"`
+	ConversionRate  float64         `json:"conversion_rate"`   // Prozent der Larven, die zu Einheiten wurden
+	UnusedLarvaTime float64         `json:"unused_larva_time"` // Larven-Sekunden ungenutzt
+	BankedTime      float64         `json:"banked_time"`       // Hatchery-Sekunden mit 3+ Larven
+	BankedShare     float64         `json:"banked_share"`      // Prozent der Hatchery-Zeit mit 3+ Larven
+	InjectPops      int             `json:"inject_pops"`
+	Hatcheries      []HatcheryLarva `json:"hatcheries"`
+	QueensBuilt     int             `json:"queens_built"`
+	PeakQueens      int             `json:"peak_queens"`
+	InjectQueens    int             `json:"inject_queens"`
+	CreepQueens     int             `json:"creep_queens"`
+	DefenseQueens   int             `json:"defense_queens"`
+	Queens          []QueenUsage    `json:"queens"`
+}
+
+// HatcheryLarva beschreibt die Larven einer Hatchery
+type HatcheryLarva struct {
+	HatcheryID   int           `json:"hatchery_id"`
+	X            int           `json:"x"`
+	Y            int           `json:"y"`
+	DoneTime     float64       `json:"done_time"`
+	LarvaSpawned int           `json:"larva_spawned"`
+	InjectPops   int           `json:"inject_pops"`
+	BankedTime   float64       `json:"banked_time"`
+	AverageLarva float64       `json:"average_larva"`
+	Timeline     []LarvaSample `json:"timeline"`
+}
+
+// LarvaSample ist die Larvenanzahl einer Hatchery zu einem Zeitpunkt
+type LarvaSample struct {
+	Time  float64 `json:"time"`
+	Count int     `json:"count"`
+}
+
+// QueenUsage beschreibt, wofür eine Queen eingesetzt wurde
+type QueenUsage struct {
+	ID          int     `json:"id"`
+	BornTime    float64 `json:"born_time"`
+	DiedTime    float64 `json:"died_time,omitempty"`
+	Injects     int     `json:"injects"`
+	CreepTumors int     `json:"creep_tumors"`
+	Role        string  `json:"role"` // inject, creep, defense
+}
+
+// CreepAnalysis für Zerg Creep-Spread
+type CreepAnalysis struct {
+	TotalTumors      int          `json:"total_tumors"`
+	QueenTumors      int          `json:"queen_tumors"`
+	SpreadTumors     int          `json:"spread_tumors"`   // von Tumoren weitergesetzt
+	MaxChainDepth    int          `json:"max_chain_depth"` // längste Tumor-Kette
+	FirstTumorTime   float64      `json:"first_tumor_time"`
+	AverageSpreadGap float64      `json:"average_spread_gap"` // Sekunden zwischen Tumoren
+	LongestSpreadGap float64      `json:"longest_spread_gap"`
+	SpreadGaps       []CreepGap   `json:"spread_gaps"`
+	FinalCoverage    float64      `json:"final_coverage"` // Prozent der Map
+	PeakCoverage     float64      `json:"peak_coverage"`
+	Timeline         []CreepPoint `json:"timeline"`
+	Phases           []CreepPhase `json:"phases"`
+}
+
+// CreepPoint für Timeline
+type CreepPoint struct {
+	Time         float64 `json:"time"`
+	ActiveTumors int     `json:"active_tumors"`
+	TotalTumors  int     `json:"total_tumors"`
+	Coverage     float64 `json:"coverage"` // Prozent der Map
+}
+
+// CreepGap ist eine Pause ohne neue Creep-Tumore
+type CreepGap struct {
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+	Duration float64 `json:"duration"`
+}
+
+// CreepPhase fasst den Creep-Spread einer Spielphase zusammen
+type CreepPhase struct {
+	Phase           string  `json:"phase"` // early, mid, late
+	Start           float64 `json:"start"`
+	End             float64 `json:"end"`
+	TumorsPlaced    int     `json:"tumors_placed"`
+	AverageCoverage float64 `json:"average_coverage"`
+	EndCoverage     float64 `json:"end_coverage"`
+}
+
+// ScoutingAnalysis beschreibt, wann und womit der Gegner gescoutet wurde
+type ScoutingAnalysis struct {
+	OpponentRace       string           `json:"opponent_race"`
+	FirstScoutTime     float64          `json:"first_scout_time"`
+	FirstScoutMethod   string           `json:"first_scout_method"`
+	Events             []ScoutEvent     `json:"events"`
+	Scans              int              `json:"scans"`
+	Hallucinations     int              `json:"hallucinations"`
+	OverlordPlacements int              `json:"overlord_placements"`
+	ObserverPlacements int              `json:"observer_placements"`
+	Windows            []ScoutingWindow `json:"windows"`
+	MissedWindows      int              `json:"missed_windows"`
+}
+
+// ScoutEvent ist ein einzelner Scout
+type ScoutEvent struct {
+	Time     float64  `json:"time"`
+	Method   string   `json:"method"` // worker, unit, overlord, observer, scan, hallucination
+	UnitType string   `json:"unit_type,omitempty"`
+	Target   string   `json:"target"` // main, natural, map
+	X        int      `json:"x"`
+	Y        int      `json:"y"`
+	InfoSeen []string `json:"info_seen"` // gegnerische Gebäude in Sichtweite
+}
+
+// ScoutingWindow ist ein wichtiges Zeitfenster, in dem gescoutet werden sollte
+type ScoutingWindow struct {
+	Key            string  `json:"key"` // early_zerg, early_terran, early_protoss, tech, late
+	Name           string  `json:"name"`
+	Start          float64 `json:"start"`
+	End            float64 `json:"end"`
+	Description    string  `json:"description"`
+	Scouted        bool    `json:"scouted"`
+	FirstScoutTime float64 `json:"first_scout_time,omitempty"`
+}
+
+// HarassmentAnalysis beschreibt gegnerischen Harass an den eigenen Basen
+type HarassmentAnalysis struct {
+	Incidents           []HarassmentIncident `json:"incidents"`
+	TotalWorkersLost    int                  `json:"total_workers_lost"`
+	TotalMiningTimeLost float64              `json:"total_mining_time_lost"` // Worker-Sekunden
+	AverageReactionTime float64              `json:"average_reaction_time"`  // Sekunden
+	UnansweredIncidents int                  `json:"unanswered_incidents"`
+}
+
+// HarassmentIncident ist ein einzelner Harass an einer Basis
+type HarassmentIncident struct {
+	Start                 float64  `json:"start"`
+	End                   float64  `json:"end"`
+	Base                  string   `json:"base"` // main, natural, base
+	X                     int      `json:"x"`
+	Y                     int      `json:"y"`
+	AttackerTypes         []string `json:"attacker_types"`
+	WorkersLost           int      `json:"workers_lost"`
+	Reacted               bool     `json:"reacted"`
+	ReactionTime          float64  `json:"reaction_time"` // Sekunden bis zur ersten Reaktion
+	MiningTimeLost        float64  `json:"mining_time_lost"`
+	EstimatedMineralsLost int      `json:"estimated_minerals_lost"` // inkl. Worker-Kosten
+}
+
 // ProductionAnalysis für Produktionsgebäude
 type ProductionAnalysis struct {
 	Efficiency       float64              `json:"efficiency"`
@@ -167,6 +572,496 @@ type ArmyAnalysis struct {
 	PeakArmyValue    int              `json:"peak_army_value"`
 	ArmyTimeline     []ArmyPoint      `json:"army_timeline"`
 	UnitComposition  []UnitCount      `json:"unit_composition"`
+	Phases           []ArmyPhase      `json:"phases,omitempty"`
+}
+
+// BenchmarkAnalysis vergleicht den Spielstand zu festen Zeitpunkten mit Zielwerten
+type BenchmarkAnalysis struct {
+	League    string              `json:"league"`  // Liga für die Zielwerte, leer = Standard
+	Matchup   string              `json:"matchup"` // z.B. ZvT
+	Snapshots []BenchmarkSnapshot `json:"snapshots"`
+}
+
+// BenchmarkSnapshot ist der Spielstand zu einem Benchmark-Zeitpunkt
+type BenchmarkSnapshot struct {
+	Time        float64               `json:"time"`
+	Label       string                `json:"label"`   // z.B. "6:00"
+	Reached     bool                  `json:"reached"` // Spiel dauerte bis zu diesem Zeitpunkt
+	Values      BenchmarkValues       `json:"values"`
+	Target      *BenchmarkValues      `json:"target,omitempty"`
+	Comparisons []BenchmarkComparison `json:"comparisons,omitempty"`
+}
+
+// BenchmarkValues sind die Kennzahlen eines Snapshots; bei Zielwerten bedeutet 0 "kein Ziel"
+type BenchmarkValues struct {
+	Supply          int     `json:"supply"`
+	Workers         int     `json:"workers"`
+	Bases           int     `json:"bases"`
+	ArmyValue       int     `json:"army_value"` // Mineralien + Gas
+	UpgradesStarted int     `json:"upgrades_started"`
+	Income          float64 `json:"income"` // Mineralien + Gas pro Minute
+}
+
+// ByMetric gibt die Werte unter den Benchmark-Metriknamen zurück
+func (bv BenchmarkValues) ByMetric() map[string]float64 {
+	return map[string]float64{
+		"supply":     float64(bv.Supply),
+		"workers":    float64(bv.Workers),
+		"bases":      float64(bv.Bases),
+		"army_value": float64(bv.ArmyValue),
+		"upgrades":   float64(bv.UpgradesStarted),
+		"income":     bv.Income,
+	}
+}
+
+// BenchmarkComparison vergleicht einen Wert mit seinem Zielwert
+type BenchmarkComparison struct {
+	Metric  string  `json:"metric"`
+	Actual  float64 `json:"actual"`
+	Target  float64 `json:"target"`
+	Percent float64 `json:"percent"` // Ist-Wert in Prozent des Ziels
+	Met     bool    `json:"met"`
+}
+
+// BenchmarkTarget sind konfigurierbare Zielwerte für einen Zeitpunkt
+type BenchmarkTarget struct {
+	League  string          `json:"league"`  // z.B. "gold", leer = alle Ligen
+	Matchup string          `json:"matchup"` // z.B. "ZvT" oder "Zv*", leer = alle
+	Time    float64         `json:"time"`    // Sekunden
+	Values  BenchmarkValues `json:"values"`
+}
+
+// Compare vergleicht die Ist-Werte mit den gesetzten Zielwerten
+func (bv BenchmarkValues) Compare(target BenchmarkValues) []BenchmarkComparison {
+	actual := bv.ByMetric()
+	var comparisons []BenchmarkComparison
+	for _, metric := range BenchmarkMetrics {
+		goal := target.ByMetric()[metric]
+		if goal <= 0 {
+			continue
+		}
+		comparisons = append(comparisons, BenchmarkComparison{
+			Metric:  metric,
+			Actual:  actual[metric],
+			Target:  goal,
+			Percent: actual[metric] / goal * 100,
+			Met:     actual[metric] >= goal,
+		})
+	}
+	return comparisons
+}
+
+// WinProbability ist der Verlauf der geschätzten Siegwahrscheinlichkeit eines Spielers
+type WinProbability struct {
+	Timeline         []WinProbabilityPoint `json:"timeline"`
+	TurningPoints    []TurningPoint        `json:"turning_points"`
+	DecidedAt        float64               `json:"decided_at"` // ab hier blieb die Wahrscheinlichkeit auf der Seite des Endstands
+	FinalProbability float64               `json:"final_probability"`
+	ModelFitted      bool                  `json:"model_fitted"` // false = Standard-Koeffizienten
+}
+
+// WinProbabilityPoint ist ein Punkt des Verlaufs mit den Differenzen zum Gegner
+type WinProbabilityPoint struct {
+	Time        float64 `json:"time"`
+	Probability float64 `json:"probability"` // 0 bis 1
+	ArmyLead    int     `json:"army_lead"`   // Armeewert-Vorsprung (Mineralien + Gas)
+	LostDiff    int     `json:"lost_diff"`   // vom Gegner mehr verlorene Ressourcen
+	WorkerDiff  int     `json:"worker_diff"`
+	BaseDiff    int     `json:"base_diff"`
+	SupplyDiff  int     `json:"supply_diff"`
+}
+
+// TurningPoint ist ein Zeitraum, in dem die Siegwahrscheinlichkeit stark gekippt ist
+type TurningPoint struct {
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Before      float64 `json:"before"`
+	After       float64 `json:"after"`
+	Change      float64 `json:"change"`
+	Factor      string  `json:"factor"` // army_lead, lost_diff, worker_diff, base_diff, supply_diff
+	Description string  `json:"description"`
+}
+
+// WinProbabilityModel enthält die Koeffizienten des logistischen Modells
+type WinProbabilityModel struct {
+	Intercept  float64   `json:"intercept"`
+	ArmyLead   float64   `json:"army_lead"`   // pro 1000 Ressourcen Armeewert-Vorsprung
+	LostDiff   float64   `json:"lost_diff"`   // pro 1000 Ressourcen, die der Gegner mehr verloren hat
+	WorkerDiff float64   `json:"worker_diff"` // pro 10 Worker Vorsprung
+	BaseDiff   float64   `json:"base_diff"`   // pro Basis Vorsprung
+	SupplyDiff float64   `json:"supply_diff"` // pro 10 Supply Vorsprung
+	Games      int       `json:"games,omitempty"`
+	Samples    int       `json:"samples,omitempty"`
+	Accuracy   float64   `json:"accuracy,omitempty"` // Trefferquote auf den Trainingsdaten in Prozent
+	FittedAt   time.Time `json:"fitted_at,omitempty"`
+}
+
+// GameStory ist der chronologische Spielverlauf ("Game Story") eines Replays
+type GameStory struct {
+	Map      string        `json:"map"`
+	Duration float64       `json:"duration"` // Echtzeitsekunden
+	Players  []StoryPlayer `json:"players"`
+	Entries  []StoryEntry  `json:"entries"`
+}
+
+// StoryPlayer ist ein Spieler im Spielverlauf
+type StoryPlayer struct {
+	PlayerSlot int    `json:"player_slot"`
+	Name       string `json:"name"`
+	Race       string `json:"race"`
+	Result     string `json:"result"`
+}
+
+// StoryEntry ist ein Ereignis im Spielverlauf
+type StoryEntry struct {
+	Time       float64 `json:"time"`  // Echtzeitsekunden
+	Label      string  `json:"label"` // m:ss
+	Kind       string  `json:"kind"`  // build, unit, expansion, tech, upgrade, supply_block, engagement, harassment, chat, leave, game_end
+	Actor      string  `json:"actor"` // Spielername, leer bei Ereignissen beider Seiten
+	ActorSlot  int     `json:"actor_slot,omitempty"`
+	Importance int     `json:"importance"` // 1 = Detail, 2 = wichtig, 3 = Schlüsselmoment
+	Title      string  `json:"title"`
+	Detail     string  `json:"detail,omitempty"`
+}
+
+// ReplayComparison stellt zwei Spiele aus Sicht je eines Spielers gegenüber (B im Vergleich zu A)
+type ReplayComparison struct {
+	A            ComparisonSide        `json:"a"`
+	B            ComparisonSide        `json:"b"`
+	BuildOrder   []BuildStepComparison `json:"build_order"`
+	BuildSummary BuildOrderDiffSummary `json:"build_summary"`
+	Benchmarks   []BenchmarkDelta      `json:"benchmarks"`
+	Timelines    []ComparisonTimeline  `json:"timelines"`
+}
+
+// ComparisonSide ist ein Spiel und Spieler im Vergleich
+type ComparisonSide struct {
+	ReplayID int64     `json:"replay_id"`
+	PlayerID int64     `json:"player_id"`
+	Name     string    `json:"name"`
+	Race     string    `json:"race"`
+	Result   string    `json:"result"`
+	Matchup  string    `json:"matchup,omitempty"`
+	Map      string    `json:"map"`
+	Duration int       `json:"duration"` // in Sekunden
+	PlayedAt time.Time `json:"played_at"`
+}
+
+// BuildStepComparison ist ein ausgerichteter Schritt beider Build Orders
+type BuildStepComparison struct {
+	Action         string   `json:"action"`
+	UnitOrBuilding string   `json:"unit_or_building"`
+	Status         string   `json:"status"`           // matched, only_a, only_b
+	TimeA          *float64 `json:"time_a,omitempty"` // Sekunden
+	TimeB          *float64 `json:"time_b,omitempty"`
+	SupplyA        int      `json:"supply_a,omitempty"`
+	SupplyB        int      `json:"supply_b,omitempty"`
+	Delta          *float64 `json:"delta,omitempty"` // TimeB - TimeA, negativ = B früher
+}
+
+// BuildOrderDiffSummary fasst den Build-Order-Vergleich zusammen
+type BuildOrderDiffSummary struct {
+	Matched         int      `json:"matched"`
+	OnlyA           int      `json:"only_a"`
+	OnlyB           int      `json:"only_b"`
+	AverageDelta    float64  `json:"average_delta"`              // mittlere Zeitdifferenz (B - A) gemeinsamer Schritte
+	FirstDivergence *float64 `json:"first_divergence,omitempty"` // erster Schritt, der nur in einem Spiel vorkommt
+}
+
+// BenchmarkDelta vergleicht beide Spiele zu einem Benchmark-Zeitpunkt
+type BenchmarkDelta struct {
+	Time     float64       `json:"time"`
+	Label    string        `json:"label"`
+	ReachedA bool          `json:"reached_a"`
+	ReachedB bool          `json:"reached_b"`
+	Metrics  []MetricDelta `json:"metrics,omitempty"` // nur wenn beide Spiele den Zeitpunkt erreicht haben
+}
+
+// MetricDelta ist die Differenz eines Werts zwischen beiden Spielen
+type MetricDelta struct {
+	Metric string  `json:"metric"`
+	A      float64 `json:"a"`
+	B      float64 `json:"b"`
+	Delta  float64 `json:"delta"` // B - A
+}
+
+// ComparisonTimeline legt den Verlauf einer Metrik beider Spiele übereinander
+type ComparisonTimeline struct {
+	Metric string            `json:"metric"` // supply, army_value, minerals, gas, income
+	Points []ComparisonPoint `json:"points"`
+}
+
+// ComparisonPoint ist ein gemeinsamer Zeitpunkt beider Verläufe
+type ComparisonPoint struct {
+	Time float64  `json:"time"`
+	A    *float64 `json:"a,omitempty"` // leer, wenn das Spiel schon vorbei ist
+	B    *float64 `json:"b,omitempty"`
+}
+
+// ReferenceReplay ist ein von Admins oder Coaches markiertes Referenzspiel eines Spielers (z.B. Profi-Replay)
+type ReferenceReplay struct {
+	ID          int64     `json:"id"`
+	ReplayID    int64     `json:"replay_id"`
+	PlayerID    int64     `json:"player_id"`
+	PlayerName  string    `json:"player_name"`     // Name im Replay
+	Player      string    `json:"player"`          // Tag, z.B. Profi-Name; Standard ist der Name im Replay
+	Matchup     string    `json:"matchup"`         // z.B. PvZ aus Sicht des Referenzspielers
+	Build       string    `json:"build,omitempty"` // Strategie-ID aus der Wissensbasis, z.B. protoss_stargate
+	Map         string    `json:"map"`
+	GameVersion string    `json:"game_version"`
+	CreatedBy   int64     `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreateReferenceRequest markiert ein Spiel als Referenz; ohne Build wird er erkannt
+type CreateReferenceRequest struct {
+	ReplayID int64  `json:"replay_id"`
+	PlayerID int64  `json:"player_id"`
+	Player   string `json:"player,omitempty"`
+	Build    string `json:"build,omitempty"`
+}
+
+// ReferenceComparison vergleicht ein Spiel mit dem passendsten Referenz-Replay (A = eigenes Spiel, B = Referenz)
+type ReferenceComparison struct {
+	Reference  ReferenceReplay   `json:"reference"`
+	Build      string            `json:"build,omitempty"` // erkannter eigener Build
+	BuildMatch bool              `json:"build_match"`     // Referenz hat denselben Build
+	Similarity float64           `json:"similarity"`      // Build-Ähnlichkeit wie bei ähnlichen Spielen (0 bis 1)
+	Gaps       []ReferenceGap    `json:"gaps"`
+	Comparison *ReplayComparison `json:"comparison"`
+}
+
+// ReferenceGap ist ein Zeitraum, in dem eine Kurve deutlich hinter der Referenz liegt
+type ReferenceGap struct {
+	Metric         string  `json:"metric"` // supply, army_value, income
+	Start          float64 `json:"start"`
+	End            float64 `json:"end"`
+	PeakTime       float64 `json:"peak_time"` // Zeitpunkt des größten Rückstands
+	Value          float64 `json:"value"`
+	ReferenceValue float64 `json:"reference_value"`
+	Deficit        float64 `json:"deficit"` // ReferenceValue - Value
+	Percent        float64 `json:"percent"` // Rückstand in Prozent des Referenzwerts
+}
+
+// GameSignature ist der Eintrag eines analysierten Spielers im Ähnlichkeitsindex
+type GameSignature struct {
+	ReplayID      int64     `json:"replay_id"`
+	PlayerID      int64     `json:"player_id"`
+	Name          string    `json:"name"`
+	Race          string    `json:"race"`
+	Result        string    `json:"result"`
+	OpponentID    int64     `json:"opponent_id,omitempty"`
+	Opponent      string    `json:"opponent,omitempty"`
+	OpponentRace  string    `json:"opponent_race,omitempty"`
+	Matchup       string    `json:"matchup"`
+	Map           string    `json:"map"`
+	Duration      int       `json:"duration"` // in Sekunden
+	PlayedAt      time.Time `json:"played_at"`
+	Build         []string  `json:"build"`          // Build-Schritte ohne Arbeiter
+	OpponentBuild []string  `json:"opponent_build"` // Build-Schritte des Gegners ohne Arbeiter
+	Reference     string    `json:"reference,omitempty"` // Spieler-Tag, wenn das Spiel ein Referenz-Replay ist
+}
+
+// SimilarGame ist ein ähnliches Spiel mit Ähnlichkeitswert und seinen Anteilen (jeweils 0 bis 1)
+type SimilarGame struct {
+	ReplayID     int64             `json:"replay_id"`
+	PlayerID     int64             `json:"player_id"`
+	Name         string            `json:"name"`
+	Race         string            `json:"race"`
+	Result       string            `json:"result"`
+	Opponent     string            `json:"opponent,omitempty"`
+	OpponentRace string            `json:"opponent_race,omitempty"`
+	Matchup      string            `json:"matchup"`
+	Map          string            `json:"map"`
+	Duration     int               `json:"duration"`
+	PlayedAt     time.Time         `json:"played_at"`
+	IsReference  bool              `json:"is_reference"`
+	Reference    string            `json:"reference,omitempty"`
+	Score        float64           `json:"score"`
+	EditDistance int               `json:"edit_distance"` // Build-Schritte, die eingefügt, gelöscht oder ersetzt werden müssten
+	Components   SimilarityScores  `json:"components"`
+}
+
+// SimilarityScores sind die Anteile des Ähnlichkeitswerts
+type SimilarityScores struct {
+	Build         float64 `json:"build"`
+	OpponentBuild float64 `json:"opponent_build"`
+	Matchup       float64 `json:"matchup"`
+	Map           float64 `json:"map"`
+	Length        float64 `json:"length"`
+}
+
+// OpponentDossier fasst alle gespeicherten Spiele eines Gegners über alle Replays der Datenbank zusammen
+type OpponentDossier struct {
+	Player          Player            `json:"player"`
+	Names           []string          `json:"names"` // alle verwendeten Namen, zuletzt verwendeter zuerst
+	Games           int               `json:"games"`
+	HeadToHead      HeadToHeadRecord  `json:"head_to_head"`
+	Races           []OpponentShare   `json:"races"`
+	Openings        []OpponentOpening `json:"openings"`
+	AnalyzedGames   int               `json:"analyzed_games"`   // Spiele mit Build Order, Grundlage der Eröffnungen
+	AverageDuration int               `json:"average_duration"` // in Sekunden
+	Maps            []OpponentShare   `json:"maps"`             // Lieblingskarten, meistgespielte zuerst
+	FirstSeen       time.Time         `json:"first_seen"`
+	LastSeen        time.Time         `json:"last_seen"`
+}
+
+// HeadToHeadRecord ist die eigene Bilanz gegen einen Gegner (Spiele gegen einen eigenen Spieler)
+type HeadToHeadRecord struct {
+	Games   int            `json:"games"`
+	Wins    int            `json:"wins"`
+	Losses  int            `json:"losses"`
+	WinRate float64        `json:"win_rate"` // Prozent der entschiedenen Spiele
+	Recent  []OpponentGame `json:"recent"`   // letzte direkte Duelle, neuestes zuerst
+}
+
+// OpponentShare ist der Anteil einer Rasse oder Karte an den Spielen eines Gegners
+type OpponentShare struct {
+	Name    string  `json:"name"`
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"` // Siege des Gegners
+	Percent float64 `json:"percent"`
+}
+
+// OpponentOpening ist eine beim Gegner erkannte Eröffnung
+type OpponentOpening struct {
+	Build   string  `json:"build"`
+	Name    string  `json:"name"`
+	Matchup string  `json:"matchup"` // aus Sicht des Gegners
+	Games   int     `json:"games"`
+	Percent float64 `json:"percent"` // Anteil an den analysierten Spielen dieses Matchups
+}
+
+// OpponentGame ist ein gespeichertes Spiel eines Gegners, Ergebnis aus seiner Sicht
+type OpponentGame struct {
+	ReplayID     int64     `json:"replay_id"`
+	Map          string    `json:"map"`
+	Duration     int       `json:"duration"` // in Sekunden
+	GameVersion  string    `json:"game_version"`
+	PlayedAt     time.Time `json:"played_at"`
+	PlayerID     int64     `json:"player_id"`
+	Name         string    `json:"name"`
+	Race         string    `json:"race"`
+	Result       string    `json:"result"`
+	OpponentID   int64     `json:"opponent_id,omitempty"` // Gegenspieler in diesem Spiel
+	Opponent     string    `json:"opponent,omitempty"`
+	OpponentRace string    `json:"opponent_race,omitempty"`
+	HeadToHead   bool      `json:"head_to_head"` // eigenes Replay, Gegenspieler ist der verknüpfte Spieler
+	Opening      string    `json:"opening,omitempty"` // erkannte Eröffnung (Strategie-ID)
+	OpeningName  string    `json:"opening_name,omitempty"`
+
+	Own        bool             `json:"-"` // Spiel aus einem Replay des Benutzers
+	BuildOrder []BuildOrderItem `json:"-"` // Build Order des Gegners aus der gespeicherten Analyse
+}
+
+// GameState ist der aus dem Replay rekonstruierte Spielstand beider Spieler zu einem Zeitpunkt
+type GameState struct {
+	Time    float64       `json:"time"`  // Echtzeitsekunden
+	Label   string        `json:"label"` // m:ss
+	Loop    int           `json:"loop"`
+	Players []PlayerState `json:"players"`
+}
+
+// PlayerState ist der Stand eines Spielers zu einem Zeitpunkt
+type PlayerState struct {
+	PlayerSlot         int               `json:"player_slot"`
+	Name               string            `json:"name"`
+	Race               string            `json:"race"`
+	Supply             int               `json:"supply"`
+	SupplyCap          int               `json:"supply_cap"`
+	Workers            int               `json:"workers"`
+	Minerals           int               `json:"minerals"`       // Bank
+	Gas                int               `json:"gas"`            // Bank
+	MineralIncome      int               `json:"mineral_income"` // pro Minute
+	GasIncome          int               `json:"gas_income"`     // pro Minute
+	ArmyValue          int               `json:"army_value"`
+	Units              []UnitTypeCount   `json:"units"`
+	Structures         []UnitTypeCount   `json:"structures"`
+	UpgradesDone       []string          `json:"upgrades_done"`
+	UpgradesInProgress []UpgradeProgress `json:"upgrades_in_progress"`
+}
+
+// UnitTypeCount zählt Einheiten oder Gebäude eines Typs
+type UnitTypeCount struct {
+	UnitType   string `json:"unit_type"`
+	Count      int    `json:"count"`       // fertig
+	InProgress int    `json:"in_progress"` // im Bau bzw. Warp-In
+}
+
+// UpgradeProgress ist ein laufendes Upgrade
+// Der Start stammt aus dem Forschungsbefehl, bei Spielende unfertige Forschungen sind enthalten
+type UpgradeProgress struct {
+	Name      string  `json:"name"`                // leer, wenn die Forschung bei Spielende nicht fertig war
+	Structure string  `json:"structure,omitempty"` // forschendes Gebäude
+	Started   float64 `json:"started"`
+	Completes float64 `json:"completes"` // 0 = bei Spielende nicht fertig
+	Progress  float64 `json:"progress"`  // Prozent, 0 wenn die Dauer unbekannt ist
+}
+
+// ArmyPhase enthält die Armeegröße einer Spielphase
+type ArmyPhase struct {
+	Phase            string  `json:"phase"` // early, mid, late
+	Start            float64 `json:"start"`
+	End              float64 `json:"end"`
+	PeakArmyValue    int     `json:"peak_army_value"`
+	AverageArmyValue float64 `json:"average_army_value"`
+	UnitsLost        int     `json:"units_lost"`
+}
+
+// HotkeyAnalysis für Control-Group- und Hotkey-Nutzung
+type HotkeyAnalysis struct {
+	ControlGroups          []ControlGroupUsage `json:"control_groups"`
+	TotalSets              int                 `json:"total_sets"`
+	TotalRecalls           int                 `json:"total_recalls"`
+	RecallsPerMinute       float64             `json:"recalls_per_minute"`
+	CameraSaves            int                 `json:"camera_saves"`
+	CameraHotkeyRecalls    int                 `json:"camera_hotkey_recalls"`
+	CameraHotkeysPerMinute float64             `json:"camera_hotkeys_per_minute"`
+	ProductionCommands     int                 `json:"production_commands"`
+	ProductionViaHotkey    int                 `json:"production_via_hotkey"`
+	ProductionHotkeyShare  float64             `json:"production_hotkey_share"` // Prozent
+}
+
+// ControlGroupUsage beschreibt die Nutzung einer Control Group
+type ControlGroupUsage struct {
+	Index            int      `json:"index"` // Hotkey-Taste (1-9, 0)
+	Sets             int      `json:"sets"`
+	Recalls          int      `json:"recalls"`
+	RecallsPerMinute float64  `json:"recalls_per_minute"`
+	Role             string   `json:"role"` // town_hall, production, tech, workers, army, mixed, empty
+	UnitTypes        []string `json:"unit_types"`
+}
+
+// AttentionAnalysis beschreibt, wohin der Spieler seine Kamera richtet
+type AttentionAnalysis struct {
+	TimeAtMain      float64        `json:"time_at_main"` // Sekunden
+	TimeAtNatural   float64        `json:"time_at_natural"`
+	TimeAtArmy      float64        `json:"time_at_army"`
+	TimeElsewhere   float64        `json:"time_elsewhere"`
+	MainShare       float64        `json:"main_share"` // Prozent
+	NaturalShare    float64        `json:"natural_share"`
+	ArmyShare       float64        `json:"army_share"`
+	ElsewhereShare  float64        `json:"elsewhere_share"`
+	AwayGaps        []AttentionGap `json:"away_gaps"` // Abwesend während Worker-Produktionslücken
+	AwayGapTime     float64        `json:"away_gap_time"`
+	HeatmapCellSize int            `json:"heatmap_cell_size"` // Map-Zellen pro Kachel
+	Heatmap         []HeatmapCell  `json:"heatmap"`
+}
+
+// AttentionGap ist eine Worker-Produktionslücke, während der die Kamera nicht an den Basen war
+type AttentionGap struct {
+	Start     float64 `json:"start"`
+	End       float64 `json:"end"`
+	Duration  float64 `json:"duration"`
+	AwayShare float64 `json:"away_share"` // Prozent der Lücke außerhalb der Basen
+	Location  string  `json:"location"`   // Meistbeobachteter Bereich: army, elsewhere
+}
+
+// HeatmapCell ist eine Kachel der Bildschirmzeit-Heatmap
+type HeatmapCell struct {
+	X       int     `json:"x"`
+	Y       int     `json:"y"`
+	Seconds float64 `json:"seconds"`
 }
 
 // ArmyPoint für Timeline
@@ -191,6 +1086,50 @@ type Suggestion struct {
 	Description string  `json:"description"`
 	Timestamp   float64 `json:"timestamp,omitempty"` // optionaler Zeitpunkt
 	TargetValue string  `json:"target_value,omitempty"` // Zielwert
+	RuleID      string  `json:"rule_id,omitempty"` // Regel, die den Vorschlag erzeugt hat
+}
+
+// SuggestionRuleSet ist eine versionierte Sammlung von Vorschlagsregeln
+type SuggestionRuleSet struct {
+	Version int              `json:"version"`
+	Rules   []SuggestionRule `json:"rules"`
+}
+
+// SuggestionRule erzeugt Vorschläge, wenn alle Bedingungen erfüllt sind
+// Metrik-Pfade beziehen sich auf das JSON der AnalysisData (z.B. "supply_analysis.block_percentage"),
+// bei for_each zusätzlich auf "item", "prev" und "parent" des aktuellen Listenelements
+type SuggestionRule struct {
+	ID          string          `json:"id"`
+	Disabled    bool            `json:"disabled,omitempty"`
+	Group       string          `json:"group,omitempty"`    // nur die erste zutreffende Regel einer Gruppe greift
+	ForEach     string          `json:"for_each,omitempty"` // Pfad zu einer Liste, ein Vorschlag pro passendem Element
+	OrderBy     string          `json:"order_by,omitempty"` // Feld der Elemente, "-" für absteigend
+	Limit       int             `json:"limit,omitempty"`    // maximale Anzahl Vorschläge (0 = unbegrenzt)
+	Conditions  []RuleCondition `json:"conditions"`
+	Priority    string          `json:"priority"` // high, medium, low
+	Category    string          `json:"category"`
+	Title       string          `json:"title,omitempty"`   // Template, leer = Katalog-Text suggestion.<id>.title
+	Message     string          `json:"message,omitempty"` // Template, leer = Katalog-Text suggestion.<id>.message
+	TargetValue string          `json:"target_value,omitempty"` // Template, leer = Katalog-Text suggestion.<id>.target_value
+	Timestamp   string          `json:"timestamp,omitempty"`    // Metrik-Pfad
+	Translations map[string]RuleText `json:"translations,omitempty"` // Texte je Sprache, z.B. "en"
+}
+
+// RuleText sind die Templates einer Regel in einer Sprache
+type RuleText struct {
+	Title       string `json:"title,omitempty"`
+	Message     string `json:"message,omitempty"`
+	TargetValue string `json:"target_value,omitempty"`
+}
+
+// RuleCondition vergleicht eine Metrik mit einem Schwellwert
+// Mit Any ist die Bedingung erfüllt, sobald eine der Unterbedingungen zutrifft
+type RuleCondition struct {
+	Metric     string          `json:"metric,omitempty"`
+	RelativeTo string          `json:"relative_to,omitempty"` // Metrik wird durch diesen Wert geteilt
+	Operator   string          `json:"operator,omitempty"`    // <, <=, >, >=, ==, !=
+	Value      interface{}     `json:"value"`
+	Any        []RuleCondition `json:"any,omitempty"`
 }
 
 // TrendData für Verbesserungstrends
@@ -209,21 +1148,49 @@ type TrendPoint struct {
 }
 
 // StrategicAnalysis enthält die vollständige strategische Spielanalyse
+// Alle Vergleiche sind aus Sicht von Player (des anfragenden Benutzers), unabhängig vom Ausgang
 type StrategicAnalysis struct {
-	Winner            string                   `json:"winner"`
+	Winner            string                   `json:"winner"` // leer, wenn der Ausgang nicht bestimmbar ist
 	Loser             string                   `json:"loser"`
 	WinnerRace        string                   `json:"winner_race"`
 	LoserRace         string                   `json:"loser_race"`
+	Player            string                   `json:"player"`
+	Opponent          string                   `json:"opponent"`
+	PlayerRace        string                   `json:"player_race"`
+	OpponentRace      string                   `json:"opponent_race"`
+	Result            string                   `json:"result"` // Win, Loss oder Undecided aus Sicht von Player
+	ResultInference   *ResultInference         `json:"result_inference,omitempty"` // nur wenn das Replay kein Ergebnis enthält
 	Matchup           string                   `json:"matchup"`
 	MetricsComparison []MetricComparison       `json:"metrics_comparison"`
 	SupplyBlocks      []SupplyBlockSummary     `json:"supply_blocks"`
 	CriticalMoments   []CriticalMoment         `json:"critical_moments"`
-	Problems          []IdentifiedProblem      `json:"problems"`
+	Problems          []IdentifiedProblem      `json:"problems"`                      // Fehler, die zur Niederlage beitrugen
+	UnpunishedMistakes []IdentifiedProblem     `json:"unpunished_mistakes,omitempty"` // Fehler trotz Sieg
+	Strengths         []StrategicInsight       `json:"strengths"`
+	CloseCalls        []StrategicInsight       `json:"close_calls"`
+	ProxyLosses       []ProxyLoss              `json:"proxy_losses,omitempty"`
 	MatchupTips       *MatchupTips             `json:"matchup_tips"`
 	ImprovementSteps  []ImprovementStep        `json:"improvement_steps"`
+	WinProbability    *WinProbability          `json:"win_probability,omitempty"` // aus Sicht von Player
 	Summary           string                   `json:"summary"`
 }
 
+// ResultInference ist das geschätzte Ergebnis eines Spiels ohne Ergebnis im Replay
+type ResultInference struct {
+	Result      string  `json:"result"`     // Win, Loss oder Undecided aus Sicht des Spielers
+	Reason      string  `json:"reason"`     // player_left, opponent_left, resources, unknown
+	Confidence  float64 `json:"confidence"` // 0 bis 1
+	Description string  `json:"description"`
+}
+
+// StrategicInsight ist eine Stärke oder ein knapper Moment aus Sicht des Spielers
+type StrategicInsight struct {
+	Key         string  `json:"key"` // z.B. spending, supply, apm, army, trades, harass, comeback, lead_lost, bad_trade
+	Time        float64 `json:"time,omitempty"` // Sekunden, nur bei zeitbezogenen Einträgen
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+}
+
 // MetricComparison vergleicht eine Metrik zwischen Spielern
 type MetricComparison struct {
 	Metric      string  `json:"metric"`
@@ -237,6 +1204,7 @@ type SupplyBlockSummary struct {
 	Time     float64 `json:"time"`
 	Duration float64 `json:"duration"`
 	Severity string  `json:"severity"`
+	Cause    string  `json:"cause"`
 }
 
 // CriticalMoment repräsentiert einen kritischen Spielmoment
@@ -248,8 +1216,19 @@ type CriticalMoment struct {
 	IsPositive  bool    `json:"is_positive"`
 }
 
+// ProxyLoss ist ein Proxy-Gebäude, das vor Fertigstellung vom Gegner zerstört wurde
+type ProxyLoss struct {
+	Player       string  `json:"player"`
+	Building     string  `json:"building"`
+	StartTime    float64 `json:"start_time"`
+	LostTime     float64 `json:"lost_time"`
+	IsLoserProxy bool    `json:"is_loser_proxy"`
+	IsPlayerProxy bool   `json:"is_player_proxy"` // Proxy des analysierten Spielers
+}
+
 // IdentifiedProblem ist ein erkanntes Problem
 type IdentifiedProblem struct {
+	Key         string `json:"key"` // supply_blocks, low_spending, proxy_destroyed, harass_workers, low_apm, low_army
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Priority    string `json:"priority"`
@@ -257,14 +1236,80 @@ type IdentifiedProblem struct {
 
 // MatchupTips enthält matchup-spezifische Tipps
 type MatchupTips struct {
-	Opening  []string `json:"opening"`
-	MidGame  []string `json:"mid_game"`
-	Timing   []string `json:"timing"`
-	LateGame []string `json:"late_game"`
+	Matchup          string            `json:"matchup,omitempty"` // z.B. "PvZ", Gegner-Rasse wie gespielt
+	Patch            string            `json:"patch,omitempty"`   // Versionsbereich der Tipps, leer wenn allgemein gültig
+	Opening          []string          `json:"opening"`
+	MidGame          []string          `json:"mid_game"`
+	Timing           []string          `json:"timing"`
+	LateGame         []string          `json:"late_game"`
+	VsRandom         []string          `json:"vs_random,omitempty"` // Gegner hat Random gewählt
+	OpponentStrategy *DetectedStrategy `json:"opponent_strategy,omitempty"`
+	Reference        *ReferenceTips    `json:"reference,omitempty"` // Rückstände gegenüber dem passendsten Referenz-Replay
+}
+
+// ReferenceTips sind Tipps aus dem Vergleich mit einem Referenz-Replay
+type ReferenceTips struct {
+	ReferenceID int64    `json:"reference_id"`
+	ReplayID    int64    `json:"replay_id"`
+	Player      string   `json:"player"`
+	Build       string   `json:"build,omitempty"`
+	Tips        []string `json:"tips"`
+}
+
+// DetectedStrategy ist eine im Build des Gegners erkannte Strategie mit passenden Tipps
+type DetectedStrategy struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Time   float64  `json:"time"` // Zeitpunkt (Sekunden), ab dem die Strategie erkennbar war
+	Tips   []string `json:"tips"`
+}
+
+// MatchupKnowledgeBase ist die Wissensbasis für Matchup-Tipps (siehe strategic/matchups.json)
+type MatchupKnowledgeBase struct {
+	Version    int                  `json:"version"`
+	Matchups   []MatchupEntry       `json:"matchups"`
+	Strategies []StrategyDefinition `json:"strategies"`
+}
+
+// MatchupEntry enthält die Tipps eines Matchups pro Spielphase und Sprache
+// Matchup ist "PvZ", "TvT" usw., "PvR" für Random-Gegner oder "default"
+// MinVersion/MaxVersion grenzen die Tipps auf Spielversionen ein (z.B. "5.0.11", inklusive)
+type MatchupEntry struct {
+	Matchup    string              `json:"matchup"`
+	MinVersion string              `json:"min_version,omitempty"`
+	MaxVersion string              `json:"max_version,omitempty"`
+	Opening    map[string][]string `json:"opening,omitempty"`
+	MidGame    map[string][]string `json:"mid_game,omitempty"`
+	Timing     map[string][]string `json:"timing,omitempty"`
+	LateGame   map[string][]string `json:"late_game,omitempty"`
+}
+
+// StrategyDefinition beschreibt eine Gegner-Strategie, die am Build Order erkannt wird
+// Alle Signale müssen zutreffen; die erste passende Strategie in der Datei gewinnt
+type StrategyDefinition struct {
+	ID         string              `json:"id"`
+	Race       string              `json:"race"`               // Rasse des Gegners
+	Against    string              `json:"against,omitempty"`  // nur gegen diese eigene Rasse, leer = alle
+	MinVersion string              `json:"min_version,omitempty"`
+	MaxVersion string              `json:"max_version,omitempty"`
+	Signals    []StrategySignal    `json:"signals"`
+	Name       map[string]string   `json:"name"`
+	Tips       map[string][]string `json:"tips"`
+}
+
+// StrategySignal ist eine Bedingung an den Build Order des Gegners
+// Gezählt werden Einträge von UnitOrBuilding bis zur Zeit Before (Sekunden, 0 = ganzes Spiel)
+type StrategySignal struct {
+	Unit     string  `json:"unit"`                // z.B. "Spawning Pool", "Barracks"
+	Before   float64 `json:"before,omitempty"`
+	MinCount int     `json:"min_count,omitempty"` // Standard 1
+	Proxy    bool    `json:"proxy,omitempty"`     // nur Proxy-Gebäude zählen
+	Absent   bool    `json:"absent,omitempty"`    // Bedingung: weniger als MinCount vorhanden
 }
 
 // ImprovementStep ist ein konkreter Verbesserungsschritt
 type ImprovementStep struct {
+	Key         string `json:"key"` // Problem-Schlüssel bzw. build_order, scouting
 	Category    string `json:"category"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
@@ -278,15 +1323,35 @@ type User struct {
 	Email         string     `json:"email"`
 	PasswordHash  string     `json:"-"` // Nie im JSON ausgeben
 	SC2PlayerName string     `json:"sc2_player_name"`
+	Language      string     `json:"language,omitempty"` // bevorzugte Sprache, leer = Accept-Language
+	Role          string     `json:"role,omitempty"`     // admin, coach, leer = Spieler
 	CreatedAt     time.Time  `json:"created_at"`
 	LastLogin     *time.Time `json:"last_login,omitempty"`
 }
 
+// Benutzerrollen; Admins und Coaches verwalten Referenz-Replays
+const (
+	RoleAdmin = "admin"
+	RoleCoach = "coach"
+)
+
+// HasRole prüft, ob der Benutzer eine der Rollen hat
+func (u *User) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
 // UserPublic ist die öffentliche Ansicht eines Benutzers (ohne sensible Daten)
 type UserPublic struct {
 	ID            int64      `json:"id"`
 	Email         string     `json:"email"`
 	SC2PlayerName string     `json:"sc2_player_name"`
+	Language      string     `json:"language,omitempty"` // bevorzugte Sprache, leer = Accept-Language
+	Role          string     `json:"role,omitempty"`
 	CreatedAt     time.Time  `json:"created_at"`
 	LastLogin     *time.Time `json:"last_login,omitempty"`
 }
@@ -297,6 +1362,8 @@ func (u *User) ToPublic() UserPublic {
 		ID:            u.ID,
 		Email:         u.Email,
 		SC2PlayerName: u.SC2PlayerName,
+		Language:      u.Language,
+		Role:          u.Role,
 		CreatedAt:     u.CreatedAt,
 		LastLogin:     u.LastLogin,
 	}
@@ -362,6 +1429,9 @@ type DailyProgress struct {
 	AvgSpendingQuotient  float64   `json:"avg_spending_quotient"`
 	AvgSupplyBlockPct    float64   `json:"avg_supply_block_pct"`
 	TotalPlayTime        int       `json:"total_play_time"` // in Sekunden
+	// Durchschnitte der Zusatz-Metriken ("sq_mid", ...) und die Anzahl Spiele mit der jeweiligen Metrik
+	MetricAverages       MetricValues   `json:"metric_averages,omitempty"`
+	MetricGames          map[string]int `json:"-"`
 }
 
 // WinRate berechnet die Gewinnrate
@@ -386,6 +1456,7 @@ type WeeklyReport struct {
 	AvgAPM          float64         `json:"avg_apm"`
 	AvgSQ           float64         `json:"avg_sq"`
 	AvgSupplyBlock  float64         `json:"avg_supply_block"`
+	MetricAverages  MetricValues    `json:"metric_averages,omitempty"`
 	MainRace        string          `json:"main_race"`
 	TotalPlayTime   int             `json:"total_play_time"`
 	Improvements    json.RawMessage `json:"improvements,omitempty"`
@@ -393,9 +1464,21 @@ type WeeklyReport struct {
 	FocusSuggestion string          `json:"focus_suggestion"`
 	Strengths       json.RawMessage `json:"strengths,omitempty"`
 	Weaknesses      json.RawMessage `json:"weaknesses,omitempty"`
+	RecurringWeaknesses []RecurringProblem `json:"recurring_weaknesses,omitempty"` // Probleme aus mehreren Spielen der Woche
 	GeneratedAt     time.Time       `json:"generated_at"`
 }
 
+// RecurringProblem ist ein Problem der strategischen Analyse, das in mehreren Spielen eines Benutzers auftritt
+type RecurringProblem struct {
+	Key       string    `json:"key"`  // Schlüssel des IdentifiedProblem, z.B. supply_blocks
+	Name      string    `json:"name"` // übersetzt bei der Ausgabe
+	Count     int       `json:"count"`     // Spiele mit diesem Problem
+	Games     int       `json:"games"`     // betrachtete Spiele mit strategischer Analyse
+	Frequency float64   `json:"frequency"` // Anteil in Prozent
+	LastSeen  time.Time `json:"last_seen"`
+	ReplayIDs []int64   `json:"replay_ids"`
+}
+
 // CoachingFocus repräsentiert den aktuellen Fokusbereich
 type CoachingFocus struct {
 	ID          int64     `json:"id"`
@@ -416,6 +1499,7 @@ type MentorDashboard struct {
 	CurrentFocus  *CoachingFocus  `json:"current_focus,omitempty"`
 	WeeklyReport  *WeeklyReport   `json:"weekly_report,omitempty"`
 	ProgressTrend []DailyProgress `json:"progress_trend"` // Letzte 14 Tage
+	RecurringWeaknesses []RecurringProblem `json:"recurring_weaknesses"` // Wiederkehrende Probleme der letzten Spiele
 }
 
 // WeekStats enthält aggregierte Wochendaten
@@ -448,6 +1532,65 @@ type RecentGame struct {
 	PlayedAt    time.Time `json:"played_at"`
 }
 
+// PerformanceGame ist ein verknüpftes Spiel eines Benutzers für die Matchup-Karten-Matrix
+type PerformanceGame struct {
+	ReplayID     int64     `json:"replay_id"`
+	Map          string    `json:"map"`
+	Race         string    `json:"race"`
+	OpponentRace string    `json:"opponent_race"`
+	Result       string    `json:"result"`
+	APM          float64   `json:"apm"`
+	SQ           float64   `json:"sq"`
+	SupplyBlock  *float64  `json:"supply_block,omitempty"` // Prozent, nil = keine Analyse
+	PlayedAt     time.Time `json:"played_at"`
+}
+
+// PerformanceMatrix ist die Leistung eines Benutzers je Matchup und Karte
+type PerformanceMatrix struct {
+	Overall      PerformanceCell       `json:"overall"`
+	Matchups     []PerformanceCell     `json:"matchups"` // je Matchup über alle Karten
+	Cells        []PerformanceCell     `json:"cells"`    // je Matchup und Karte
+	WeakSpots    []PerformanceWeakSpot `json:"weak_spots"`
+	TestedCells  int                   `json:"tested_cells"`   // Anzahl der Tests in der Holm-Korrektur
+	WeakSpotNote string                `json:"weak_spot_note"` // Erklärung von Test und Baseline
+}
+
+// PerformanceCell fasst die Spiele eines Matchups (auf einer Karte) zusammen
+type PerformanceCell struct {
+	Matchup     string         `json:"matchup,omitempty"`
+	Map         string         `json:"map,omitempty"` // leer = alle Karten
+	Games       int            `json:"games"`
+	Wins        int            `json:"wins"`
+	Losses      int            `json:"losses"`
+	WinRate     float64        `json:"win_rate"`      // Prozent der entschiedenen Spiele
+	WinRateLow  float64        `json:"win_rate_low"`  // 95 %-Konfidenzintervall (Wilson)
+	WinRateHigh float64        `json:"win_rate_high"`
+	APM         MetricEstimate `json:"apm"`
+	SQ          MetricEstimate `json:"sq"`
+	SupplyBlock MetricEstimate `json:"supply_block"`
+}
+
+// MetricEstimate ist der Mittelwert einer Metrik mit 95 %-Konfidenzintervall
+type MetricEstimate struct {
+	Average float64 `json:"average"`
+	Low     float64 `json:"low"`
+	High    float64 `json:"high"`
+	Games   int     `json:"games"` // Spiele mit Wert
+}
+
+// PerformanceWeakSpot ist ein Matchup (auf einer Karte) mit signifikant schlechterer Win Rate als im Rest
+type PerformanceWeakSpot struct {
+	Matchup        string  `json:"matchup"`
+	Map            string  `json:"map,omitempty"` // leer = alle Karten
+	Games          int     `json:"games"`         // entschiedene Spiele
+	Wins           int     `json:"wins"`
+	WinRate        float64 `json:"win_rate"`
+	Baseline       float64 `json:"baseline"`         // Win Rate der übrigen Spiele (bei Karten inkl. desselben Matchups auf anderen Karten)
+	PValue         float64 `json:"p_value"`          // einseitiger exakter Binomialtest gegen Baseline
+	AdjustedPValue float64 `json:"adjusted_p_value"` // nach Holm-Bonferroni über alle getesteten Zellen
+	Message        string  `json:"message"`
+}
+
 // GoalTemplate ist eine vordefinierte Zielvorlage
 type GoalTemplate struct {
 	Name        string  `json:"name"`
@@ -470,6 +1613,9 @@ func GetGoalTemplates() []GoalTemplate {
 		{Name: "Win Rate", GoalType: "weekly", MetricName: "win_rate", Comparison: ">=", Beginner: 45, Advanced: 55, Description: "Gewinnrate über der Woche"},
 		{Name: "Gesamtspiele", GoalType: "weekly", MetricName: "games_played", Comparison: ">=", Beginner: 15, Advanced: 30, Description: "Anzahl Spiele pro Woche"},
 		{Name: "Spending Quotient", GoalType: "weekly", MetricName: "sq", Comparison: ">=", Beginner: 60, Advanced: 80, Description: "Durchschnittlicher SQ über die Woche"},
+		{Name: "Spending im Midgame", GoalType: "weekly", MetricName: "sq_mid", Comparison: ">=", Beginner: 60, Advanced: 80, Description: "Durchschnittlicher SQ zwischen 6:00 und 12:00"},
+		{Name: "Worker bei 6:00", GoalType: "weekly", MetricName: "workers_at_6m", Comparison: ">=", Beginner: 40, Advanced: 50, Description: "Durchschnittliche Worker-Anzahl bei 6:00"},
+		{Name: "Early-Game Supply Blocks", GoalType: "weekly", MetricName: "supply_block_early", Comparison: "<=", Beginner: 10, Advanced: 5, Description: "Supply Block Prozent in den ersten 6 Minuten"},
 	}
 }
 
@@ -488,6 +1634,17 @@ type RegisterRequest struct {
 	SC2PlayerName string `json:"sc2_player_name"`
 }
 
+// Language ist eine verfügbare Sprache für generierte Texte
+type Language struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// UpdateUserRequest ändert Benutzer-Einstellungen; nicht gesetzte Felder bleiben unverändert
+type UpdateUserRequest struct {
+	Language *string `json:"language,omitempty"` // "" = automatisch (Accept-Language)
+}
+
 // LoginRequest ist der Request zum Login
 type LoginRequest struct {
 	Email    string `json:"email"`
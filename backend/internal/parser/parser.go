@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/icza/s2prot"
@@ -17,6 +18,8 @@ type ParsedReplay struct {
 	Hash        string
 	Filename    string
 	Map         string
+	MapSizeX    int // Map-Breite in Zellen
+	MapSizeY    int // Map-Höhe in Zellen
 	Duration    int // Sekunden (Spielzeit)
 	GameVersion string
 	PlayedAt    time.Time
@@ -33,6 +36,9 @@ type ParsedPlayer struct {
 	Result     string // Win, Loss, Undecided
 	IsHuman    bool
 	Region     string
+	League     string // höchste Liga (bronze ... grandmaster), leer wenn unbekannt
+	RandomRace bool   // in der Lobby Random gewählt, Race ist die tatsächlich gespielte Rasse
+	LeftAt     int    // Sekunden, zu denen der Spieler das Spiel verlassen hat (0 = kein Leave-Event)
 }
 
 // ParsedEvents enthält die relevanten Events für die Analyse
@@ -58,6 +64,79 @@ type GameEvent struct {
 	Data      map[string]interface{}
 }
 
+// UnitPosition ist die Position einer Einheit aus einem UnitPositions-Event
+type UnitPosition struct {
+	UnitIndex int
+	X         float64 // Map-Zellen wie bei UnitBorn
+	Y         float64
+}
+
+// UnitPositions dekodiert die (Index-Delta, x, y) Tripel eines UnitPositions-Events ab firstUnitIndex
+// Die Koordinaten liegen im selben Raster wie x/y von UnitBorn/UnitInit (s2protocol und sc2reader
+// multiplizieren beide mit 4); sie werden daher unverändert übernommen
+func (e TrackerEvent) UnitPositions() []UnitPosition {
+	if e.EventType != "UnitPositions" {
+		return nil
+	}
+	items, _ := e.Data["items"].([]interface{})
+	unitIndex := getIntFromMap(e.Data, "firstUnitIndex", 0)
+	positions := make([]UnitPosition, 0, len(items)/3)
+	for i := 0; i+2 < len(items); i += 3 {
+		unitIndex += ToInt(items[i])
+		positions = append(positions, UnitPosition{
+			UnitIndex: unitIndex,
+			X:         float64(ToInt(items[i+1])),
+			Y:         float64(ToInt(items[i+2])),
+		})
+	}
+	return positions
+}
+
+// AbilityLink liefert die Ability-ID eines Cmd-Events (0 bei Rechtsklick ohne Ability)
+// Die IDs hängen vom Patch ab
+func (e GameEvent) AbilityLink() int {
+	if abil, ok := e.Data["abil"].(map[string]interface{}); ok {
+		return GetInt(abil, "abilLink")
+	}
+	return 0
+}
+
+// CmdTarget liefert das Ziel eines Cmd-Events (Punkt oder Ziel-Einheit) in Map-Zellen
+func (e GameEvent) CmdTarget() (x, y float64, ok bool) {
+	cmdData, ok := e.Data["data"].(map[string]interface{})
+	if !ok {
+		return 0, 0, false
+	}
+	target, ok := cmdData["TargetPoint"].(map[string]interface{})
+	if !ok {
+		unit, isUnit := cmdData["TargetUnit"].(map[string]interface{})
+		if !isUnit {
+			return 0, 0, false
+		}
+		if target, ok = unit["snapshotPoint"].(map[string]interface{}); !ok {
+			return 0, 0, false
+		}
+	}
+	// Zielkoordinaten sind in 1/4096 Map-Zellen
+	return float64(GetInt(target, "x")) / 4096, float64(GetInt(target, "y")) / 4096, true
+}
+
+// CmdQueued prüft ob ein Cmd-Event eingereiht ist (Shift), also erst nach dem vorherigen Befehl ausgeführt wird
+func (e GameEvent) CmdQueued() bool {
+	// cmdFlags Bit 1 = Queued (s2protocol)
+	return GetInt(e.Data, "cmdFlags")&2 != 0
+}
+
+// CmdTargetsNeutral prüft ob ein Cmd-Event eine neutrale Einheit anvisiert (Mineralien, Geysire)
+func (e GameEvent) CmdTargetsNeutral() bool {
+	cmdData, _ := e.Data["data"].(map[string]interface{})
+	unit, ok := cmdData["TargetUnit"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	return GetInt(unit, "snapshotControlPlayerId") == 0 && GetInt(unit, "snapshotUpkeepPlayerId") == 0
+}
+
 // MessageEvent repräsentiert eine Chat-Nachricht
 type MessageEvent struct {
 	Loop     int
@@ -113,6 +192,12 @@ func (p *Parser) ParseFile(filepath string) (*ParsedReplay, error) {
 	parsed.Map = details.Title()
 	parsed.PlayedAt = details.TimeUTC()
 	parsed.Players = parseDetailPlayers(details)
+	assignLobbyData(parsed.Players, details, r.InitData)
+
+	// Map-Größe aus den Lobby-Daten
+	gameDesc := r.InitData.GameDescription
+	parsed.MapSizeX = int(gameDesc.Int("mapSizeX"))
+	parsed.MapSizeY = int(gameDesc.Int("mapSizeY"))
 
 	// Initialisiere Events
 	parsed.Events = &ParsedEvents{
@@ -125,10 +210,12 @@ func (p *Parser) ParseFile(filepath string) (*ParsedReplay, error) {
 	if r.TrackerEvts != nil && len(r.TrackerEvts.Evts) > 0 {
 		parsed.Events.TrackerEvents = parseTrackerEvents(r.TrackerEvts.Evts)
 	}
+	resolveRaces(parsed.Players, parsed.Events.TrackerEvents)
 
 	// Lade Game-Events für APM-Berechnung
 	if len(r.GameEvts) > 0 {
 		parsed.Events.GameEvents = parseGameEvents(r.GameEvts)
+		assignLeaveTimes(parsed.Players, r.GameEvts)
 	}
 
 	// Lade Message-Events
@@ -183,6 +270,61 @@ func parseDetailPlayers(details rep.Details) []ParsedPlayer {
 	return result
 }
 
+// assignLobbyData ordnet den Spielern Rassenwahl und höchste Liga aus den Lobby-Daten zu
+// Details-Spieler -> Lobby-Slot (über die Working-Set-Slot-ID) -> User-Init-Daten (über die User-ID)
+func assignLobbyData(players []ParsedPlayer, details rep.Details, initData rep.InitData) {
+	detailPlayers := details.Players()
+	for i := range players {
+		if i >= len(detailPlayers) {
+			break
+		}
+		slotID := detailPlayers[i].WorkingSetSlotID()
+		for _, slot := range initData.LobbyState.Slots {
+			if slot.WorkingSetSlotID() != slotID {
+				continue
+			}
+			players[i].RandomRace = slot.RacePrefRace() == rep.RaceRandom
+			userID := int(slot.UserID())
+			if userID < 0 || userID >= len(initData.UserInitDatas) {
+				break
+			}
+			league := initData.UserInitDatas[userID].HighestLeague()
+			if league != nil && league != rep.LeagueUnknown && league != rep.LeagueUnranked {
+				players[i].League = strings.ToLower(league.Name)
+			}
+			break
+		}
+	}
+}
+
+// raceByStartUnit ordnet Start-Einheiten ihrer Rasse zu
+var raceByStartUnit = map[string]string{
+	"SCV": "Terran", "CommandCenter": "Terran",
+	"Drone": "Zerg", "Hatchery": "Zerg",
+	"Probe": "Protoss", "Nexus": "Protoss",
+}
+
+// resolveRaces bestimmt die gespielte Rasse aus den ersten eigenen Einheiten,
+// falls die Details keine eindeutige Rasse enthalten (z.B. "Random" oder unbekannte Lokalisierung)
+func resolveRaces(players []ParsedPlayer, events []TrackerEvent) {
+	for i := range players {
+		switch players[i].Race {
+		case "Terran", "Zerg", "Protoss":
+			continue
+		}
+		for _, evt := range events {
+			if evt.EventType != "UnitBorn" || getIntFromMap(evt.Data, "controlPlayerId", 0) != players[i].Slot {
+				continue
+			}
+			unitType, _ := evt.Data["unitTypeName"].(string)
+			if race, ok := raceByStartUnit[unitType]; ok {
+				players[i].Race = race
+				break
+			}
+		}
+	}
+}
+
 // parseResult konvertiert das Spielergebnis
 func parseResult(result *rep.Result) string {
 	if result == nil {
@@ -219,7 +361,7 @@ func parseTrackerEvents(evts []s2prot.Event) []TrackerEvent {
 		// Filtere relevante Events (vereinfachte Namen in neueren s2prot Versionen)
 		switch evt.EvtType.Name {
 		case "PlayerStats", "UnitBorn", "UnitDied", "UnitTypeChange",
-			"Upgrade", "UnitInit", "UnitDone":
+			"Upgrade", "UnitInit", "UnitDone", "UnitPositions":
 			result = append(result, te)
 		}
 	}
@@ -253,7 +395,7 @@ func parseGameEvents(gameEvts []s2prot.Event) []GameEvent {
 		switch evt.EvtType.Name {
 		case "Cmd", "CmdUpdateTargetPoint", "CmdUpdateTargetUnit",
 			"SelectionDelta", "ControlGroupUpdate",
-			"CameraUpdate", "CommandManagerState":
+			"CameraUpdate", "CameraSave", "CommandManagerState":
 			result = append(result, ge)
 		}
 	}
@@ -261,6 +403,27 @@ func parseGameEvents(gameEvts []s2prot.Event) []GameEvent {
 	return result
 }
 
+// assignLeaveTimes merkt sich, wann die Spieler das Spiel verlassen haben
+// Die User-ID wird wie bei den Game-Events auf den Spieler-Slot abgebildet
+func assignLeaveTimes(players []ParsedPlayer, gameEvts []s2prot.Event) {
+	for _, evt := range gameEvts {
+		// Ab Build 24764 "UserLeave", davor "PlayerLeave"
+		if evt.EvtType.Name != "UserLeave" && evt.EvtType.Name != "PlayerLeave" {
+			continue
+		}
+		data := structToMap(evt.Struct)
+		userID := 0
+		if useridMap, ok := data["userid"].(map[string]interface{}); ok {
+			userID = getIntFromMap(useridMap, "userId", 0)
+		}
+		for i := range players {
+			if players[i].Slot == userID+1 && players[i].LeftAt == 0 {
+				players[i].LeftAt = loopsToSeconds(int(evt.Loop()))
+			}
+		}
+	}
+}
+
 // parseMessageEvents extrahiert Chat-Nachrichten
 func parseMessageEvents(msgEvts []s2prot.Event) []MessageEvent {
 	var result []MessageEvent
@@ -302,34 +465,74 @@ func structToMap(s s2prot.Struct) map[string]interface{} {
 	}
 
 	for key, val := range s {
-		switch v := val.(type) {
-		case s2prot.Struct:
-			result[key] = structToMap(v)
-		case []interface{}:
-			result[key] = v
-		default:
-			result[key] = v
-		}
+		result[key] = convertValue(val)
 	}
 
 	return result
 }
 
+// convertValue wandelt s2prot-Typen rekursiv in Standard-Go-Typen um
+func convertValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case s2prot.Struct:
+		return structToMap(v)
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, item := range v {
+			arr[i] = convertValue(item)
+		}
+		return arr
+	case s2prot.BitArr:
+		// Bit-Arrays (z.B. Selection-Masken) als []bool
+		bits := make([]bool, v.Count)
+		for i := range bits {
+			bits[i] = v.Bit(i)
+		}
+		return bits
+	default:
+		return v
+	}
+}
+
 // getIntFromMap extrahiert einen Int-Wert aus einer Map
 func getIntFromMap(m map[string]interface{}, key string, defaultVal int) int {
 	if val, ok := m[key]; ok {
-		switch v := val.(type) {
-		case int:
-			return v
-		case int64:
-			return int(v)
-		case float64:
-			return int(v)
+		switch val.(type) {
+		case int, int64, float64:
+			return ToInt(val)
 		}
 	}
 	return defaultVal
 }
 
+// GetInt extrahiert einen Int-Wert aus Event-Daten (0 wenn nicht vorhanden)
+func GetInt(m map[string]interface{}, key string) int {
+	return getIntFromMap(m, key, 0)
+}
+
+// ToInt wandelt einen numerischen Event-Wert in int um (0 bei anderen Typen)
+func ToInt(val interface{}) int {
+	switch v := val.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// GetString extrahiert einen String-Wert aus Event-Daten ("" wenn nicht vorhanden)
+func GetString(m map[string]interface{}, key string) string {
+	return getStringFromMap(m, key, "")
+}
+
+// GetUnitTag berechnet den Unit-Tag eines Events (Index << 18 + Recycle)
+func GetUnitTag(m map[string]interface{}) int {
+	return GetInt(m, "unitTagIndex")<<18 + GetInt(m, "unitTagRecycle")
+}
+
 // getStringFromMap extrahiert einen String-Wert aus einer Map
 func getStringFromMap(m map[string]interface{}, key string, defaultVal string) string {
 	if val, ok := m[key]; ok {
@@ -374,3 +577,14 @@ func LoopsToGameSeconds(loops int) float64 {
 func LoopsToRealSeconds(loops int) float64 {
 	return float64(loops) / 16.0 / 1.4
 }
+
+// RealSecondsToLoops konvertiert Echtzeitsekunden zu Loops
+func RealSecondsToLoops(seconds float64) int {
+	return int(seconds * 16.0 * 1.4)
+}
+
+// FormatTime formatiert Sekunden als m:ss
+func FormatTime(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
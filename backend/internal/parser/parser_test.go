@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// Echte Events aus einem WoL-Replay (Map 160x168): das Start-Command-Center von Spieler 2
+// und ein UnitPositions-Event während eines Angriffs auf dessen Main
+var (
+	fixtureBorn = TrackerEvent{Loop: 0, EventType: "UnitBorn", Data: map[string]interface{}{
+		"controlPlayerId": int64(2), "upkeepPlayerId": int64(2),
+		"unitTagIndex": int64(228), "unitTagRecycle": int64(1),
+		"unitTypeName": "CommandCenter", "x": int64(137), "y": int64(76),
+	}}
+	fixturePositions = TrackerEvent{Loop: 11520, EventType: "UnitPositions", Data: map[string]interface{}{
+		"firstUnitIndex": int64(228),
+		"items": []interface{}{
+			int64(0), int64(132), int64(73), int64(74), int64(139), int64(78), int64(2), int64(139), int64(77),
+			int64(10), int64(139), int64(78), int64(39), int64(141), int64(81), int64(43), int64(138), int64(77),
+			int64(55), int64(139), int64(77), int64(1), int64(140), int64(82), int64(27), int64(138), int64(77),
+			int64(29), int64(139), int64(78), int64(1), int64(141), int64(81), int64(2), int64(138), int64(76),
+			int64(2), int64(140), int64(82), int64(14), int64(139), int64(77),
+		},
+	}}
+)
+
+func TestUnitPositions(t *testing.T) {
+	tests := []struct {
+		name string
+		evt  TrackerEvent
+		want []UnitPosition
+	}{
+		{
+			name: "other event type",
+			evt:  fixtureBorn,
+			want: nil,
+		},
+		{
+			name: "no items",
+			evt:  TrackerEvent{EventType: "UnitPositions", Data: map[string]interface{}{"firstUnitIndex": int64(5)}},
+			want: []UnitPosition{},
+		},
+		{
+			name: "index deltas accumulate from firstUnitIndex",
+			evt: TrackerEvent{EventType: "UnitPositions", Data: map[string]interface{}{
+				"firstUnitIndex": int64(10),
+				"items":          []interface{}{int64(0), int64(20), int64(30), int64(3), int64(21), int64(31)},
+			}},
+			want: []UnitPosition{{UnitIndex: 10, X: 20, Y: 30}, {UnitIndex: 13, X: 21, Y: 31}},
+		},
+		{
+			name: "incomplete trailing triple is ignored",
+			evt: TrackerEvent{EventType: "UnitPositions", Data: map[string]interface{}{
+				"firstUnitIndex": 1,
+				"items":          []interface{}{0, 5.0, 6.0, 2, 7},
+			}},
+			want: []UnitPosition{{UnitIndex: 1, X: 5, Y: 6}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.evt.UnitPositions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnitPositions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitPositionsSharesUnitBornGrid(t *testing.T) {
+	const mapSizeX, mapSizeY = 160, 168
+
+	positions := fixturePositions.UnitPositions()
+	if len(positions) != 14 {
+		t.Fatalf("got %d positions, want 14", len(positions))
+	}
+	if positions[0].UnitIndex != GetInt(fixtureBorn.Data, "unitTagIndex") {
+		t.Fatalf("first unit index = %d, want the command center", positions[0].UnitIndex)
+	}
+	if last := positions[len(positions)-1].UnitIndex; last != 527 {
+		t.Errorf("last unit index = %d, want 527", last)
+	}
+
+	// Alle Einheiten stehen im selben Raster wie UnitBorn: in der Karte und nahe am Command Center
+	bornX, bornY := float64(GetInt(fixtureBorn.Data, "x")), float64(GetInt(fixtureBorn.Data, "y"))
+	for _, p := range positions {
+		if p.X < 0 || p.X > mapSizeX || p.Y < 0 || p.Y > mapSizeY {
+			t.Errorf("unit %d at (%v, %v) is outside the %dx%d map", p.UnitIndex, p.X, p.Y, mapSizeX, mapSizeY)
+		}
+		if d := math.Hypot(p.X-bornX, p.Y-bornY); d > 10 {
+			t.Errorf("unit %d at (%v, %v) is %.1f cells from the command center", p.UnitIndex, p.X, p.Y, d)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package parser
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Research ist eine Forschung eines Spielers
+type Research struct {
+	Upgrade   string  // Upgrade-Name; leer, wenn die Forschung bei Spielende noch lief
+	Structure string  // forschendes Gebäude (leer, wenn kein Forschungsbefehl gefunden wurde)
+	Start     float64 // Forschungsbeginn in Sekunden
+	Done      float64 // Fertigstellung in Sekunden (0 = bei Spielende nicht fertig)
+}
+
+// abilityKey identifiziert einen Button: Ability und Befehlsindex
+type abilityKey struct {
+	link, index int
+}
+
+// maxResearchDuration: ältere Befehle ohne Fertigstellung gelten nicht als laufende Forschung
+const maxResearchDuration = 200.0
+
+// researchCommand ist ein Befehl ohne Ziel an eine Auswahl aus Forschungsgebäuden
+type researchCommand struct {
+	key       abilityKey
+	structure string
+	tag       int // Game-Tag des Gebäudes, -1 bei mehreren ausgewählten Gebäuden
+	time      float64
+	consumed  bool
+}
+
+// CollectResearch ermittelt die Forschungen eines Spielers
+// Der Beginn stammt aus dem Forschungsbefehl, das Ende aus dem Upgrade-Event. Da die
+// Ability-IDs vom Patch abhängen, wird jedem Button das Upgrade zugeordnet, dessen
+// geschätzte Dauer am besten zum Abstand zwischen Befehl und Fertigstellung passt.
+// Ohne passenden Befehl (z.B. Forschung im Hauptgebäude) wird der Beginn aus der
+// geschätzten Dauer zurückgerechnet. Wartet die Forschung in der Warteschlange eines
+// Gebäudes, beginnt sie erst mit der Fertigstellung der vorherigen. Befehle ohne
+// Fertigstellung in den letzten maxResearchDuration Sekunden laufen bei Spielende noch.
+func CollectResearch(events *ParsedEvents, playerID int) []Research {
+	if events == nil {
+		return nil
+	}
+
+	tagTypes := make(map[int]string) // Game-Tag -> Einheitentyp
+	var completions []TrackerEvent
+	var end float64
+	for _, evt := range events.TrackerEvents {
+		end = LoopsToRealSeconds(evt.Loop)
+		switch evt.EventType {
+		case "UnitBorn", "UnitInit", "UnitTypeChange":
+			tagTypes[GetUnitTag(evt.Data)] = GetString(evt.Data, "unitTypeName")
+		case "Upgrade":
+			// Upgrades bei Loop 0 sind Skins und Start-Boni, keine Forschung
+			name := GetString(evt.Data, "upgradeTypeName")
+			if evt.PlayerID == playerID && evt.Loop > 0 && name != "" && !IsCosmeticUpgrade(name) {
+				completions = append(completions, evt)
+			}
+		}
+	}
+
+	var commands []researchCommand
+	selections := make(Selections)
+	for _, evt := range events.GameEvents {
+		if evt.PlayerID != playerID {
+			continue
+		}
+		switch evt.EventType {
+		case "SelectionDelta", "ControlGroupUpdate":
+			selections.Apply(evt)
+
+		case "Cmd":
+			if _, _, ok := evt.CmdTarget(); ok || evt.AbilityLink() == 0 {
+				continue
+			}
+			active := selections.Active()
+			if len(active) == 0 {
+				continue
+			}
+			researching := true
+			for _, tag := range active {
+				if !IsResearchStructure(tagTypes[tag]) {
+					researching = false
+					break
+				}
+			}
+			if !researching {
+				continue
+			}
+			tag := -1
+			if len(active) == 1 {
+				tag = active[0]
+			}
+			abil, _ := evt.Data["abil"].(map[string]interface{})
+			commands = append(commands, researchCommand{
+				key:       abilityKey{link: GetInt(abil, "abilLink"), index: GetInt(abil, "abilCmdIndex")},
+				structure: tagTypes[active[0]],
+				tag:       tag,
+				time:      LoopsToRealSeconds(evt.Loop),
+			})
+		}
+	}
+
+	result := []Research{}
+	var tags []int                             // Gebäude je Forschung, parallel zu result
+	upgradeKeys := make(map[string]abilityKey) // Upgrade -> Button
+	keyUpgrades := make(map[abilityKey]string) // Button -> Upgrade
+	for _, evt := range completions {
+		name := GetString(evt.Data, "upgradeTypeName")
+		done := LoopsToRealSeconds(evt.Loop)
+		expected := EstimatedResearchDuration(name)
+		research := Research{Upgrade: name, Done: done}
+
+		tag := -1
+		best := -1
+		if key, ok := upgradeKeys[name]; ok {
+			// Bekannter Button: der letzte Befehl davor hat die Forschung gestartet
+			for i, c := range commands {
+				if c.time > done {
+					break
+				}
+				if !c.consumed && c.key == key {
+					best = i
+				}
+			}
+		} else {
+			bestDiff := math.MaxFloat64
+			for i, c := range commands {
+				if c.time > done {
+					break
+				}
+				if _, taken := keyUpgrades[c.key]; c.consumed || taken {
+					continue
+				}
+				// Chrono Boost verkürzt, eine Warteschlange verlängert die Forschung
+				elapsed := done - c.time
+				if elapsed < expected*0.5 || elapsed > expected*2 {
+					continue
+				}
+				if diff := math.Abs(elapsed - expected); diff < bestDiff {
+					best, bestDiff = i, diff
+				}
+			}
+		}
+
+		if best >= 0 {
+			c := commands[best]
+			upgradeKeys[name] = c.key
+			keyUpgrades[c.key] = name
+			research.Start = c.time
+			research.Structure = c.structure
+			tag = c.tag
+			for i := range commands {
+				if commands[i].key == c.key && commands[i].time <= done {
+					commands[i].consumed = true
+				}
+			}
+		} else {
+			research.Start = math.Max(0, done-expected)
+		}
+		result = append(result, research)
+		tags = append(tags, tag)
+	}
+
+	// Buttons ohne Fertigstellung: Forschung lief bei Spielende noch
+	running := make(map[abilityKey]bool)
+	for _, c := range commands {
+		if _, taken := keyUpgrades[c.key]; c.consumed || taken || running[c.key] || end-c.time > maxResearchDuration {
+			continue
+		}
+		running[c.key] = true
+		result = append(result, Research{Structure: c.structure, Start: c.time})
+		tags = append(tags, c.tag)
+	}
+
+	// Warteschlange: im selben Gebäude beginnt die Forschung erst nach der vorherigen
+	for i := range result {
+		if tags[i] < 0 {
+			continue
+		}
+		for j := range result {
+			if i != j && tags[j] == tags[i] && result[j].Done > result[i].Start && (result[i].Done == 0 || result[j].Done < result[i].Done) {
+				result[i].Start = result[j].Done
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Start < result[j].Start })
+	return result
+}
+
+// IsCosmeticUpgrade prüft ob ein Upgrade kosmetisch ist (Skins, Sprays, Belohnungen)
+func IsCosmeticUpgrade(name string) bool {
+	lowerName := strings.ToLower(name)
+	for _, c := range []string{"reward", "dance", "skin", "spray", "voice", "emote"} {
+		if strings.Contains(lowerName, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// researchDurations sind Forschungszeiten (Sekunden) häufiger Upgrades
+var researchDurations = map[string]float64{
+	"stimpack":               100,
+	"shieldwall":             79,
+	"punishergrenades":       43,
+	"zerglingmovementspeed":  79,
+	"glialreconstitution":    79,
+	"overlordspeed":          43,
+	"burrow":                 71,
+	"evolvegroovedspines":    50,
+	"evolvemuscularaugments": 64,
+	"warpgateresearch":       100,
+	"blinktech":              121,
+	"charge":                 100,
+	"extendedthermallance":   100,
+}
+
+// EstimatedResearchDuration schätzt die Forschungsdauer eines Upgrades in Sekunden
+// Die Werte hängen vom Patch ab und dienen nur der Zuordnung zu Forschungsbefehlen
+func EstimatedResearchDuration(name string) float64 {
+	lowerName := strings.ToLower(name)
+	if d, ok := researchDurations[lowerName]; ok {
+		return d
+	}
+	// Waffen- und Panzerungsstufen
+	levels := []string{"level1", "level2", "level3"}
+	for i, level := range levels {
+		if strings.HasSuffix(lowerName, level) {
+			if strings.HasPrefix(lowerName, "protoss") {
+				return []float64{129, 154, 179}[i]
+			}
+			return []float64{114, 136, 157}[i]
+		}
+	}
+	return 100
+}
@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"math"
+	"testing"
+)
+
+const (
+	engineeringBayIndex = 10
+	engineeringBayTag   = engineeringBayIndex<<18 + 1
+	weaponsKey          = 2 // Button-Index von Infanteriewaffen im Forschungsbefehl
+	armorKey            = 6
+)
+
+// researchFixture baut die Events eines Spielers mit einer Engineering Bay
+type researchFixture struct {
+	events ParsedEvents
+}
+
+func newResearchFixture() *researchFixture {
+	f := &researchFixture{}
+	f.events.TrackerEvents = append(f.events.TrackerEvents, TrackerEvent{EventType: "UnitInit", PlayerID: 1, Data: map[string]interface{}{
+		"controlPlayerId": 1, "unitTagIndex": engineeringBayIndex, "unitTagRecycle": 1, "unitTypeName": "EngineeringBay",
+	}})
+	f.events.GameEvents = append(f.events.GameEvents, GameEvent{EventType: "SelectionDelta", PlayerID: 1, Data: map[string]interface{}{
+		"controlGroupId": ActiveSelection,
+		"delta":          map[string]interface{}{"addUnitTags": []interface{}{engineeringBayTag}},
+	}})
+	return f
+}
+
+func (f *researchFixture) command(seconds float64, index int) {
+	f.events.GameEvents = append(f.events.GameEvents, GameEvent{Loop: RealSecondsToLoops(seconds), EventType: "Cmd", PlayerID: 1, Data: map[string]interface{}{
+		"abil": map[string]interface{}{"abilLink": 154, "abilCmdIndex": index},
+		"data": map[string]interface{}{"None": nil},
+	}})
+}
+
+func (f *researchFixture) tracker(seconds float64, eventType string, data map[string]interface{}) {
+	f.events.TrackerEvents = append(f.events.TrackerEvents, TrackerEvent{Loop: RealSecondsToLoops(seconds), EventType: eventType, PlayerID: 1, Data: data})
+}
+
+func (f *researchFixture) upgrade(seconds float64, name string) {
+	f.tracker(seconds, "Upgrade", map[string]interface{}{"upgradeTypeName": name})
+}
+
+func TestCollectResearch(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(f *researchFixture)
+		want  []Research
+	}{
+		{
+			name: "start from the research command",
+			build: func(f *researchFixture) {
+				f.command(100, weaponsKey)
+				f.upgrade(230, "TerranInfantryWeaponsLevel1")
+			},
+			want: []Research{{Upgrade: "TerranInfantryWeaponsLevel1", Structure: "EngineeringBay", Start: 100, Done: 230}},
+		},
+		{
+			name: "queued research starts when the previous one completes",
+			build: func(f *researchFixture) {
+				f.command(100, weaponsKey)
+				f.command(101, armorKey)
+				f.upgrade(214, "TerranInfantryWeaponsLevel1")
+				f.upgrade(328, "TerranInfantryArmorsLevel1")
+			},
+			want: []Research{
+				{Upgrade: "TerranInfantryWeaponsLevel1", Structure: "EngineeringBay", Start: 100, Done: 214},
+				{Upgrade: "TerranInfantryArmorsLevel1", Structure: "EngineeringBay", Start: 214, Done: 328},
+			},
+		},
+		{
+			name: "running at game end",
+			build: func(f *researchFixture) {
+				f.command(300, weaponsKey)
+				f.tracker(400, "PlayerStats", nil)
+			},
+			want: []Research{{Structure: "EngineeringBay", Start: 300}},
+		},
+		{
+			name: "old command without completion is ignored",
+			build: func(f *researchFixture) {
+				f.command(100, weaponsKey)
+				f.tracker(400, "PlayerStats", nil)
+			},
+			want: []Research{},
+		},
+		{
+			name: "estimate without research command",
+			build: func(f *researchFixture) {
+				f.upgrade(143, "overlordspeed")
+			},
+			want: []Research{{Upgrade: "overlordspeed", Start: 100, Done: 143}},
+		},
+		{
+			name: "cosmetic upgrades are skipped",
+			build: func(f *researchFixture) {
+				f.upgrade(0, "TerranInfantryWeaponsLevel1")
+				f.upgrade(50, "MarineSkin")
+			},
+			want: []Research{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newResearchFixture()
+			tt.build(f)
+			got := CollectResearch(&f.events, 1)
+			if len(got) != len(tt.want) {
+				t.Fatalf("CollectResearch() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if g.Upgrade != w.Upgrade || g.Structure != w.Structure || math.Abs(g.Start-w.Start) > 0.1 || math.Abs(g.Done-w.Done) > 0.1 {
+					t.Errorf("research %d = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
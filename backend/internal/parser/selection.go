@@ -0,0 +1,150 @@
+package parser
+
+// Control-Group-Update Typen (s2protocol)
+const (
+	ControlGroupSet = iota
+	ControlGroupAppend
+	ControlGroupRecall
+	ControlGroupClear
+	ControlGroupSetAndSteal
+	ControlGroupAppendAndSteal
+)
+
+// ActiveSelection ist die Control-Group-ID der aktuellen Auswahl in SelectionDelta-Events
+const ActiveSelection = 10
+
+// Selections verfolgt die Auswahl eines Spielers (0-9 = Control Groups, 10 = aktuelle Auswahl)
+// Die Einträge sind Game-Unit-Tags (Index << 18 + Recycle)
+type Selections map[int][]int
+
+// Apply wendet ein SelectionDelta- oder ControlGroupUpdate-Event an
+func (s Selections) Apply(evt GameEvent) {
+	switch evt.EventType {
+	case "SelectionDelta":
+		groupID := GetInt(evt.Data, "controlGroupId")
+		delta, _ := evt.Data["delta"].(map[string]interface{})
+		s[groupID] = applySelectionDelta(s[groupID], delta)
+
+	case "ControlGroupUpdate":
+		index := GetInt(evt.Data, "controlGroupIndex")
+		if index < 0 || index > 9 {
+			return
+		}
+		// Die Maske entfernt Einheiten (z.B. tote) aus der Gruppe
+		if mask, ok := evt.Data["mask"].(map[string]interface{}); ok {
+			s[index] = applyRemoveMask(s[index], mask)
+		}
+		switch GetInt(evt.Data, "controlGroupUpdate") {
+		case ControlGroupRecall:
+			s[ActiveSelection] = append([]int(nil), s[index]...)
+		case ControlGroupClear:
+			s[index] = nil
+		case ControlGroupSet:
+			s[index] = append([]int(nil), s[ActiveSelection]...)
+		case ControlGroupSetAndSteal:
+			s.steal(index, s[ActiveSelection])
+			s[index] = append([]int(nil), s[ActiveSelection]...)
+		case ControlGroupAppend:
+			s[index] = appendUnique(s[index], s[ActiveSelection])
+		case ControlGroupAppendAndSteal:
+			s.steal(index, s[ActiveSelection])
+			s[index] = appendUnique(s[index], s[ActiveSelection])
+		}
+	}
+}
+
+// Active liefert die aktuelle Auswahl
+func (s Selections) Active() []int {
+	return s[ActiveSelection]
+}
+
+// steal entfernt Einheiten aus allen anderen Control Groups
+func (s Selections) steal(target int, tags []int) {
+	stolen := make(map[int]bool, len(tags))
+	for _, tag := range tags {
+		stolen[tag] = true
+	}
+	for index := 0; index <= 9; index++ {
+		if index == target {
+			continue
+		}
+		kept := s[index][:0]
+		for _, tag := range s[index] {
+			if !stolen[tag] {
+				kept = append(kept, tag)
+			}
+		}
+		s[index] = kept
+	}
+}
+
+// applySelectionDelta wendet ein SelectionDelta auf eine Auswahl an
+func applySelectionDelta(tags []int, delta map[string]interface{}) []int {
+	if delta == nil {
+		return tags
+	}
+	if mask, ok := delta["removeMask"].(map[string]interface{}); ok {
+		tags = applyRemoveMask(tags, mask)
+	}
+	if added, ok := delta["addUnitTags"].([]interface{}); ok {
+		for _, tag := range added {
+			tags = append(tags, ToInt(tag))
+		}
+	}
+	return tags
+}
+
+// applyRemoveMask entfernt Einheiten gemäß der Remove-Maske (None, Mask, OneIndices, ZeroIndices)
+func applyRemoveMask(tags []int, mask map[string]interface{}) []int {
+	remove := make(map[int]bool)
+
+	if bits, ok := mask["Mask"].([]bool); ok {
+		for i, bit := range bits {
+			if bit {
+				remove[i] = true
+			}
+		}
+	} else if indices, ok := mask["OneIndices"].([]interface{}); ok {
+		for _, idx := range indices {
+			remove[ToInt(idx)] = true
+		}
+	} else if indices, ok := mask["ZeroIndices"].([]interface{}); ok {
+		// ZeroIndices: nur diese Einheiten bleiben ausgewählt
+		keep := make(map[int]bool)
+		for _, idx := range indices {
+			keep[ToInt(idx)] = true
+		}
+		for i := range tags {
+			if !keep[i] {
+				remove[i] = true
+			}
+		}
+	}
+
+	if len(remove) == 0 {
+		return tags
+	}
+
+	result := make([]int, 0, len(tags))
+	for i, tag := range tags {
+		if !remove[i] {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// appendUnique hängt Einheiten an eine Gruppe an, ohne Duplikate
+func appendUnique(tags []int, added []int) []int {
+	existing := make(map[int]bool, len(tags))
+	for _, tag := range tags {
+		existing[tag] = true
+	}
+	for _, tag := range added {
+		if !existing[tag] {
+			tags = append(tags, tag)
+			existing[tag] = true
+		}
+	}
+	return tags
+}
@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"math"
+	"strings"
+)
+
+// townHalls sind alle Hauptgebäude-Typen inklusive abgehobener Command Center (Kleinbuchstaben)
+var townHalls = map[string]bool{
+	"commandcenter": true, "commandcenterflying": true,
+	"orbitalcommand": true, "orbitalcommandflying": true,
+	"planetaryfortress": true,
+	"nexus":             true,
+	"hatchery":          true, "lair": true, "hive": true,
+}
+
+// workers sind alle Worker-Typen (Kleinbuchstaben)
+var workers = map[string]bool{
+	"scv": true, "probe": true, "drone": true, "droneburrowed": true,
+}
+
+// buildings sind Teilstrings, an denen Gebäude-Typen erkannt werden (Kleinbuchstaben)
+var buildings = []string{
+	// Terran
+	"commandcenter", "orbitalcommand", "planetaryfortress",
+	"supplydepot", "barracks", "factory", "starport",
+	"engineeringbay", "armory", "ghostacademy", "fusioncore",
+	"bunker", "missileturret", "sensortower", "refinery",
+	"techlab", "reactor",
+	// Protoss
+	"nexus", "pylon", "gateway", "warpgate", "forge", "cyberneticscore",
+	"roboticsfacility", "roboticsbay", "stargate", "fleetbeacon",
+	"twilightcouncil", "templararchive", "darkshrine",
+	"photoncannon", "shieldbattery", "assimilator",
+	// Zerg
+	"hatchery", "lair", "hive", "spawningpool", "evolutionchamber",
+	"roachwarren", "banelingnest", "hydraliskden", "lurkerden",
+	"spire", "greaterspire", "infestationpit", "ultraliskcavern",
+	"nydusnetwork", "nyduscanal", "spinecrawler", "sporecrawler", "extractor",
+}
+
+// IsTownHall prüft ob ein Einheitentyp ein Hauptgebäude ist
+func IsTownHall(unitType string) bool {
+	return townHalls[strings.ToLower(unitType)]
+}
+
+const (
+	// naturalMinDistance: näher an der Main stehende Hauptgebäude sind noch in der Main
+	// (z.B. ein Command Center, das später zur Natural fliegt)
+	naturalMinDistance = 18.0
+	// naturalMaxDistance ist der größte Abstand (Map-Zellen) zwischen Main und Natural
+	naturalMaxDistance = 50.0
+)
+
+// NaturalIndex bestimmt unter den Hauptgebäuden eines Spielers (Index 0 = Main) die Natural:
+// das der Main nächste Hauptgebäude außerhalb der Main, höchstens naturalMaxDistance entfernt.
+// pos liefert die Position des i-ten Hauptgebäudes in Map-Zellen; -1 = keine Natural
+func NaturalIndex(n int, pos func(i int) (x, y float64)) int {
+	if n < 2 {
+		return -1
+	}
+	mainX, mainY := pos(0)
+	natural := -1
+	bestDist := naturalMaxDistance
+	for i := 1; i < n; i++ {
+		x, y := pos(i)
+		d := math.Hypot(x-mainX, y-mainY)
+		if d < naturalMinDistance || d > bestDist || (natural >= 0 && d == bestDist) {
+			continue
+		}
+		natural = i
+		bestDist = d
+	}
+	return natural
+}
+
+// IsWorker prüft ob ein Einheitentyp ein Worker ist
+func IsWorker(unitType string) bool {
+	return workers[strings.ToLower(unitType)]
+}
+
+// supportUnits sind Teilstrings von Hilfseinheiten, die weder Worker noch Gebäude sind
+// und nicht zur Armee zählen (Kleinbuchstaben)
+var supportUnits = []string{
+	"mule", "larva", "egg", "cocoon", "broodling", "locust", "interceptor",
+	"autoturret", "changeling", "creeptumor", "overlord", "phaseshift",
+	"disruptorphased", "beacon",
+}
+
+// IsArmyUnit prüft ob eine Einheit zur Armee zählt (keine Worker, Gebäude oder Hilfseinheiten)
+func IsArmyUnit(unitType string) bool {
+	if unitType == "" || IsWorker(unitType) || IsBuilding(unitType) {
+		return false
+	}
+	lowerType := strings.ToLower(unitType)
+	for _, s := range supportUnits {
+		if strings.Contains(lowerType, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsBuilding prüft ob ein Einheitentyp ein Gebäude ist (Creep-Tumore zählen nicht)
+func IsBuilding(unitType string) bool {
+	lowerType := strings.ToLower(unitType)
+	for _, b := range buildings {
+		if strings.Contains(lowerType, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// researchStructures sind Gebäude, die ausschließlich forschen (Kleinbuchstaben)
+// Produktionsgebäude und Hauptgebäude fehlen, weil ihre Befehle ohne Ziel meist Einheiten bauen
+var researchStructures = map[string]bool{
+	// Terran
+	"engineeringbay": true, "armory": true, "ghostacademy": true, "fusioncore": true,
+	"techlab": true, "barrackstechlab": true, "factorytechlab": true, "starporttechlab": true,
+	// Protoss
+	"forge": true, "cyberneticscore": true, "twilightcouncil": true, "roboticsbay": true,
+	"fleetbeacon": true, "templararchive": true, "darkshrine": true,
+	// Zerg
+	"spawningpool": true, "evolutionchamber": true, "roachwarren": true, "banelingnest": true,
+	"hydraliskden": true, "lurkerden": true, "lurkerdenmp": true, "spire": true, "greaterspire": true,
+	"infestationpit": true, "ultraliskcavern": true,
+}
+
+// IsResearchStructure prüft ob ein Einheitentyp ein reines Forschungsgebäude ist
+func IsResearchStructure(unitType string) bool {
+	return researchStructures[strings.ToLower(unitType)]
+}
@@ -0,0 +1,81 @@
+package parser
+
+import "testing"
+
+func TestIsBuilding(t *testing.T) {
+	tests := []struct {
+		unitType string
+		want     bool
+	}{
+		{"UltraliskCavern", true},
+		{"NydusCanal", true},
+		{"NydusNetwork", true},
+		{"WarpGate", true},
+		{"BarracksTechLab", true},
+		{"SupplyDepotLowered", true},
+		{"CommandCenterFlying", true},
+		{"LurkerDenMP", true},
+		{"CreepTumorBurrowed", false},
+		{"Ultralisk", false},
+		{"Marine", false},
+		{"Probe", false},
+	}
+	for _, tt := range tests {
+		if got := IsBuilding(tt.unitType); got != tt.want {
+			t.Errorf("IsBuilding(%q) = %v, want %v", tt.unitType, got, tt.want)
+		}
+	}
+}
+
+func TestIsArmyUnit(t *testing.T) {
+	tests := []struct {
+		unitType string
+		want     bool
+	}{
+		{"Marine", true},
+		{"Ultralisk", true},
+		{"OverseerSiegeMode", true},
+		{"SiegeTankSieged", true},
+		{"", false},
+		{"SCV", false},
+		{"MULE", false},
+		{"Overlord", false},
+		{"OverlordTransport", false},
+		{"Changeling", false},
+		{"AutoTurret", false},
+		{"AdeptPhaseShift", false},
+		{"LocustMP", false},
+		{"BanelingCocoon", false},
+		{"CreepTumorBurrowed", false},
+		{"NydusCanal", false},
+		{"Barracks", false},
+	}
+	for _, tt := range tests {
+		if got := IsArmyUnit(tt.unitType); got != tt.want {
+			t.Errorf("IsArmyUnit(%q) = %v, want %v", tt.unitType, got, tt.want)
+		}
+	}
+}
+
+func TestNaturalIndex(t *testing.T) {
+	tests := []struct {
+		name  string
+		bases [][2]float64
+		want  int
+	}{
+		{"main only", [][2]float64{{40, 40}}, -1},
+		{"natural second", [][2]float64{{40, 40}, {65, 50}}, 1},
+		{"third base built first", [][2]float64{{44, 104}, {38, 20}, {22, 105}}, 2},
+		{"command center in the main", [][2]float64{{137, 83}, {130, 88}, {137, 105}}, 2},
+		{"only distant bases", [][2]float64{{40, 40}, {120, 40}}, -1},
+		{"rebuilt natural keeps the first", [][2]float64{{40, 40}, {65, 50}, {65, 50}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NaturalIndex(len(tt.bases), func(i int) (float64, float64) { return tt.bases[i][0], tt.bases[i][1] })
+			if got != tt.want {
+				t.Errorf("NaturalIndex() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
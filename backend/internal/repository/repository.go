@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -98,6 +100,60 @@ func (r *Repository) migrate() error {
 		FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE CASCADE
 	);
 
+	CREATE TABLE IF NOT EXISTS strategic_analyses (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		replay_id INTEGER NOT NULL,
+		player_id INTEGER NOT NULL,
+		result TEXT,
+		data TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(replay_id, player_id),
+		FOREIGN KEY (replay_id) REFERENCES replays(id) ON DELETE CASCADE,
+		FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS strategic_analysis_translations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		replay_id INTEGER NOT NULL,
+		player_id INTEGER NOT NULL,
+		language TEXT NOT NULL,
+		data TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(replay_id, player_id, language),
+		FOREIGN KEY (replay_id) REFERENCES replays(id) ON DELETE CASCADE,
+		FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS reference_replays (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		replay_id INTEGER NOT NULL,
+		player_id INTEGER NOT NULL,
+		player TEXT NOT NULL,
+		matchup TEXT NOT NULL,
+		build TEXT,
+		created_by INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(replay_id, player_id),
+		FOREIGN KEY (replay_id) REFERENCES replays(id) ON DELETE CASCADE,
+		FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_reference_replays_matchup ON reference_replays(matchup);
+
+	CREATE TABLE IF NOT EXISTS game_signatures (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		replay_id INTEGER NOT NULL,
+		player_id INTEGER NOT NULL,
+		opponent_id INTEGER,
+		matchup TEXT NOT NULL,
+		build TEXT NOT NULL,
+		opponent_build TEXT,
+		UNIQUE(replay_id, player_id),
+		FOREIGN KEY (replay_id) REFERENCES replays(id) ON DELETE CASCADE,
+		FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_game_signatures_matchup ON game_signatures(matchup);
 	CREATE INDEX IF NOT EXISTS idx_replays_played_at ON replays(played_at);
 	CREATE INDEX IF NOT EXISTS idx_replays_hash ON replays(hash);
 	CREATE INDEX IF NOT EXISTS idx_game_players_replay ON game_players(replay_id);
@@ -206,6 +262,30 @@ func (r *Repository) migrate() error {
 	// Migration: player_id zu user_replays hinzufügen
 	r.db.Exec(`ALTER TABLE user_replays ADD COLUMN player_id INTEGER`)
 
+	// Migration: Zusatz-Metriken (Phasen, Benchmarks) für Fortschritt und Wochenberichte
+	r.db.Exec(`ALTER TABLE daily_progress ADD COLUMN metric_averages TEXT`)
+	r.db.Exec(`ALTER TABLE daily_progress ADD COLUMN metric_games TEXT`)
+	r.db.Exec(`ALTER TABLE weekly_reports ADD COLUMN metric_averages TEXT`)
+
+	// Migration: bevorzugte Sprache für generierte Texte und Fehlermeldungen
+	r.db.Exec(`ALTER TABLE users ADD COLUMN language TEXT`)
+
+	// Migration: Random-Wahl in der Lobby (Race enthält die tatsächlich gespielte Rasse)
+	r.db.Exec(`ALTER TABLE game_players ADD COLUMN random_race INTEGER DEFAULT 0`)
+
+	// Migration: Zeitpunkt des Verlassens für Spiele ohne Ergebnis
+	r.db.Exec(`ALTER TABLE game_players ADD COLUMN left_at INTEGER DEFAULT 0`)
+
+	// Migration: wiederkehrende Probleme aus den strategischen Analysen der Woche
+	r.db.Exec(`ALTER TABLE weekly_reports ADD COLUMN recurring_weaknesses TEXT`)
+
+	// Migration: Rollen (admin, coach) für die Verwaltung von Referenz-Replays
+	r.db.Exec(`ALTER TABLE users ADD COLUMN role TEXT`)
+
+	// Migration: Version von Analyse und Wissensbasis, mit der eine strategische Analyse erstellt wurde
+	r.db.Exec(`ALTER TABLE strategic_analyses ADD COLUMN version TEXT`)
+	r.db.Exec(`ALTER TABLE strategic_analysis_translations ADD COLUMN version TEXT`)
+
 	return nil
 }
 
@@ -381,10 +461,10 @@ func (r *Repository) DeleteReplay(id int64) error {
 // CreateGamePlayer speichert einen Spieler für ein Replay
 func (r *Repository) CreateGamePlayer(gp *models.GamePlayer) error {
 	_, err := r.db.Exec(
-		`INSERT INTO game_players (replay_id, player_id, player_slot, name, race, result, apm, spending_quotient, is_human)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		`INSERT INTO game_players (replay_id, player_id, player_slot, name, race, result, apm, spending_quotient, is_human, random_race, left_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		gp.ReplayID, gp.PlayerID, gp.PlayerSlot, gp.Name, gp.Race, gp.Result,
-		gp.APM, gp.SpendingQuotient, gp.IsHuman,
+		gp.APM, gp.SpendingQuotient, gp.IsHuman, gp.RandomRace, gp.LeftAt,
 	)
 	return err
 }
@@ -392,8 +472,11 @@ func (r *Repository) CreateGamePlayer(gp *models.GamePlayer) error {
 // GetGamePlayersByReplayID gibt alle Spieler eines Replays zurück
 func (r *Repository) GetGamePlayersByReplayID(replayID int64) ([]models.GamePlayer, error) {
 	rows, err := r.db.Query(
-		`SELECT replay_id, player_id, player_slot, name, race, result, apm, spending_quotient, is_human
-		 FROM game_players WHERE replay_id = ? ORDER BY player_slot`,
+		`SELECT gp.replay_id, gp.player_id, gp.player_slot, gp.name, gp.race, gp.result, gp.apm, gp.spending_quotient, gp.is_human,
+		        COALESCE(gp.random_race, 0), COALESCE(gp.left_at, 0), COALESCE(p.toon_handle, '')
+		 FROM game_players gp
+		 LEFT JOIN players p ON p.id = gp.player_id
+		 WHERE gp.replay_id = ? ORDER BY gp.player_slot`,
 		replayID,
 	)
 	if err != nil {
@@ -405,7 +488,7 @@ func (r *Repository) GetGamePlayersByReplayID(replayID int64) ([]models.GamePlay
 	for rows.Next() {
 		var gp models.GamePlayer
 		err := rows.Scan(&gp.ReplayID, &gp.PlayerID, &gp.PlayerSlot, &gp.Name,
-			&gp.Race, &gp.Result, &gp.APM, &gp.SpendingQuotient, &gp.IsHuman)
+			&gp.Race, &gp.Result, &gp.APM, &gp.SpendingQuotient, &gp.IsHuman, &gp.RandomRace, &gp.LeftAt, &gp.ToonHandle)
 		if err != nil {
 			return nil, err
 		}
@@ -488,6 +571,229 @@ func (r *Repository) GetAnalysesByReplayID(replayID int64) ([]models.Analysis, e
 	return analyses, rows.Err()
 }
 
+// SaveStrategicAnalysis speichert die strategische Analyse aus Sicht eines Spielers
+// version kennzeichnet Analyse und Wissensbasis; Übersetzungen der vorherigen Analyse werden verworfen
+func (r *Repository) SaveStrategicAnalysis(replayID, playerID int64, version string, sa *models.StrategicAnalysis) error {
+	data, err := json.Marshal(sa)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`INSERT OR REPLACE INTO strategic_analyses (replay_id, player_id, result, data, version)
+		 VALUES (?, ?, ?, ?, ?)`,
+		replayID, playerID, sa.Result, string(data), version,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`DELETE FROM strategic_analysis_translations WHERE replay_id = ? AND player_id = ?`,
+		replayID, playerID,
+	)
+	return err
+}
+
+// DeleteStrategicAnalysis löscht die strategische Analyse eines Spielers samt Übersetzungen
+func (r *Repository) DeleteStrategicAnalysis(replayID, playerID int64) error {
+	_, err := r.db.Exec(
+		`DELETE FROM strategic_analyses WHERE replay_id = ? AND player_id = ?`,
+		replayID, playerID,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`DELETE FROM strategic_analysis_translations WHERE replay_id = ? AND player_id = ?`,
+		replayID, playerID,
+	)
+	return err
+}
+
+// SaveStrategicAnalysisTranslation speichert die strategische Analyse in einer weiteren Sprache
+func (r *Repository) SaveStrategicAnalysisTranslation(replayID, playerID int64, lang, version string, sa *models.StrategicAnalysis) error {
+	data, err := json.Marshal(sa)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`INSERT OR REPLACE INTO strategic_analysis_translations (replay_id, player_id, language, data, version)
+		 VALUES (?, ?, ?, ?, ?)`,
+		replayID, playerID, lang, string(data), version,
+	)
+	return err
+}
+
+// GetStrategicAnalysisTranslation lädt die gespeicherte strategische Analyse in einer weiteren Sprache
+// Analysen mit anderer Version gelten als nicht vorhanden
+func (r *Repository) GetStrategicAnalysisTranslation(replayID, playerID int64, lang, version string) (*models.StrategicAnalysis, error) {
+	var data string
+	err := r.db.QueryRow(
+		`SELECT data FROM strategic_analysis_translations
+		 WHERE replay_id = ? AND player_id = ? AND language = ? AND version = ?`,
+		replayID, playerID, lang, version,
+	).Scan(&data)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sa models.StrategicAnalysis
+	if err := json.Unmarshal([]byte(data), &sa); err != nil {
+		return nil, err
+	}
+	return &sa, nil
+}
+
+// GetStrategicAnalysis lädt die gespeicherte strategische Analyse aus Sicht eines Spielers
+// Analysen mit anderer Version gelten als nicht vorhanden
+func (r *Repository) GetStrategicAnalysis(replayID, playerID int64, version string) (*models.StrategicAnalysis, error) {
+	var data string
+	err := r.db.QueryRow(
+		`SELECT data FROM strategic_analyses WHERE replay_id = ? AND player_id = ? AND version = ?`,
+		replayID, playerID, version,
+	).Scan(&data)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sa models.StrategicAnalysis
+	if err := json.Unmarshal([]byte(data), &sa); err != nil {
+		return nil, err
+	}
+	return &sa, nil
+}
+
+// GetClaimedWithoutStrategicAnalysis gibt zugeordnete Replays zurück, für deren Spieler noch
+// keine strategische Analyse in der angegebenen Version gespeichert ist (Replay-ID -> Spieler-IDs)
+func (r *Repository) GetClaimedWithoutStrategicAnalysis(version string) (map[int64][]int64, error) {
+	rows, err := r.db.Query(
+		`SELECT DISTINCT ur.replay_id, ur.player_id
+		 FROM user_replays ur
+		 LEFT JOIN strategic_analyses sa ON sa.replay_id = ur.replay_id AND sa.player_id = ur.player_id
+		 WHERE ur.player_id IS NOT NULL AND (sa.id IS NULL OR COALESCE(sa.version, '') != ?)`,
+		version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	missing := make(map[int64][]int64)
+	for rows.Next() {
+		var replayID, playerID int64
+		if err := rows.Scan(&replayID, &playerID); err != nil {
+			return nil, err
+		}
+		missing[replayID] = append(missing[replayID], playerID)
+	}
+	return missing, rows.Err()
+}
+
+// GetRecurringProblems ermittelt Probleme, die in mindestens minCount der letzten games
+// Spiele eines Benutzers mit strategischer Analyse auftreten
+func (r *Repository) GetRecurringProblems(userID int64, games, minCount int) ([]models.RecurringProblem, error) {
+	rows, err := r.db.Query(
+		`SELECT r.id, r.played_at, sa.data
+		 FROM replays r
+		 JOIN user_replays ur ON ur.replay_id = r.id
+		 JOIN strategic_analyses sa ON sa.replay_id = r.id AND sa.player_id = ur.player_id
+		 WHERE ur.user_id = ? AND ur.player_id IS NOT NULL
+		 ORDER BY r.played_at DESC
+		 LIMIT ?`,
+		userID, games,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return aggregateRecurringProblems(rows, minCount)
+}
+
+// getRecurringProblemsForPeriod ermittelt wiederkehrende Probleme der Spiele in einem Zeitraum
+func (r *Repository) getRecurringProblemsForPeriod(userID int64, start, end time.Time, minCount int) ([]models.RecurringProblem, error) {
+	rows, err := r.db.Query(
+		`SELECT r.id, r.played_at, sa.data
+		 FROM replays r
+		 JOIN user_replays ur ON ur.replay_id = r.id
+		 JOIN strategic_analyses sa ON sa.replay_id = r.id AND sa.player_id = ur.player_id
+		 WHERE ur.user_id = ? AND ur.player_id IS NOT NULL
+		   AND r.played_at >= ? AND r.played_at < ?
+		 ORDER BY r.played_at DESC`,
+		userID, start.Format("2006-01-02"), end.AddDate(0, 0, 1).Format("2006-01-02"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return aggregateRecurringProblems(rows, minCount)
+}
+
+// aggregateRecurringProblems zählt je Problem-Schlüssel die Spiele, in denen es vorkam
+// Fehler trotz Sieg zählen mit, jedes Spiel zählt pro Problem höchstens einmal
+func aggregateRecurringProblems(rows *sql.Rows, minCount int) ([]models.RecurringProblem, error) {
+	defer rows.Close()
+
+	byKey := make(map[string]*models.RecurringProblem)
+	var order []string
+	games := 0
+	for rows.Next() {
+		var replayID int64
+		var playedAt time.Time
+		var data string
+		if err := rows.Scan(&replayID, &playedAt, &data); err != nil {
+			return nil, err
+		}
+		var sa struct {
+			Problems           []models.IdentifiedProblem `json:"problems"`
+			UnpunishedMistakes []models.IdentifiedProblem `json:"unpunished_mistakes"`
+		}
+		if err := json.Unmarshal([]byte(data), &sa); err != nil {
+			continue
+		}
+		games++
+
+		seen := make(map[string]bool)
+		for _, p := range append(sa.Problems, sa.UnpunishedMistakes...) {
+			if p.Key == "" || seen[p.Key] {
+				continue
+			}
+			seen[p.Key] = true
+			rp, ok := byKey[p.Key]
+			if !ok {
+				// Zeilen kommen absteigend nach Datum, das erste Vorkommen ist das jüngste
+				rp = &models.RecurringProblem{Key: p.Key, LastSeen: playedAt}
+				byKey[p.Key] = rp
+				order = append(order, p.Key)
+			}
+			rp.Count++
+			rp.ReplayIDs = append(rp.ReplayIDs, replayID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	recurring := []models.RecurringProblem{}
+	for _, key := range order {
+		rp := byKey[key]
+		if rp.Count < minCount {
+			continue
+		}
+		rp.Games = games
+		rp.Frequency = float64(rp.Count) / float64(games) * 100
+		recurring = append(recurring, *rp)
+	}
+	sort.SliceStable(recurring, func(i, j int) bool {
+		return recurring[i].Count > recurring[j].Count
+	})
+	return recurring, nil
+}
+
 // GetPlayerTrends gibt Trend-Daten für einen Spieler zurück
 func (r *Repository) GetPlayerTrends(playerID int64, limit int) ([]models.TrendPoint, error) {
 	rows, err := r.db.Query(
@@ -566,6 +872,9 @@ func (r *Repository) GetAllPlayerMetrics(playerID int64, limit int) ([]map[strin
 				if data.InjectAnalysis != nil {
 					result["inject_efficiency"] = data.InjectAnalysis.Efficiency
 				}
+				for key, value := range data.GoalMetrics() {
+					result[key] = value
+				}
 			}
 		}
 
@@ -605,11 +914,12 @@ func (r *Repository) CreateUser(email, passwordHash, sc2PlayerName string) (*mod
 func (r *Repository) GetUserByEmail(email string) (*models.User, error) {
 	var user models.User
 	var lastLogin sql.NullTime
+	var language, role sql.NullString
 	err := r.db.QueryRow(
-		`SELECT id, email, password_hash, sc2_player_name, created_at, last_login
+		`SELECT id, email, password_hash, sc2_player_name, language, role, created_at, last_login
 		 FROM users WHERE email = ?`,
 		email,
-	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.SC2PlayerName, &user.CreatedAt, &lastLogin)
+	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.SC2PlayerName, &language, &role, &user.CreatedAt, &lastLogin)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -620,6 +930,8 @@ func (r *Repository) GetUserByEmail(email string) (*models.User, error) {
 	if lastLogin.Valid {
 		user.LastLogin = &lastLogin.Time
 	}
+	user.Language = language.String
+	user.Role = role.String
 	return &user, nil
 }
 
@@ -627,11 +939,12 @@ func (r *Repository) GetUserByEmail(email string) (*models.User, error) {
 func (r *Repository) GetUserByID(id int64) (*models.User, error) {
 	var user models.User
 	var lastLogin sql.NullTime
+	var language, role sql.NullString
 	err := r.db.QueryRow(
-		`SELECT id, email, password_hash, sc2_player_name, created_at, last_login
+		`SELECT id, email, password_hash, sc2_player_name, language, role, created_at, last_login
 		 FROM users WHERE id = ?`,
 		id,
-	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.SC2PlayerName, &user.CreatedAt, &lastLogin)
+	).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.SC2PlayerName, &language, &role, &user.CreatedAt, &lastLogin)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -642,6 +955,8 @@ func (r *Repository) GetUserByID(id int64) (*models.User, error) {
 	if lastLogin.Valid {
 		user.LastLogin = &lastLogin.Time
 	}
+	user.Language = language.String
+	user.Role = role.String
 	return &user, nil
 }
 
@@ -654,6 +969,29 @@ func (r *Repository) UpdateUserLastLogin(userID int64) error {
 	return err
 }
 
+// UpdateUserLanguage speichert die bevorzugte Sprache eines Benutzers ("" = automatisch)
+func (r *Repository) UpdateUserLanguage(userID int64, language string) error {
+	_, err := r.db.Exec(
+		`UPDATE users SET language = ? WHERE id = ?`,
+		sql.NullString{String: language, Valid: language != ""}, userID,
+	)
+	return err
+}
+
+// SetUserRole setzt die Rolle eines Benutzers per Email (leer = Spieler)
+// Gibt false zurück, wenn kein Benutzer mit dieser Email existiert
+func (r *Repository) SetUserRole(email, role string) (bool, error) {
+	result, err := r.db.Exec(
+		`UPDATE users SET role = ? WHERE email = ?`,
+		sql.NullString{String: role, Valid: role != ""}, email,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
 // LinkReplayToUser verknüpft ein Replay mit einem Benutzer und speichert die player_id
 func (r *Repository) LinkReplayToUser(userID, replayID, playerID int64) error {
 	_, err := r.db.Exec(
@@ -676,6 +1014,44 @@ func (r *Repository) UserOwnsReplay(userID, replayID int64) (bool, error) {
 	return count > 0, nil
 }
 
+// GetUserReplayPlayerID gibt die player_id zurück, mit der ein Benutzer ein Replay verknüpft hat (0 = keine)
+func (r *Repository) GetUserReplayPlayerID(userID, replayID int64) (int64, error) {
+	var playerID sql.NullInt64
+	err := r.db.QueryRow(
+		`SELECT player_id FROM user_replays WHERE user_id = ? AND replay_id = ?`,
+		userID, replayID,
+	).Scan(&playerID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return playerID.Int64, nil
+}
+
+// GetReplayClaims gibt die Benutzer zurück, die ein Replay verknüpft haben (Benutzer-ID -> Spieler-ID)
+func (r *Repository) GetReplayClaims(replayID int64) (map[int64]int64, error) {
+	rows, err := r.db.Query(
+		`SELECT user_id, COALESCE(player_id, 0) FROM user_replays WHERE replay_id = ?`,
+		replayID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	claims := make(map[int64]int64)
+	for rows.Next() {
+		var userID, playerID int64
+		if err := rows.Scan(&userID, &playerID); err != nil {
+			return nil, err
+		}
+		claims[userID] = playerID
+	}
+	return claims, rows.Err()
+}
+
 // CountUserReplays gibt die Anzahl der Replays eines Benutzers zurück
 func (r *Repository) CountUserReplays(userID int64) (int, error) {
 	var count int
@@ -720,6 +1096,303 @@ func (r *Repository) GetUserReplays(userID int64, limit, offset int) ([]models.R
 	return replays, rows.Err()
 }
 
+// ============== Reference Replay Methods ==============
+
+// referenceColumns sind die Spalten für scanReferenceReplay
+const referenceColumns = `rr.id, rr.replay_id, rr.player_id, COALESCE(gp.name, ''), rr.player, rr.matchup,
+	COALESCE(rr.build, ''), r.map, r.game_version, COALESCE(rr.created_by, 0), rr.created_at
+	FROM reference_replays rr
+	JOIN replays r ON r.id = rr.replay_id
+	LEFT JOIN game_players gp ON gp.replay_id = rr.replay_id AND gp.player_id = rr.player_id`
+
+// scanReferenceReplay liest eine Zeile mit referenceColumns
+func scanReferenceReplay(scanner interface{ Scan(...interface{}) error }) (*models.ReferenceReplay, error) {
+	var ref models.ReferenceReplay
+	err := scanner.Scan(&ref.ID, &ref.ReplayID, &ref.PlayerID, &ref.PlayerName, &ref.Player, &ref.Matchup,
+		&ref.Build, &ref.Map, &ref.GameVersion, &ref.CreatedBy, &ref.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &ref, nil
+}
+
+// SaveReferenceReplay markiert ein Spiel als Referenz bzw. aktualisiert die Tags einer bestehenden Referenz
+func (r *Repository) SaveReferenceReplay(ref *models.ReferenceReplay) (*models.ReferenceReplay, error) {
+	_, err := r.db.Exec(
+		`INSERT INTO reference_replays (replay_id, player_id, player, matchup, build, created_by)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(replay_id, player_id) DO UPDATE SET player = excluded.player, matchup = excluded.matchup, build = excluded.build`,
+		ref.ReplayID, ref.PlayerID, ref.Player, ref.Matchup,
+		sql.NullString{String: ref.Build, Valid: ref.Build != ""}, ref.CreatedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanReferenceReplay(r.db.QueryRow(
+		`SELECT `+referenceColumns+` WHERE rr.replay_id = ? AND rr.player_id = ?`,
+		ref.ReplayID, ref.PlayerID,
+	))
+}
+
+// GetReferenceReplay lädt ein Referenz-Replay
+func (r *Repository) GetReferenceReplay(id int64) (*models.ReferenceReplay, error) {
+	ref, err := scanReferenceReplay(r.db.QueryRow(`SELECT `+referenceColumns+` WHERE rr.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return ref, err
+}
+
+// ListReferenceReplays gibt alle Referenz-Replays zurück, optional nur eines Matchups (z.B. PvZ)
+func (r *Repository) ListReferenceReplays(matchup string) ([]models.ReferenceReplay, error) {
+	query := `SELECT ` + referenceColumns
+	var args []interface{}
+	if matchup != "" {
+		query += ` WHERE rr.matchup = ?`
+		args = append(args, matchup)
+	}
+	query += ` ORDER BY rr.matchup, rr.created_at DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []models.ReferenceReplay{}
+	for rows.Next() {
+		ref, err := scanReferenceReplay(rows)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, *ref)
+	}
+	return refs, rows.Err()
+}
+
+// GetReferencePlayerIDs gibt die Spieler zurück, für die ein Replay als Referenz markiert ist
+func (r *Repository) GetReferencePlayerIDs(replayID int64) ([]int64, error) {
+	rows, err := r.db.Query(`SELECT player_id FROM reference_replays WHERE replay_id = ? ORDER BY id`, replayID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var playerIDs []int64
+	for rows.Next() {
+		var playerID int64
+		if err := rows.Scan(&playerID); err != nil {
+			return nil, err
+		}
+		playerIDs = append(playerIDs, playerID)
+	}
+	return playerIDs, rows.Err()
+}
+
+// DeleteReferenceReplay hebt die Markierung als Referenz auf; das Replay selbst bleibt erhalten
+func (r *Repository) DeleteReferenceReplay(id int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM reference_replays WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// ============== Similarity Index Methods ==============
+
+// signatureColumns sind die Spalten für scanGameSignature
+const signatureColumns = `gs.replay_id, gs.player_id, gp.name, gp.race, gp.result,
+	COALESCE(gs.opponent_id, 0), COALESCE(op.name, ''), COALESCE(op.race, ''), gs.matchup,
+	r.map, r.duration, r.played_at, gs.build, COALESCE(gs.opponent_build, '[]'), COALESCE(rr.player, '')
+	FROM game_signatures gs
+	JOIN replays r ON r.id = gs.replay_id
+	JOIN game_players gp ON gp.replay_id = gs.replay_id AND gp.player_id = gs.player_id
+	LEFT JOIN game_players op ON op.replay_id = gs.replay_id AND op.player_id = gs.opponent_id
+	LEFT JOIN reference_replays rr ON rr.replay_id = gs.replay_id AND rr.player_id = gs.player_id`
+
+// scanGameSignature liest eine Zeile mit signatureColumns
+func scanGameSignature(scanner interface{ Scan(...interface{}) error }) (*models.GameSignature, error) {
+	var sig models.GameSignature
+	var build, opponentBuild string
+	err := scanner.Scan(&sig.ReplayID, &sig.PlayerID, &sig.Name, &sig.Race, &sig.Result,
+		&sig.OpponentID, &sig.Opponent, &sig.OpponentRace, &sig.Matchup,
+		&sig.Map, &sig.Duration, &sig.PlayedAt, &build, &opponentBuild, &sig.Reference)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(build), &sig.Build); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(opponentBuild), &sig.OpponentBuild); err != nil {
+		return nil, err
+	}
+	return &sig, nil
+}
+
+// SaveGameSignature speichert den Eintrag eines Spielers im Ähnlichkeitsindex
+func (r *Repository) SaveGameSignature(sig *models.GameSignature) error {
+	build, err := json.Marshal(sig.Build)
+	if err != nil {
+		return err
+	}
+	opponentBuild, err := json.Marshal(sig.OpponentBuild)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`INSERT OR REPLACE INTO game_signatures (replay_id, player_id, opponent_id, matchup, build, opponent_build)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		sig.ReplayID, sig.PlayerID, sql.NullInt64{Int64: sig.OpponentID, Valid: sig.OpponentID != 0},
+		sig.Matchup, string(build), string(opponentBuild),
+	)
+	return err
+}
+
+// GetGameSignature lädt den Indexeintrag eines Spielers (nil = nicht indexiert)
+func (r *Repository) GetGameSignature(replayID, playerID int64) (*models.GameSignature, error) {
+	sig, err := scanGameSignature(r.db.QueryRow(
+		`SELECT `+signatureColumns+` WHERE gs.replay_id = ? AND gs.player_id = ?`,
+		replayID, playerID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return sig, err
+}
+
+// GetSimilarityCandidates gibt die Indexeinträge der eigenen Spiele eines Benutzers
+// (als verknüpfter Spieler) und aller Referenz-Replays zurück
+func (r *Repository) GetSimilarityCandidates(userID int64) ([]models.GameSignature, error) {
+	rows, err := r.db.Query(
+		`SELECT `+signatureColumns+`
+		 WHERE rr.id IS NOT NULL OR EXISTS (
+			SELECT 1 FROM user_replays ur
+			WHERE ur.user_id = ? AND ur.replay_id = gs.replay_id AND ur.player_id = gs.player_id
+		 )`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sigs []models.GameSignature
+	for rows.Next() {
+		sig, err := scanGameSignature(rows)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, *sig)
+	}
+	return sigs, rows.Err()
+}
+
+// GetAnalysesWithoutSignature gibt Replays zurück, deren analysierte Spieler noch nicht
+// im Ähnlichkeitsindex stehen (Replay-ID -> Spieler-IDs)
+func (r *Repository) GetAnalysesWithoutSignature() (map[int64][]int64, error) {
+	rows, err := r.db.Query(
+		`SELECT a.replay_id, a.player_id
+		 FROM analyses a
+		 LEFT JOIN game_signatures gs ON gs.replay_id = a.replay_id AND gs.player_id = a.player_id
+		 WHERE gs.id IS NULL`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	missing := make(map[int64][]int64)
+	for rows.Next() {
+		var replayID, playerID int64
+		if err := rows.Scan(&replayID, &playerID); err != nil {
+			return nil, err
+		}
+		missing[replayID] = append(missing[replayID], playerID)
+	}
+	return missing, rows.Err()
+}
+
+// GetOutdatedAnalysisReplays gibt die Replays zurück, deren gespeicherte Analysen älter als version sind
+func (r *Repository) GetOutdatedAnalysisReplays(version int) ([]int64, error) {
+	rows, err := r.db.Query(
+		`SELECT DISTINCT replay_id FROM analyses
+		 WHERE COALESCE(json_extract(data, '$.version'), 0) < ?
+		 ORDER BY replay_id`,
+		version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var replayIDs []int64
+	for rows.Next() {
+		var replayID int64
+		if err := rows.Scan(&replayID); err != nil {
+			return nil, err
+		}
+		replayIDs = append(replayIDs, replayID)
+	}
+	return replayIDs, rows.Err()
+}
+
+// opponentJoin verbindet den Spieler gp mit seinem Gegenspieler op: bevorzugt ein Mitspieler mit
+// anderem Ergebnis, sonst der erste andere Spieler (menschliche Spieler zuerst)
+const opponentJoin = `LEFT JOIN game_players op ON op.replay_id = gp.replay_id AND op.player_id = COALESCE(
+			(SELECT o.player_id FROM game_players o
+			 WHERE o.replay_id = gp.replay_id AND o.player_id != gp.player_id AND o.result != gp.result
+			 ORDER BY o.is_human DESC, o.player_slot LIMIT 1),
+			(SELECT o.player_id FROM game_players o
+			 WHERE o.replay_id = gp.replay_id AND o.player_id != gp.player_id
+			 ORDER BY o.is_human DESC, o.player_slot LIMIT 1)
+		 )`
+
+// GetOpponentGames gibt alle Spiele eines Spielers über alle Replays zurück, neuestes zuerst
+// Own markiert Spiele aus den Replays des Benutzers, HeadToHead davon die Spiele, in denen der
+// Gegenspieler der mit dem Replay verknüpfte Spieler ist. Die Build Order kommt aus der
+// gespeicherten Analyse des Spielers (leer ohne Analyse).
+func (r *Repository) GetOpponentGames(playerID, userID int64) ([]models.OpponentGame, error) {
+	rows, err := r.db.Query(
+		`SELECT r.id, r.map, r.duration, COALESCE(r.game_version, ''), r.played_at,
+		        gp.player_id, gp.name, gp.race, gp.result,
+		        COALESCE(op.player_id, 0), COALESCE(op.name, ''), COALESCE(op.race, ''),
+		        EXISTS (SELECT 1 FROM user_replays ur WHERE ur.user_id = ? AND ur.replay_id = r.id),
+		        EXISTS (SELECT 1 FROM user_replays ur WHERE ur.user_id = ? AND ur.replay_id = r.id AND ur.player_id = op.player_id),
+		        COALESCE(json_extract(a.data, '$.build_order'), '')
+		 FROM game_players gp
+		 JOIN replays r ON r.id = gp.replay_id
+		 `+opponentJoin+`
+		 LEFT JOIN analyses a ON a.replay_id = gp.replay_id AND a.player_id = gp.player_id
+		 WHERE gp.player_id = ?
+		 ORDER BY r.played_at DESC`,
+		userID, userID, playerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []models.OpponentGame
+	for rows.Next() {
+		var g models.OpponentGame
+		var buildOrder string
+		err := rows.Scan(&g.ReplayID, &g.Map, &g.Duration, &g.GameVersion, &g.PlayedAt,
+			&g.PlayerID, &g.Name, &g.Race, &g.Result,
+			&g.OpponentID, &g.Opponent, &g.OpponentRace, &g.Own, &g.HeadToHead, &buildOrder)
+		if err != nil {
+			return nil, err
+		}
+		if buildOrder != "" {
+			if err := json.Unmarshal([]byte(buildOrder), &g.BuildOrder); err != nil {
+				return nil, err
+			}
+		}
+		games = append(games, g)
+	}
+	return games, rows.Err()
+}
+
 // ============== Goal Methods ==============
 
 // CreateGoal erstellt ein neues Ziel
@@ -826,12 +1499,15 @@ func (r *Repository) GetOrCreateDailyProgress(userID int64, date time.Time) (*mo
 	dateStr := date.Format("2006-01-02")
 
 	var dp models.DailyProgress
+	var metricAverages, metricGames sql.NullString
 	err := r.db.QueryRow(
-		`SELECT id, user_id, date, games_played, wins, losses, avg_apm, avg_spending_quotient, avg_supply_block_pct, total_play_time
+		`SELECT id, user_id, date, games_played, wins, losses, avg_apm, avg_spending_quotient, avg_supply_block_pct, total_play_time,
+		        metric_averages, metric_games
 		 FROM daily_progress WHERE user_id = ? AND date = ?`,
 		userID, dateStr,
 	).Scan(&dp.ID, &dp.UserID, &dp.Date, &dp.GamesPlayed, &dp.Wins, &dp.Losses,
-		&dp.AvgAPM, &dp.AvgSpendingQuotient, &dp.AvgSupplyBlockPct, &dp.TotalPlayTime)
+		&dp.AvgAPM, &dp.AvgSpendingQuotient, &dp.AvgSupplyBlockPct, &dp.TotalPlayTime,
+		&metricAverages, &metricGames)
 
 	if err == sql.ErrNoRows {
 		// Erstelle neuen Eintrag
@@ -855,6 +1531,8 @@ func (r *Repository) GetOrCreateDailyProgress(userID int64, date time.Time) (*mo
 	if err != nil {
 		return nil, err
 	}
+	decodeJSONColumn(metricAverages, &dp.MetricAverages)
+	decodeJSONColumn(metricGames, &dp.MetricGames)
 	return &dp, nil
 }
 
@@ -864,19 +1542,37 @@ func (r *Repository) UpdateDailyProgress(dp *models.DailyProgress) error {
 		`UPDATE daily_progress SET
 			games_played = ?, wins = ?, losses = ?,
 			avg_apm = ?, avg_spending_quotient = ?, avg_supply_block_pct = ?,
-			total_play_time = ?
+			total_play_time = ?, metric_averages = ?, metric_games = ?
 		 WHERE id = ?`,
 		dp.GamesPlayed, dp.Wins, dp.Losses,
 		dp.AvgAPM, dp.AvgSpendingQuotient, dp.AvgSupplyBlockPct,
-		dp.TotalPlayTime, dp.ID,
+		dp.TotalPlayTime, encodeJSONColumn(dp.MetricAverages), encodeJSONColumn(dp.MetricGames), dp.ID,
 	)
 	return err
 }
 
+// encodeJSONColumn serialisiert eine Map für eine TEXT-Spalte; leere Maps werden NULL
+func encodeJSONColumn(v interface{}) *string {
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" || string(data) == "{}" {
+		return nil
+	}
+	s := string(data)
+	return &s
+}
+
+// decodeJSONColumn liest eine JSON-TEXT-Spalte, NULL lässt das Ziel unverändert
+func decodeJSONColumn(col sql.NullString, v interface{}) {
+	if col.Valid && col.String != "" {
+		json.Unmarshal([]byte(col.String), v)
+	}
+}
+
 // GetProgressHistory gibt die Fortschrittshistorie zurück
 func (r *Repository) GetProgressHistory(userID int64, days int) ([]models.DailyProgress, error) {
 	rows, err := r.db.Query(
-		`SELECT id, user_id, date, games_played, wins, losses, avg_apm, avg_spending_quotient, avg_supply_block_pct, total_play_time
+		`SELECT id, user_id, date, games_played, wins, losses, avg_apm, avg_spending_quotient, avg_supply_block_pct, total_play_time,
+		        metric_averages
 		 FROM daily_progress
 		 WHERE user_id = ? AND date >= date('now', '-' || ? || ' days')
 		 ORDER BY date ASC`,
@@ -890,11 +1586,14 @@ func (r *Repository) GetProgressHistory(userID int64, days int) ([]models.DailyP
 	var progress []models.DailyProgress
 	for rows.Next() {
 		var dp models.DailyProgress
+		var metricAverages sql.NullString
 		err := rows.Scan(&dp.ID, &dp.UserID, &dp.Date, &dp.GamesPlayed, &dp.Wins, &dp.Losses,
-			&dp.AvgAPM, &dp.AvgSpendingQuotient, &dp.AvgSupplyBlockPct, &dp.TotalPlayTime)
+			&dp.AvgAPM, &dp.AvgSpendingQuotient, &dp.AvgSupplyBlockPct, &dp.TotalPlayTime,
+			&metricAverages)
 		if err != nil {
 			return nil, err
 		}
+		decodeJSONColumn(metricAverages, &dp.MetricAverages)
 		progress = append(progress, dp)
 	}
 	return progress, rows.Err()
@@ -906,17 +1605,19 @@ func (r *Repository) GetProgressHistory(userID int64, days int) ([]models.DailyP
 func (r *Repository) GetWeeklyReport(userID int64, weekStart time.Time) (*models.WeeklyReport, error) {
 	var wr models.WeeklyReport
 	var improvements, regressions, strengths, weaknesses sql.NullString
-	var mainRace sql.NullString
+	var mainRace, metricAverages, recurring sql.NullString
 
 	err := r.db.QueryRow(
 		`SELECT id, user_id, week_start, week_end, total_games, wins, losses, win_rate,
 		        avg_apm, avg_sq, avg_supply_block, main_race, total_play_time,
-		        improvements, regressions, focus_suggestion, strengths, weaknesses, generated_at
+		        improvements, regressions, focus_suggestion, strengths, weaknesses, generated_at,
+		        metric_averages, recurring_weaknesses
 		 FROM weekly_reports WHERE user_id = ? AND week_start = ?`,
 		userID, weekStart.Format("2006-01-02"),
 	).Scan(&wr.ID, &wr.UserID, &wr.WeekStart, &wr.WeekEnd, &wr.TotalGames, &wr.Wins, &wr.Losses,
 		&wr.WinRate, &wr.AvgAPM, &wr.AvgSQ, &wr.AvgSupplyBlock, &mainRace, &wr.TotalPlayTime,
-		&improvements, &regressions, &wr.FocusSuggestion, &strengths, &weaknesses, &wr.GeneratedAt)
+		&improvements, &regressions, &wr.FocusSuggestion, &strengths, &weaknesses, &wr.GeneratedAt,
+		&metricAverages, &recurring)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -940,6 +1641,8 @@ func (r *Repository) GetWeeklyReport(userID int64, weekStart time.Time) (*models
 	if weaknesses.Valid {
 		wr.Weaknesses = json.RawMessage(weaknesses.String)
 	}
+	decodeJSONColumn(metricAverages, &wr.MetricAverages)
+	decodeJSONColumn(recurring, &wr.RecurringWeaknesses)
 
 	return &wr, nil
 }
@@ -968,12 +1671,14 @@ func (r *Repository) SaveWeeklyReport(wr *models.WeeklyReport) error {
 		`INSERT OR REPLACE INTO weekly_reports
 		 (user_id, week_start, week_end, total_games, wins, losses, win_rate,
 		  avg_apm, avg_sq, avg_supply_block, main_race, total_play_time,
-		  improvements, regressions, focus_suggestion, strengths, weaknesses)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		  improvements, regressions, focus_suggestion, strengths, weaknesses, metric_averages,
+		  recurring_weaknesses)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		wr.UserID, wr.WeekStart.Format("2006-01-02"), wr.WeekEnd.Format("2006-01-02"),
 		wr.TotalGames, wr.Wins, wr.Losses, wr.WinRate,
 		wr.AvgAPM, wr.AvgSQ, wr.AvgSupplyBlock, wr.MainRace, wr.TotalPlayTime,
 		improvements, regressions, wr.FocusSuggestion, strengths, weaknesses,
+		encodeJSONColumn(wr.MetricAverages), encodeJSONColumn(wr.RecurringWeaknesses),
 	)
 	if err != nil {
 		return err
@@ -995,9 +1700,12 @@ func (r *Repository) GenerateWeeklyReport(userID int64, weekStart time.Time) (*m
 	var totalGames, wins, losses, totalPlayTime int
 	var sumAPM, sumSQ, sumSupplyBlock float64
 	var gamesWithMetrics int
+	metricSums := make(map[string]float64)
+	metricCounts := make(map[string]int)
 
 	rows, err := r.db.Query(
-		`SELECT games_played, wins, losses, avg_apm, avg_spending_quotient, avg_supply_block_pct, total_play_time
+		`SELECT games_played, wins, losses, avg_apm, avg_spending_quotient, avg_supply_block_pct, total_play_time,
+		        metric_averages, metric_games
 		 FROM daily_progress
 		 WHERE user_id = ? AND date >= ? AND date <= ?`,
 		userID, weekStart.Format("2006-01-02"), weekEnd.Format("2006-01-02"),
@@ -1009,11 +1717,20 @@ func (r *Repository) GenerateWeeklyReport(userID int64, weekStart time.Time) (*m
 
 	for rows.Next() {
 		var dp models.DailyProgress
+		var metricAverages, metricGames sql.NullString
 		err := rows.Scan(&dp.GamesPlayed, &dp.Wins, &dp.Losses, &dp.AvgAPM,
-			&dp.AvgSpendingQuotient, &dp.AvgSupplyBlockPct, &dp.TotalPlayTime)
+			&dp.AvgSpendingQuotient, &dp.AvgSupplyBlockPct, &dp.TotalPlayTime,
+			&metricAverages, &metricGames)
 		if err != nil {
 			return nil, err
 		}
+		decodeJSONColumn(metricAverages, &dp.MetricAverages)
+		decodeJSONColumn(metricGames, &dp.MetricGames)
+		// Werte nach der Anzahl Spiele gewichten, in denen die Metrik vorkam
+		for key, value := range dp.MetricAverages {
+			metricSums[key] += value * float64(dp.MetricGames[key])
+			metricCounts[key] += dp.MetricGames[key]
+		}
 		totalGames += dp.GamesPlayed
 		wins += dp.Wins
 		losses += dp.Losses
@@ -1035,6 +1752,12 @@ func (r *Repository) GenerateWeeklyReport(userID int64, weekStart time.Time) (*m
 		avgSQ = sumSQ / float64(gamesWithMetrics)
 		avgSupplyBlock = sumSupplyBlock / float64(gamesWithMetrics)
 	}
+	metricAverages := models.MetricValues{}
+	for key, sum := range metricSums {
+		if metricCounts[key] > 0 {
+			metricAverages[key] = sum / float64(metricCounts[key])
+		}
+	}
 
 	// Ermittle die Hauptrasse
 	mainRace := r.getMainRaceForPeriod(userID, weekStart, weekEnd)
@@ -1078,6 +1801,22 @@ func (r *Repository) GenerateWeeklyReport(userID int64, weekStart time.Time) (*m
 			regressions["supply_block"] = fmt.Sprintf("+%.1f%%", change)
 		}
 
+		// Zusatz-Metriken (z.B. "sq_mid"), Supply Blocks: weniger ist besser
+		for key, value := range metricAverages {
+			prev, ok := prevReport.MetricAverages[key]
+			if !ok || prev <= 0 {
+				continue
+			}
+			change := (value - prev) / prev * 100
+			lowerIsBetter := strings.HasPrefix(key, "supply_block")
+			switch {
+			case change > 5 && !lowerIsBetter, change < -5 && lowerIsBetter:
+				improvements[key] = fmt.Sprintf("%+.1f%%", change)
+			case change < -5 && !lowerIsBetter, change > 5 && lowerIsBetter:
+				regressions[key] = fmt.Sprintf("%+.1f%%", change)
+			}
+		}
+
 		// Win Rate Vergleich
 		if winRate > prevReport.WinRate+5 {
 			improvements["win_rate"] = fmt.Sprintf("+%.1f%%", winRate-prevReport.WinRate)
@@ -1086,34 +1825,57 @@ func (r *Repository) GenerateWeeklyReport(userID int64, weekStart time.Time) (*m
 		}
 	}
 
-	// Bestimme Stärken und Schwächen
+	// Bestimme Stärken und Schwächen (als Katalog-Schlüssel, übersetzt wird bei der Ausgabe)
 	if avgAPM > 100 {
-		strengthsList = append(strengthsList, "Gute APM")
+		strengthsList = append(strengthsList, "weekly.strength.good_apm")
 	} else if avgAPM < 60 {
-		weaknessesList = append(weaknessesList, "APM verbessern")
+		weaknessesList = append(weaknessesList, "weekly.weakness.improve_apm")
 	}
 
 	if avgSQ > 70 {
-		strengthsList = append(strengthsList, "Gutes Ressourcen-Management")
+		strengthsList = append(strengthsList, "weekly.strength.good_resource_management")
 	} else if avgSQ < 50 {
-		weaknessesList = append(weaknessesList, "Ressourcen schneller ausgeben")
+		weaknessesList = append(weaknessesList, "weekly.weakness.spend_faster")
 	}
 
 	if avgSupplyBlock < 10 {
-		strengthsList = append(strengthsList, "Wenige Supply Blocks")
+		strengthsList = append(strengthsList, "weekly.strength.few_supply_blocks")
 	} else if avgSupplyBlock > 20 {
-		weaknessesList = append(weaknessesList, "Supply Blocks reduzieren")
+		weaknessesList = append(weaknessesList, "weekly.weakness.reduce_supply_blocks")
+	}
+
+	// Schwache Phasen, die im Wochenschnitt untergehen
+	if avgSQ >= 50 {
+		if sqLate, ok := metricAverages["sq_late"]; ok && sqLate < 50 {
+			weaknessesList = append(weaknessesList, "weekly.weakness.spending_late")
+		} else if sqMid, ok := metricAverages["sq_mid"]; ok && sqMid < 50 {
+			weaknessesList = append(weaknessesList, "weekly.weakness.spending_mid")
+		}
+	}
+	if avgSupplyBlock <= 20 {
+		if blockEarly, ok := metricAverages["supply_block_early"]; ok && blockEarly > 20 {
+			weaknessesList = append(weaknessesList, "weekly.weakness.supply_blocks_early")
+		}
+	}
+
+	// Wiederkehrende Probleme aus den strategischen Analysen der Woche
+	recurring, err := r.getRecurringProblemsForPeriod(userID, weekStart, weekEnd, 2)
+	if err != nil {
+		return nil, err
+	}
+	for _, rp := range recurring {
+		weaknessesList = append(weaknessesList, "weekly.weakness.recurring."+rp.Key)
 	}
 
 	// Fokus-Empfehlung basierend auf größter Schwäche
 	if avgSupplyBlock > 15 {
-		focusSuggestion = "Fokussiere dich diese Woche auf das Vermeiden von Supply Blocks. Baue frühzeitig Supply-Gebäude."
+		focusSuggestion = "weekly.focus.supply_blocks"
 	} else if avgSQ < 60 {
-		focusSuggestion = "Fokussiere dich auf dein Macro: Halte deine Ressourcen niedrig und produziere kontinuierlich."
+		focusSuggestion = "weekly.focus.macro"
 	} else if avgAPM < 80 {
-		focusSuggestion = "Arbeite an deiner Spielgeschwindigkeit. Nutze Hotkeys und übe deine Makro-Zyklen."
+		focusSuggestion = "weekly.focus.speed"
 	} else {
-		focusSuggestion = "Du machst gute Fortschritte! Konzentriere dich auf Timing-Angriffe und strategische Entscheidungen."
+		focusSuggestion = "weekly.focus.strategy"
 	}
 
 	wr := &models.WeeklyReport{
@@ -1127,9 +1889,11 @@ func (r *Repository) GenerateWeeklyReport(userID int64, weekStart time.Time) (*m
 		AvgAPM:          avgAPM,
 		AvgSQ:           avgSQ,
 		AvgSupplyBlock:  avgSupplyBlock,
+		MetricAverages:   metricAverages,
 		MainRace:        mainRace,
 		TotalPlayTime:   totalPlayTime,
 		FocusSuggestion: focusSuggestion,
+		RecurringWeaknesses: recurring,
 		GeneratedAt:     time.Now(),
 	}
 
@@ -1353,8 +2117,46 @@ func (r *Repository) GetRecentGames(userID int64, limit int) ([]models.RecentGam
 	return games, rows.Err()
 }
 
+// GetPerformanceGames gibt die verknüpften Spiele eines Benutzers seit since zurück (Nullwert = alle)
+// mit Gegnerrasse und Supply-Block-Anteil aus der Analyse
+func (r *Repository) GetPerformanceGames(userID int64, since time.Time) ([]models.PerformanceGame, error) {
+	rows, err := r.db.Query(
+		`SELECT r.id, r.map, gp.race, COALESCE(op.race, ''), gp.result, gp.apm, gp.spending_quotient,
+		        json_extract(a.data, '$.supply_analysis.block_percentage'), r.played_at
+		 FROM replays r
+		 JOIN user_replays ur ON ur.replay_id = r.id
+		 JOIN game_players gp ON gp.replay_id = r.id AND gp.player_id = ur.player_id
+		 `+opponentJoin+`
+		 LEFT JOIN analyses a ON a.replay_id = r.id AND a.player_id = ur.player_id
+		 WHERE ur.user_id = ? AND ur.player_id IS NOT NULL AND r.played_at >= ?
+		 ORDER BY r.played_at DESC`,
+		userID, since.Format("2006-01-02"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []models.PerformanceGame
+	for rows.Next() {
+		var g models.PerformanceGame
+		var supplyBlock sql.NullFloat64
+		err := rows.Scan(&g.ReplayID, &g.Map, &g.Race, &g.OpponentRace, &g.Result, &g.APM, &g.SQ,
+			&supplyBlock, &g.PlayedAt)
+		if err != nil {
+			return nil, err
+		}
+		if supplyBlock.Valid {
+			g.SupplyBlock = &supplyBlock.Float64
+		}
+		games = append(games, g)
+	}
+	return games, rows.Err()
+}
+
 // UpdateProgressFromReplay aktualisiert den Fortschritt basierend auf einem neuen Replay
-func (r *Repository) UpdateProgressFromReplay(userID int64, replay *models.Replay, playerMetrics *models.GamePlayer, supplyBlockPct float64) error {
+// metrics enthält die Zusatz-Metriken des Spiels; fehlende Werte (z.B. nicht erreichte Phasen) zählen nicht in den Schnitt
+func (r *Repository) UpdateProgressFromReplay(userID int64, replay *models.Replay, playerMetrics *models.GamePlayer, supplyBlockPct float64, metrics models.MetricValues) error {
 	date := replay.PlayedAt
 	dp, err := r.GetOrCreateDailyProgress(userID, date)
 	if err != nil {
@@ -1383,6 +2185,18 @@ func (r *Repository) UpdateProgressFromReplay(userID int64, replay *models.Repla
 		dp.AvgSupplyBlockPct = supplyBlockPct
 	}
 
+	if dp.MetricAverages == nil {
+		dp.MetricAverages = models.MetricValues{}
+	}
+	if dp.MetricGames == nil {
+		dp.MetricGames = make(map[string]int)
+	}
+	for key, value := range metrics {
+		n := float64(dp.MetricGames[key])
+		dp.MetricAverages[key] = (dp.MetricAverages[key]*n + value) / (n + 1)
+		dp.MetricGames[key]++
+	}
+
 	dp.TotalPlayTime += replay.Duration
 
 	if err := r.UpdateDailyProgress(dp); err != nil {
@@ -1393,6 +2207,77 @@ func (r *Repository) UpdateProgressFromReplay(userID int64, replay *models.Repla
 	return r.updateGoalsFromProgress(userID, dp)
 }
 
+// RebuildDailyProgress berechnet den Fortschritt eines Tages aus den verknüpften Replays neu
+// (z.B. nach einer Neuanalyse) und verwirft die Wochenberichte ab dieser Woche,
+// damit sie beim nächsten Abruf aus den neuen Werten erzeugt werden
+func (r *Repository) RebuildDailyProgress(userID int64, date time.Time) error {
+	dateStr := date.Format("2006-01-02")
+
+	rows, err := r.db.Query(
+		`SELECT ur.replay_id, ur.player_id, r.played_at
+		 FROM user_replays ur
+		 JOIN replays r ON r.id = ur.replay_id
+		 WHERE ur.user_id = ? AND ur.player_id IS NOT NULL`,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	claims := make(map[int64]int64) // Replay-ID -> Spieler-ID
+	for rows.Next() {
+		var replayID, playerID int64
+		var playedAt time.Time
+		if err := rows.Scan(&replayID, &playerID, &playedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		if playedAt.Format("2006-01-02") == dateStr {
+			claims[replayID] = playerID
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM daily_progress WHERE user_id = ? AND date = ?`, userID, dateStr); err != nil {
+		return err
+	}
+	for replayID, playerID := range claims {
+		replay, err := r.GetReplayByID(replayID)
+		if err != nil || replay == nil {
+			continue
+		}
+		var player *models.GamePlayer
+		for i := range replay.GamePlayers {
+			if replay.GamePlayers[i].PlayerID == playerID {
+				player = &replay.GamePlayers[i]
+			}
+		}
+		if player == nil {
+			continue
+		}
+
+		var supplyBlockPct float64
+		var metrics models.MetricValues
+		if analysis, err := r.GetAnalysis(replayID, playerID); err == nil && analysis != nil {
+			var data models.AnalysisData
+			if err := json.Unmarshal(analysis.Data, &data); err == nil {
+				if data.SupplyAnalysis != nil {
+					supplyBlockPct = data.SupplyAnalysis.BlockPercentage
+				}
+				metrics = data.GoalMetrics()
+			}
+		}
+		if err := r.UpdateProgressFromReplay(userID, replay, player, supplyBlockPct, metrics); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.db.Exec(`DELETE FROM weekly_reports WHERE user_id = ? AND week_end >= ?`, userID, dateStr)
+	return err
+}
+
 // updateGoalsFromProgress aktualisiert Ziele basierend auf Fortschritt
 func (r *Repository) updateGoalsFromProgress(userID int64, dp *models.DailyProgress) error {
 	goals, err := r.GetActiveGoals(userID)
@@ -1422,6 +2307,13 @@ func (r *Repository) updateGoalsFromProgress(userID int64, dp *models.DailyProgr
 			currentValue = dp.AvgSpendingQuotient
 		case "win_rate":
 			currentValue = dp.WinRate()
+		default:
+			// Zusatz-Metriken wie "sq_mid" oder "workers_at_6m"
+			value, ok := dp.MetricAverages[goal.MetricName]
+			if !ok {
+				continue
+			}
+			currentValue = value
 		}
 
 		if err := r.UpdateGoalProgress(goal.ID, currentValue); err != nil {
@@ -8,6 +8,17 @@ const api = axios.create({
   },
 })
 
+// Sprache für generierte Texte und Fehlermeldungen (ohne Benutzer-Einstellung)
+api.defaults.headers.common['Accept-Language'] = navigator.languages?.join(',') || navigator.language
+
+export function setLanguage(language: string | null) {
+  if (language) {
+    api.defaults.headers.common['Accept-Language'] = language
+  } else {
+    api.defaults.headers.common['Accept-Language'] = navigator.languages?.join(',') || navigator.language
+  }
+}
+
 // Auth Token Management
 export function setAuthToken(token: string | null) {
   if (token) {
@@ -28,6 +39,8 @@ export interface Player {
   apm: number
   spending_quotient: number
   is_human: boolean
+  toon_handle?: string
+  random_race?: boolean
 }
 
 export interface Replay {
@@ -47,6 +60,7 @@ export interface SupplyPoint {
   supply_used: number
   supply_max: number
   is_blocked: boolean
+  is_maxed?: boolean
 }
 
 export interface SupplyBlock {
@@ -56,13 +70,34 @@ export interface SupplyBlock {
   severity: string
   supply_used: number
   supply_max: number
+  cause: 'no_supply_started' | 'supply_started_late' | 'supply_provider_killed'
+  provider_in_progress: boolean
+  production_waiting: boolean
+  estimated_supply_lost: number
+  resources_floated: number
+}
+
+export type GamePhaseName = 'early' | 'mid' | 'late'
+
+// Phasen-Metriken, z.B. "sq_mid" oder "supply_block_early"
+export type MetricValues = Record<string, number>
+
+export interface SupplyPhase {
+  phase: GamePhaseName
+  start: number
+  end: number
+  block_time: number
+  block_percentage: number
+  blocks: number
 }
 
 export interface SupplyAnalysis {
   total_block_time: number
   block_percentage: number
+  maxed_out_time: number
   blocks: SupplyBlock[]
   supply_timeline: SupplyPoint[]
+  phases?: SupplyPhase[]
 }
 
 export interface ResourcePoint {
@@ -87,18 +122,77 @@ export interface SpendingAnalysis {
     gas: number
   }
   resource_timeline: ResourcePoint[]
+  sq_timeline: SQPoint[]
+  float_periods: FloatPeriod[]
+  float_time: number
+  phases?: SpendingPhase[]
+}
+
+export interface SQPoint {
+  time: number
+  sq: number
+}
+
+export interface FloatPeriod {
+  start: number
+  end: number
+  duration: number
+  peak_minerals: number
+  peak_gas: number
+}
+
+export interface SpendingPhase {
+  phase: GamePhaseName
+  start: number
+  end: number
+  spending_quotient: number
+  rating: string
+  average_unspent: {
+    minerals: number
+    gas: number
+  }
+  average_income: number
+}
+
+export interface APMBreakdown {
+  commands: number
+  selections: number
+  control_group_recalls: number
+  control_group_sets: number
+  camera_moves: number
 }
 
 export interface APMPoint {
   time: number
   apm: number
+  breakdown: APMBreakdown
+}
+
+export interface PACAnalysis {
+  fixations: number
+  pac_per_minute: number
+  actions_per_pac: number
+  average_fixation_duration: number
+  average_reaction_latency: number
 }
 
 export interface APMAnalysis {
   average_apm: number
   peak_apm: number
   eapm: number
+  resolution: number
+  breakdown: APMBreakdown
   apm_timeline: APMPoint[]
+  pac?: PACAnalysis
+  phases?: APMPhase[]
+}
+
+export interface APMPhase {
+  phase: GamePhaseName
+  start: number
+  end: number
+  apm: number
+  eapm: number
 }
 
 export interface BuildOrderItem {
@@ -106,6 +200,12 @@ export interface BuildOrderItem {
   supply: number
   action: string
   unit_or_building: string
+  status: 'completed' | 'in_progress' | 'cancelled' | 'destroyed'
+  completed_time?: number
+  lost_time?: number
+  killed_by?: number
+  pattern?: string
+  is_proxy?: boolean
 }
 
 export interface ArmyPoint {
@@ -122,6 +222,196 @@ export interface ArmyAnalysis {
     count: number
     value: number
   }[]
+  phases?: ArmyPhase[]
+}
+
+export interface ArmyPhase {
+  phase: GamePhaseName
+  start: number
+  end: number
+  peak_army_value: number
+  average_army_value: number
+  units_lost: number
+}
+
+export interface ControlGroupUsage {
+  index: number
+  sets: number
+  recalls: number
+  recalls_per_minute: number
+  role: 'town_hall' | 'production' | 'tech' | 'workers' | 'army' | 'mixed' | 'empty'
+  unit_types: string[]
+}
+
+export interface HotkeyAnalysis {
+  control_groups: ControlGroupUsage[]
+  total_sets: number
+  total_recalls: number
+  recalls_per_minute: number
+  camera_saves: number
+  camera_hotkey_recalls: number
+  camera_hotkeys_per_minute: number
+  production_commands: number
+  production_via_hotkey: number
+  production_hotkey_share: number
+}
+
+export interface AttentionGap {
+  start: number
+  end: number
+  duration: number
+  away_share: number
+  location: 'army' | 'elsewhere'
+}
+
+export interface HeatmapCell {
+  x: number
+  y: number
+  seconds: number
+}
+
+export interface AttentionAnalysis {
+  time_at_main: number
+  time_at_natural: number
+  time_at_army: number
+  time_elsewhere: number
+  main_share: number
+  natural_share: number
+  army_share: number
+  elsewhere_share: number
+  away_gaps: AttentionGap[]
+  away_gap_time: number
+  heatmap_cell_size: number
+  heatmap: HeatmapCell[]
+}
+
+export interface LarvaSample {
+  time: number
+  count: number
+}
+
+export interface HatcheryLarva {
+  hatchery_id: number
+  x: number
+  y: number
+  done_time: number
+  larva_spawned: number
+  inject_pops: number
+  banked_time: number
+  average_larva: number
+  timeline: LarvaSample[]
+}
+
+export interface QueenUsage {
+  id: number
+  born_time: number
+  died_time?: number
+  injects: number
+  creep_tumors: number
+  role: 'inject' | 'creep' | 'defense'
+}
+
+export interface LarvaAnalysis {
+  larva_spawned: number
+  larva_used: number
+  conversion_rate: number
+  unused_larva_time: number
+  banked_time: number
+  banked_share: number
+  inject_pops: number
+  hatcheries: HatcheryLarva[]
+  queens_built: number
+  peak_queens: number
+  inject_queens: number
+  creep_queens: number
+  defense_queens: number
+  queens: QueenUsage[]
+}
+
+export interface CreepPoint {
+  time: number
+  active_tumors: number
+  total_tumors: number
+  coverage: number
+}
+
+export interface CreepPhase {
+  phase: GamePhaseName
+  start: number
+  end: number
+  tumors_placed: number
+  average_coverage: number
+  end_coverage: number
+}
+
+export interface CreepAnalysis {
+  total_tumors: number
+  queen_tumors: number
+  spread_tumors: number
+  max_chain_depth: number
+  first_tumor_time: number
+  average_spread_gap: number
+  longest_spread_gap: number
+  spread_gaps: { start: number; end: number; duration: number }[]
+  final_coverage: number
+  peak_coverage: number
+  timeline: CreepPoint[]
+  phases: CreepPhase[]
+}
+
+export interface ScoutEvent {
+  time: number
+  method: 'worker' | 'unit' | 'overlord' | 'observer' | 'scan' | 'hallucination'
+  unit_type?: string
+  target: 'main' | 'natural' | 'map'
+  x: number
+  y: number
+  info_seen: string[]
+}
+
+export interface ScoutingWindow {
+  key: string
+  name: string
+  start: number
+  end: number
+  description: string
+  scouted: boolean
+  first_scout_time?: number
+}
+
+export interface ScoutingAnalysis {
+  opponent_race: string
+  first_scout_time: number
+  first_scout_method: string
+  events: ScoutEvent[]
+  scans: number
+  hallucinations: number
+  overlord_placements: number
+  observer_placements: number
+  windows: ScoutingWindow[]
+  missed_windows: number
+}
+
+export interface HarassmentIncident {
+  start: number
+  end: number
+  base: 'main' | 'natural' | 'base'
+  x: number
+  y: number
+  attacker_types: string[]
+  workers_lost: number
+  reacted: boolean
+  reaction_time: number
+  mining_time_lost: number
+  estimated_minerals_lost: number
+}
+
+export interface HarassmentAnalysis {
+  incidents: HarassmentIncident[]
+  total_workers_lost: number
+  total_mining_time_lost: number
+  average_reaction_time: number
+  unanswered_incidents: number
 }
 
 export interface Suggestion {
@@ -131,6 +421,7 @@ export interface Suggestion {
   description: string
   timestamp?: number
   target_value?: string
+  rule_id?: string
 }
 
 export interface AnalysisData {
@@ -143,10 +434,334 @@ export interface AnalysisData {
     total_injects: number
     missed_injects: number
   }
+  larva_analysis?: LarvaAnalysis
+  creep_analysis?: CreepAnalysis
   army_analysis?: ArmyAnalysis
+  hotkey_analysis?: HotkeyAnalysis
+  attention_analysis?: AttentionAnalysis
+  scouting_analysis?: ScoutingAnalysis
+  harassment_analysis?: HarassmentAnalysis
+  benchmark_analysis?: BenchmarkAnalysis
+  win_probability?: WinProbability
   suggestions: Suggestion[]
 }
 
+export interface WinProbabilityPoint {
+  time: number
+  probability: number
+  army_lead: number
+  lost_diff: number
+  worker_diff: number
+  base_diff: number
+  supply_diff: number
+}
+
+export interface TurningPoint {
+  start: number
+  end: number
+  before: number
+  after: number
+  change: number
+  factor: 'army_lead' | 'lost_diff' | 'worker_diff' | 'base_diff' | 'supply_diff'
+  description: string
+}
+
+export interface WinProbability {
+  timeline: WinProbabilityPoint[]
+  turning_points: TurningPoint[]
+  decided_at: number
+  final_probability: number
+  model_fitted: boolean
+}
+
+export interface UnitTypeCount {
+  unit_type: string
+  count: number
+  in_progress: number
+}
+
+export interface UpgradeProgress {
+  name: string
+  structure?: string
+  started: number
+  completes: number
+  progress: number
+}
+
+export interface PlayerState {
+  player_slot: number
+  name: string
+  race: string
+  supply: number
+  supply_cap: number
+  workers: number
+  minerals: number
+  gas: number
+  mineral_income: number
+  gas_income: number
+  army_value: number
+  units: UnitTypeCount[]
+  structures: UnitTypeCount[]
+  upgrades_done: string[]
+  upgrades_in_progress: UpgradeProgress[]
+}
+
+export interface GameState {
+  time: number
+  label: string
+  loop: number
+  players: PlayerState[]
+}
+
+export interface StoryPlayer {
+  player_slot: number
+  name: string
+  race: string
+  result: string
+}
+
+export interface StoryEntry {
+  time: number
+  label: string
+  kind: 'build' | 'unit' | 'expansion' | 'tech' | 'upgrade' | 'supply_block' | 'engagement' | 'harassment' | 'chat' | 'leave' | 'game_end'
+  actor: string
+  actor_slot?: number
+  importance: 1 | 2 | 3
+  title: string
+  detail?: string
+}
+
+export interface GameStory {
+  map: string
+  duration: number
+  players: StoryPlayer[]
+  entries: StoryEntry[]
+}
+
+export interface ComparisonSide {
+  replay_id: number
+  player_id: number
+  name: string
+  race: string
+  result: string
+  matchup?: string
+  map: string
+  duration: number
+  played_at: string
+}
+
+export interface BuildStepComparison {
+  action: string
+  unit_or_building: string
+  status: 'matched' | 'only_a' | 'only_b'
+  time_a?: number
+  time_b?: number
+  supply_a?: number
+  supply_b?: number
+  delta?: number
+}
+
+export interface BuildOrderDiffSummary {
+  matched: number
+  only_a: number
+  only_b: number
+  average_delta: number
+  first_divergence?: number
+}
+
+export interface MetricDelta {
+  metric: string
+  a: number
+  b: number
+  delta: number
+}
+
+export interface BenchmarkDelta {
+  time: number
+  label: string
+  reached_a: boolean
+  reached_b: boolean
+  metrics?: MetricDelta[]
+}
+
+export interface ComparisonTimeline {
+  metric: 'supply' | 'army_value' | 'minerals' | 'gas' | 'income'
+  points: { time: number; a?: number; b?: number }[]
+}
+
+export interface ReplayComparison {
+  a: ComparisonSide
+  b: ComparisonSide
+  build_order: BuildStepComparison[]
+  build_summary: BuildOrderDiffSummary
+  benchmarks: BenchmarkDelta[]
+  timelines: ComparisonTimeline[]
+}
+
+export interface ReferenceReplay {
+  id: number
+  replay_id: number
+  player_id: number
+  player_name: string
+  player: string
+  matchup: string
+  build?: string
+  map: string
+  game_version: string
+  created_by: number
+  created_at: string
+}
+
+export interface ReferenceGap {
+  metric: 'income' | 'supply' | 'army_value'
+  start: number
+  end: number
+  peak_time: number
+  value: number
+  reference_value: number
+  deficit: number
+  percent: number
+}
+
+export interface ReferenceComparison {
+  reference: ReferenceReplay
+  build?: string
+  build_match: boolean
+  similarity: number
+  gaps: ReferenceGap[]
+  comparison: ReplayComparison
+}
+
+export interface SimilarityScores {
+  build: number
+  opponent_build: number
+  matchup: number
+  map: number
+  length: number
+}
+
+export interface SimilarGame {
+  replay_id: number
+  player_id: number
+  name: string
+  race: string
+  result: string
+  opponent?: string
+  opponent_race?: string
+  matchup: string
+  map: string
+  duration: number
+  played_at: string
+  is_reference: boolean
+  reference?: string
+  score: number
+  edit_distance: number
+  components: SimilarityScores
+}
+
+export interface GameSignature {
+  replay_id: number
+  player_id: number
+  name: string
+  race: string
+  result: string
+  opponent_id?: number
+  opponent?: string
+  opponent_race?: string
+  matchup: string
+  map: string
+  duration: number
+  played_at: string
+  build: string[]
+  opponent_build: string[]
+  reference?: string
+}
+
+export interface OpponentShare {
+  name: string
+  games: number
+  wins: number
+  percent: number
+}
+
+export interface OpponentOpening {
+  build: string
+  name: string
+  matchup: string
+  games: number
+  percent: number
+}
+
+export interface OpponentGame {
+  replay_id: number
+  map: string
+  duration: number
+  game_version: string
+  played_at: string
+  player_id: number
+  name: string
+  race: string
+  result: string
+  opponent_id?: number
+  opponent?: string
+  opponent_race?: string
+  head_to_head: boolean
+  opening?: string
+  opening_name?: string
+}
+
+export interface OpponentDossier {
+  player: { id: number; toon_handle: string; name: string; region: string }
+  names: string[]
+  games: number
+  head_to_head: {
+    games: number
+    wins: number
+    losses: number
+    win_rate: number
+    recent: OpponentGame[]
+  }
+  races: OpponentShare[]
+  openings: OpponentOpening[]
+  analyzed_games: number
+  average_duration: number
+  maps: OpponentShare[]
+  first_seen: string
+  last_seen: string
+}
+
+export interface BenchmarkValues {
+  supply: number
+  workers: number
+  bases: number
+  army_value: number
+  upgrades_started: number
+  income: number
+}
+
+export interface BenchmarkComparison {
+  metric: 'supply' | 'workers' | 'bases' | 'army_value' | 'upgrades' | 'income'
+  actual: number
+  target: number
+  percent: number
+  met: boolean
+}
+
+export interface BenchmarkSnapshot {
+  time: number
+  label: string
+  reached: boolean
+  values: BenchmarkValues
+  target?: BenchmarkValues
+  comparisons?: BenchmarkComparison[]
+}
+
+export interface BenchmarkAnalysis {
+  league: string
+  matchup: string
+  snapshots: BenchmarkSnapshot[]
+}
+
 export interface ReplayAnalysis {
   replay: Replay
   analyses: Record<number, AnalysisData>
@@ -170,6 +785,7 @@ export interface SupplyBlockSummary {
   time: number
   duration: number
   severity: string
+  cause: string
 }
 
 export interface CriticalMoment {
@@ -181,36 +797,92 @@ export interface CriticalMoment {
 }
 
 export interface IdentifiedProblem {
+  key: string
   title: string
   description: string
   priority: string
 }
 
+export interface DetectedStrategy {
+  id: string
+  name: string
+  time: number
+  tips: string[]
+}
+
 export interface MatchupTips {
+  matchup?: string
+  patch?: string
   opening: string[]
   mid_game: string[]
   timing: string[]
   late_game: string[]
+  vs_random?: string[]
+  opponent_strategy?: DetectedStrategy
+  reference?: ReferenceTips
+}
+
+export interface ReferenceTips {
+  reference_id: number
+  replay_id: number
+  player: string
+  build?: string
+  tips: string[]
 }
 
 export interface ImprovementStep {
+  key: string
   category: string
   title: string
   description: string
 }
 
+export interface ProxyLoss {
+  player: string
+  building: string
+  start_time: number
+  lost_time: number
+  is_loser_proxy: boolean
+  is_player_proxy: boolean
+}
+
+export interface ResultInference {
+  result: string
+  reason: string // player_left, opponent_left, resources, unknown
+  confidence: number
+  description: string
+}
+
+export interface StrategicInsight {
+  key: string
+  time?: number
+  title: string
+  description: string
+}
+
 export interface StrategicAnalysis {
   winner: string
   loser: string
   winner_race: string
   loser_race: string
+  player: string
+  opponent: string
+  player_race: string
+  opponent_race: string
+  result: string // Win, Loss, Undecided aus Sicht von player
+  result_inference?: ResultInference
   matchup: string
   metrics_comparison: MetricComparison[]
   supply_blocks: SupplyBlockSummary[]
   critical_moments: CriticalMoment[]
   problems: IdentifiedProblem[]
+  unpunished_mistakes?: IdentifiedProblem[]
+  strengths: StrategicInsight[]
+  close_calls: StrategicInsight[]
+  proxy_losses?: ProxyLoss[]
   matchup_tips: MatchupTips
   improvement_steps: ImprovementStep[]
+  win_probability?: WinProbability
   summary: string
 }
 
@@ -272,21 +944,100 @@ export async function getTrends(playerId: number, limit = 20): Promise<{ trends:
   return response.data
 }
 
-export async function getStrategicAnalysis(id: number): Promise<StrategicAnalysisResponse> {
-  const response = await api.get(`/replays/${id}/strategic`)
+// playerId: Perspektive, Standard ist der mit dem Replay verknüpfte Spieler
+export async function getStrategicAnalysis(id: number, playerId?: number): Promise<StrategicAnalysisResponse> {
+  const response = await api.get(`/replays/${id}/strategic`, {
+    params: playerId ? { player_id: playerId } : undefined
+  })
   return response.data
 }
 
+// time als mm:ss oder Sekunden
+export async function getReplayState(id: number, time: string): Promise<GameState> {
+  const response = await api.get(`/replays/${id}/state`, { params: { t: time } })
+  return response.data
+}
+
+// minImportance: 1 = alle Ereignisse, 3 = nur Schlüsselmomente
+export async function getReplayTimeline(id: number, minImportance?: number): Promise<GameStory> {
+  const response = await api.get(`/replays/${id}/timeline`, {
+    params: minImportance ? { min_importance: minImportance } : undefined
+  })
+  return response.data
+}
+
+// Vergleicht Spiel b mit Spiel a; ohne Spieler-IDs gilt der jeweils verknüpfte Spieler
+export async function compareReplays(a: number, b: number, playerA?: number, playerB?: number): Promise<ReplayComparison> {
+  const response = await api.get('/compare', {
+    params: { a, b, player_a: playerA, player_b: playerB }
+  })
+  return response.data
+}
+
+export async function getReplayReference(id: number, playerId?: number): Promise<ReferenceComparison> {
+  const response = await api.get(`/replays/${id}/reference`, {
+    params: playerId ? { player_id: playerId } : undefined
+  })
+  return response.data
+}
+
+export async function getSimilarReplays(
+  id: number,
+  options: { playerId?: number; result?: 'Win' | 'Loss'; limit?: number } = {}
+): Promise<{ game: GameSignature; similar: SimilarGame[] }> {
+  const response = await api.get(`/replays/${id}/similar`, {
+    params: { player_id: options.playerId, result: options.result, limit: options.limit }
+  })
+  return response.data
+}
+
+export async function getOpponentDossier(toonHandle: string): Promise<OpponentDossier> {
+  const response = await api.get(`/opponents/${encodeURIComponent(toonHandle)}`)
+  return response.data
+}
+
+export async function listReferences(matchup?: string): Promise<{ references: ReferenceReplay[] }> {
+  const response = await api.get('/references', { params: matchup ? { matchup } : undefined })
+  return response.data
+}
+
+// Nur Admins und Coaches; ohne build wird der Build erkannt
+export async function createReference(
+  replayId: number,
+  playerId: number,
+  tags: { player?: string; build?: string } = {}
+): Promise<ReferenceReplay> {
+  const response = await api.post('/references', { replay_id: replayId, player_id: playerId, ...tags })
+  return response.data
+}
+
+export async function deleteReference(id: number): Promise<void> {
+  await api.delete(`/references/${id}`)
+}
+
 // ============== Auth Types ==============
 
 export interface User {
   id: number
   email: string
   sc2_player_name: string
+  language?: string
+  role?: 'admin' | 'coach'
   created_at: string
   last_login?: string
 }
 
+export interface Language {
+  code: string
+  name: string
+}
+
+export interface LanguagesResponse {
+  languages: Language[]
+  default: string
+  current: string
+}
+
 export interface AuthResponse {
   token: string
   user: User
@@ -328,6 +1079,7 @@ export interface DailyProgress {
   avg_spending_quotient: number
   avg_supply_block_pct: number
   total_play_time: number
+  metric_averages?: MetricValues
 }
 
 export interface WeekStats {
@@ -378,6 +1130,7 @@ export interface WeeklyReport {
   avg_apm: number
   avg_sq: number
   avg_supply_block: number
+  metric_averages?: MetricValues
   main_race: string
   total_play_time: number
   improvements?: Record<string, string>
@@ -385,9 +1138,62 @@ export interface WeeklyReport {
   focus_suggestion: string
   strengths?: string[]
   weaknesses?: string[]
+  recurring_weaknesses?: RecurringProblem[]
   generated_at: string
 }
 
+export interface RecurringProblem {
+  key: string
+  name: string
+  count: number
+  games: number
+  frequency: number
+  last_seen: string
+  replay_ids: number[]
+}
+
+export interface MetricEstimate {
+  average: number
+  low: number
+  high: number
+  games: number
+}
+
+export interface PerformanceCell {
+  matchup?: string
+  map?: string
+  games: number
+  wins: number
+  losses: number
+  win_rate: number
+  win_rate_low: number
+  win_rate_high: number
+  apm: MetricEstimate
+  sq: MetricEstimate
+  supply_block: MetricEstimate
+}
+
+export interface PerformanceWeakSpot {
+  matchup: string
+  map?: string
+  games: number
+  wins: number
+  win_rate: number
+  baseline: number
+  p_value: number
+  adjusted_p_value: number
+  message: string
+}
+
+export interface PerformanceMatrix {
+  overall: PerformanceCell
+  matchups: PerformanceCell[]
+  cells: PerformanceCell[]
+  weak_spots: PerformanceWeakSpot[]
+  tested_cells: number
+  weak_spot_note: string
+}
+
 export interface MentorDashboard {
   user: User
   today_stats: DailyProgress | null
@@ -397,6 +1203,7 @@ export interface MentorDashboard {
   current_focus: CoachingFocus | null
   weekly_report: WeeklyReport | null
   progress_trend: DailyProgress[]
+  recurring_weaknesses: RecurringProblem[]
 }
 
 // ============== Auth API ==============
@@ -424,6 +1231,17 @@ export async function getMe(): Promise<User> {
   return response.data
 }
 
+// language: '' = automatisch über die Browser-Sprache
+export async function updateMe(settings: { language?: string }): Promise<User> {
+  const response = await api.patch('/auth/me', settings)
+  return response.data
+}
+
+export async function getLanguages(): Promise<LanguagesResponse> {
+  const response = await api.get('/languages')
+  return response.data
+}
+
 // ============== Mentor API ==============
 
 export async function getMentorDashboard(): Promise<MentorDashboard> {
@@ -475,4 +1293,9 @@ export async function getGoalTemplates(): Promise<GoalTemplate[]> {
   return response.data
 }
 
+export async function getPerformanceMatrix(days?: number): Promise<{ matrix: PerformanceMatrix; days: number }> {
+  const response = await api.get('/mentor/performance', { params: days ? { days } : undefined })
+  return response.data
+}
+
 export default api
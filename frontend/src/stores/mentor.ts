@@ -1,6 +1,6 @@
 import { defineStore } from 'pinia'
 import { ref } from 'vue'
-import type { MentorDashboard, Goal, GoalTemplate, DailyProgress, WeeklyReport, CoachingFocus } from '@/api/client'
+import type { MentorDashboard, Goal, GoalTemplate, DailyProgress, WeeklyReport, CoachingFocus, PerformanceMatrix } from '@/api/client'
 import {
   getMentorDashboard,
   getGoals,
@@ -10,6 +10,7 @@ import {
   getWeeklyReport as apiGetWeeklyReport,
   setCoachingFocus as apiSetCoachingFocus,
   getGoalTemplates,
+  getPerformanceMatrix,
 } from '@/api/client'
 
 export const useMentorStore = defineStore('mentor', () => {
@@ -18,6 +19,7 @@ export const useMentorStore = defineStore('mentor', () => {
   const goalTemplates = ref<GoalTemplate[]>([])
   const progressHistory = ref<DailyProgress[]>([])
   const weeklyReport = ref<WeeklyReport | null>(null)
+  const performance = ref<PerformanceMatrix | null>(null)
   const loading = ref(false)
   const error = ref<string | null>(null)
 
@@ -133,11 +135,21 @@ export const useMentorStore = defineStore('mentor', () => {
     }
   }
 
+  async function fetchPerformance(days?: number) {
+    try {
+      const data = await getPerformanceMatrix(days)
+      performance.value = data.matrix
+    } catch {
+      // Ignoriere Fehler, die Matrix ist optional
+    }
+  }
+
   function reset() {
     dashboard.value = null
     goals.value = []
     progressHistory.value = []
     weeklyReport.value = null
+    performance.value = null
     error.value = null
   }
 
@@ -147,6 +159,7 @@ export const useMentorStore = defineStore('mentor', () => {
     goalTemplates,
     progressHistory,
     weeklyReport,
+    performance,
     loading,
     error,
     fetchDashboard,
@@ -157,6 +170,7 @@ export const useMentorStore = defineStore('mentor', () => {
     fetchWeeklyReport,
     setCoachingFocus,
     fetchGoalTemplates,
+    fetchPerformance,
     reset,
   }
 })
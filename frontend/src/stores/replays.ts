@@ -91,10 +91,10 @@ export const useReplayStore = defineStore('replays', () => {
     }
   }
 
-  async function fetchStrategicAnalysis(replayId: number) {
+  async function fetchStrategicAnalysis(replayId: number, playerId?: number) {
     loadingStrategic.value = true
     try {
-      strategicAnalysis.value = await getStrategicAnalysis(replayId)
+      strategicAnalysis.value = await getStrategicAnalysis(replayId, playerId)
     } catch (e) {
       // Strategische Analyse ist optional, also kein Fehler
       strategicAnalysis.value = null